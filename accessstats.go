@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// With 'EnableAccessStatistics', PollGo! counts the page views and opened answer forms of each poll, so creators can see whether their link is opened at all.
+// Only the aggregated counts are stored. To count unique visitors, IP address and user agent are hashed with a random salt which is kept in memory and replaced every day.
+// So a visitor opening the answer form on two days is counted twice, and visitors can not be recognised across days or restarts.
+
+// accessFlushInterval is the time between two writes of the collected counts to the data safe.
+const accessFlushInterval = time.Minute
+
+// accessMaxVisitors is the maximum number of visitors remembered per day. Further visitors are not counted as unique.
+const accessMaxVisitors = 1000000
+
+type accessCounts struct {
+	views       int
+	opens       int
+	uniqueOpens int
+}
+
+var (
+	accessMutex    sync.Mutex
+	accessPending  = make(map[string]accessCounts)
+	accessVisitors = make(map[[sha256.Size]byte]bool)
+	accessSalt     []byte
+	accessDay      string
+)
+
+// accessBot returns whether the request was made by a crawler, which is not counted.
+func accessBot(r *http.Request) bool {
+	ua := strings.ToLower(r.UserAgent())
+	return ua == "" || strings.Contains(ua, "bot") || strings.Contains(ua, "crawler") || strings.Contains(ua, "spider")
+}
+
+// recordPollView counts a view of the poll stored under key.
+func recordPollView(r *http.Request, key string) {
+	if !config.EnableAccessStatistics || accessBot(r) {
+		return
+	}
+	accessMutex.Lock()
+	defer accessMutex.Unlock()
+	c := accessPending[key]
+	c.views++
+	accessPending[key] = c
+}
+
+// recordAnswerFormOpen counts an opened answer form of the poll stored under key.
+func recordAnswerFormOpen(r *http.Request, key string) {
+	if !config.EnableAccessStatistics || accessBot(r) {
+		return
+	}
+	accessMutex.Lock()
+	defer accessMutex.Unlock()
+
+	if day := time.Now().Format(time.DateOnly); day != accessDay || accessSalt == nil {
+		accessSalt = make([]byte, 32)
+		_, err := rand.Read(accessSalt)
+		if err != nil {
+			log.Printf("access statistics: can not create salt: %s", err.Error())
+			accessSalt = nil
+		}
+		accessVisitors = make(map[[sha256.Size]byte]bool)
+		accessDay = day
+	}
+
+	c := accessPending[key]
+	c.opens++
+	if accessSalt != nil && len(accessVisitors) < accessMaxVisitors {
+		h := sha256.New()
+		h.Write(accessSalt)
+		for _, s := range []string{key, GetRealIP(r), r.UserAgent()} {
+			h.Write([]byte(s))
+			h.Write([]byte{0})
+		}
+		var visitor [sha256.Size]byte
+		h.Sum(visitor[:0])
+		if !accessVisitors[visitor] {
+			accessVisitors[visitor] = true
+			c.uniqueOpens++
+		}
+	}
+	accessPending[key] = c
+}
+
+// flushAccessStatistics writes the collected counts to the data safe.
+func flushAccessStatistics() {
+	accessMutex.Lock()
+	pending := accessPending
+	accessPending = make(map[string]accessCounts)
+	accessMutex.Unlock()
+
+	for key, c := range pending {
+		err := safe.AddPollAccess(key, c.views, c.opens, c.uniqueOpens)
+		if err != nil {
+			log.Printf("access statistics (%s): can not save counts: %s", key, err.Error())
+		}
+	}
+}
+
+// accessWorker periodically writes the collected counts to the data safe. It never returns.
+func accessWorker() {
+	t := time.NewTicker(accessFlushInterval)
+	defer t.Stop()
+	for {
+		<-t.C
+		flushAccessStatistics()
+	}
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// aggregateQueueSize is the number of polls which can wait for the recomputation of their aggregate.
+// If the queue is full, the aggregate is recomputed on the next read.
+const aggregateQueueSize = 1000
+
+// pollAggregate is the precomputed aggregate of a poll as stored in the data safe.
+type pollAggregate struct {
+	Statistics   PollStatistics
+	Revision     string    // revision of the poll the aggregate was computed for
+	LastActivity time.Time // last activity of the poll the aggregate was computed for
+}
+
+var (
+	aggregateQueue        = make(chan string, aggregateQueueSize)
+	aggregatePending      = make(map[string]bool)
+	aggregatePendingMutex sync.Mutex
+)
+
+// markAggregateDirty schedules the recomputation of the aggregate of the poll stored under key.
+// Until it is recomputed, the aggregate is not used.
+func markAggregateDirty(key string) {
+	if !config.PrecomputeAggregates {
+		return
+	}
+	aggregatePendingMutex.Lock()
+	defer aggregatePendingMutex.Unlock()
+	if aggregatePending[key] {
+		return
+	}
+	select {
+	case aggregateQueue <- key:
+		aggregatePending[key] = true
+	default:
+		// Queue is full - the stored aggregate will be seen as outdated and is recomputed on read
+	}
+}
+
+// loadAggregate returns the stored aggregate of the poll if it is up to date.
+// The bool is false if no usable aggregate exists.
+func (p Poll) loadAggregate(key string) (PollStatistics, bool) {
+	aggregatePendingMutex.Lock()
+	pending := aggregatePending[key]
+	aggregatePendingMutex.Unlock()
+	if pending {
+		return PollStatistics{}, false
+	}
+
+	b, err := safe.GetAggregate(key)
+	if err != nil || b == nil {
+		return PollStatistics{}, false
+	}
+	var a pollAggregate
+	err = json.Unmarshal(b, &a)
+	if err != nil || a.Revision != p.Revision() {
+		return PollStatistics{}, false
+	}
+	last, err := safe.GetLastActivity(key)
+	if err != nil || !last.Equal(a.LastActivity) {
+		return PollStatistics{}, false
+	}
+	return a.Statistics, true
+}
+
+// updateAggregate computes the aggregate of the poll stored under key and saves it to the data safe.
+func updateAggregate(key string) error {
+	b, err := safe.GetPollConfig(key)
+	if err != nil {
+		return err
+	}
+	p, err := LoadPoll(b)
+	if err != nil {
+		return err
+	}
+	if !p.initialised || p.Deleted {
+		return safe.SaveAggregate(key, nil)
+	}
+
+	// The last activity is read first, so changes during computation lead to an outdated aggregate instead of a wrong one
+	last, err := safe.GetLastActivity(key)
+	if err != nil {
+		return err
+	}
+	s, err := p.computeStatistics(key)
+	if err != nil {
+		return err
+	}
+	a, err := json.Marshal(pollAggregate{Statistics: s, Revision: p.Revision(), LastActivity: last})
+	if err != nil {
+		return err
+	}
+	return safe.SaveAggregate(key, a)
+}
+
+// aggregateWorker recomputes the aggregates of all polls marked with markAggregateDirty. It never returns.
+func aggregateWorker() {
+	for key := range aggregateQueue {
+		aggregatePendingMutex.Lock()
+		delete(aggregatePending, key)
+		aggregatePendingMutex.Unlock()
+
+		err := updateAggregate(key)
+		if err != nil {
+			log.Printf("aggregate: can not update %s: %s", key, err.Error())
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// The answer form is either shown as a grid (one column per answer option) or in a compact layout (one row of answer chips per question).
+// The grid needs horizontal scrolling on small screens if there are many answer options, so phones get the compact layout unless the poll chooses a layout.
+// The layout is selected on the server, so it works without JavaScript.
+
+// Values of AnswerLayout.
+const (
+	answerLayoutGrid    = "grid"
+	answerLayoutCompact = "compact"
+)
+
+// parseAnswerLayout returns the AnswerLayout for the value of a form. Unknown values select the layout by the device of the visitor.
+func parseAnswerLayout(v string) string {
+	switch v {
+	case answerLayoutGrid, answerLayoutCompact:
+		return v
+	default:
+		return ""
+	}
+}
+
+// mobileClient returns whether the request was made by a phone or similar device.
+// The Sec-CH-UA-Mobile client hint is preferred, the User-Agent is only used for browsers not sending client hints.
+func mobileClient(r *http.Request) bool {
+	switch r.Header.Get("Sec-CH-UA-Mobile") {
+	case "?1":
+		return true
+	case "?0":
+		return false
+	}
+	return strings.Contains(r.UserAgent(), "Mobi")
+}
+
+// answerLayout returns the layout of the answer form for the request.
+// The 'layout' parameter (set by the link on the form) takes precedence over the layout of the poll.
+// If the layout is chosen by the device, the response headers are set accordingly.
+func (p Poll) answerLayout(rw http.ResponseWriter, r *http.Request) string {
+	if l := parseAnswerLayout(r.Form.Get("layout")); l != "" {
+		return l
+	}
+	if p.AnswerLayout != "" {
+		return p.AnswerLayout
+	}
+	rw.Header().Set("Accept-CH", "Sec-CH-UA-Mobile")
+	rw.Header().Add("Vary", "Sec-CH-UA-Mobile, User-Agent")
+	if mobileClient(r) {
+		return answerLayoutCompact
+	}
+	return answerLayoutGrid
+}
+
+// answerLayoutURL returns the query of the current answer form with the other layout selected.
+func answerLayoutURL(r *http.Request, compact bool) string {
+	q := r.URL.Query()
+	if compact {
+		q.Set("layout", answerLayoutGrid)
+	} else {
+		q.Set("layout", answerLayoutCompact)
+	}
+	return "?" + q.Encode()
+}
@@ -0,0 +1,618 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Top-Ranger/pollgo/helper"
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+// apiPollsPath is the path of the poll API relative to 'ServerPath'.
+const apiPollsPath = "/api/v1/polls/"
+
+// apiMaxBodyBytes is the maximum size of request bodies of the API.
+const apiMaxBodyBytes = 1000000 // 1 MB
+
+// APIError is returned by the API for all failed requests.
+type APIError struct {
+	Status int
+	Error  string
+}
+
+// APIAnswerOption represents a single answer option of a poll.
+type APIAnswerOption struct {
+	Text   string
+	Value  float64
+	Colour string
+}
+
+// APIPoll represents a poll as returned by the API.
+type APIPoll struct {
+	Key              string
+	Type             string
+	Description      string
+	Questions        []string
+	AnswerOptions    []APIAnswerOption
+	Deadline         *time.Time
+	Closed           bool
+	Anonymous        bool
+	UniqueNames      bool
+	DisableComments  bool
+	MultipleChoice   bool   // answers are a bitmask of the selected answer options (bit i set if option i is selected)
+	ResultVisibility string // "" if all answers are shown, "closed" if they are hidden until the poll is closed, "totals" if only aggregated results are shown
+	Capacity         []int  // maximum number of participants picking each question (0 for no limit), nil if no question is limited
+	FinalDate        *int   // index of the question chosen as final date
+	Revision         string
+}
+
+// APINewPoll is the request body for creating a poll.
+// The configuration uses the same format as the exported configuration of a poll.
+type APINewPoll struct {
+	Consent bool // must be true, the creator has to accept the privacy policy
+	Poll    Poll
+}
+
+// APIAnswer represents a single answer of a poll.
+type APIAnswer struct {
+	ID      string
+	Name    string
+	Comment string
+	Answers []int // index of the chosen answer option for each question, bitmask of the chosen answer options in multiple choice polls
+}
+
+// APIVote is the request body for submitting or changing an answer.
+type APIVote struct {
+	Name      string
+	Comment   string
+	Answers   []int  // index of the chosen answer option for each question, bitmask of the chosen answer options in multiple choice polls
+	Consent   bool   // must be true, the participant has to accept the privacy policy
+	Revision  string // optional, the answer is rejected if the poll changed since
+	EditToken string // only used when changing an answer
+}
+
+// APIVoteResult is returned after an answer was saved.
+type APIVoteResult struct {
+	ID        string
+	EditToken string // required to change the answer later, only returned once
+}
+
+func writeAPIJSON(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	err := json.NewEncoder(rw).Encode(v)
+	if err != nil {
+		log.Printf("api: can not write response: %s", err.Error())
+	}
+}
+
+func writeAPIError(rw http.ResponseWriter, status int, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	writeAPIJSON(rw, status, APIError{Status: status, Error: message})
+}
+
+func writeAPIMethodNotAllowed(rw http.ResponseWriter, allowed ...string) {
+	rw.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeAPIError(rw, http.StatusMethodNotAllowed, "")
+}
+
+// apiAcceptsJSON returns whether the client accepts JSON responses. Clients without 'Accept' header accept everything.
+func apiAcceptsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+			continue
+		}
+		if mediaType == "application/json" || mediaType == "application/*" || mediaType == "*/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// readAPIBody decodes the JSON body of the request into v.
+// It returns false if the request must not be processed further. In that case, the response has already been written.
+func readAPIBody(rw http.ResponseWriter, r *http.Request, v interface{}) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		writeAPIError(rw, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return false
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(rw, r.Body, apiMaxBodyBytes))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(v)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeAPIError(rw, http.StatusRequestEntityTooLarge, "")
+			return false
+		}
+		writeAPIError(rw, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// apiAuthenticate checks the credentials sent through HTTP basic authentication.
+// It returns the authenticated user (empty if authentication is disabled) and false if the request must not be processed further.
+// In that case, the response has already been written.
+func apiAuthenticate(rw http.ResponseWriter, r *http.Request) (string, bool) {
+	if !config.AuthenticationEnabled {
+		return "", true
+	}
+	return apiAuthenticateWith(authenticater, rw, r)
+}
+
+// apiAuthenticateParticipant checks the credentials of participants sent through HTTP basic authentication if 'ParticipantAuthenticater' is set.
+// It returns false if the request must not be processed further. In that case, the response has already been written.
+func apiAuthenticateParticipant(rw http.ResponseWriter, r *http.Request) bool {
+	if participantAuthenticater == nil {
+		return true
+	}
+	_, ok := apiAuthenticateWith(participantAuthenticater, rw, r)
+	return ok
+}
+
+// apiAuthenticateWith checks the credentials sent through HTTP basic authentication with the given authenticater.
+func apiAuthenticateWith(a registry.Authenticater, rw http.ResponseWriter, r *http.Request) (string, bool) {
+	user, pw, ok := r.BasicAuth()
+	if !ok || user == "" || pw == "" {
+		rw.Header().Set("WWW-Authenticate", `Basic realm="pollgo", charset="UTF-8"`)
+		writeAPIError(rw, http.StatusUnauthorized, "")
+		return "", false
+	}
+	correct, err := authenticateWith(a, r, user, pw)
+	if errors.Is(err, ErrLoginLocked) {
+		rw.Header().Set("Retry-After", strconv.Itoa(config.LoginLockoutMinutes*60))
+		writeAPIError(rw, http.StatusTooManyRequests, GetDefaultTranslation().LoginLocked)
+		return "", false
+	}
+	if err != nil {
+		writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+		return "", false
+	}
+	if !correct {
+		if config.LogFailedLogin {
+			log.Printf("Failed authentication from %s", GetRealIP(r))
+		}
+		rw.Header().Set("WWW-Authenticate", `Basic realm="pollgo", charset="UTF-8"`)
+		writeAPIError(rw, http.StatusUnauthorized, "")
+		return "", false
+	}
+	return user, true
+}
+
+// apiPoll converts the poll into its API representation.
+func (p Poll) apiPoll(key string) APIPoll {
+	a := APIPoll{
+		Key:              key,
+		Type:             p.Type,
+		Description:      p.Description,
+		Questions:        p.Questions,
+		AnswerOptions:    make([]APIAnswerOption, len(p.AnswerOption)),
+		Closed:           p.Closed(),
+		Anonymous:        p.Anonymous,
+		UniqueNames:      p.UniqueNames,
+		DisableComments:  p.DisableComments,
+		MultipleChoice:   p.MultipleChoice,
+		ResultVisibility: p.ResultVisibility,
+		Revision:         p.Revision(),
+	}
+	if p.hasCapacity() {
+		a.Capacity = p.Capacity
+	}
+	for i := range p.AnswerOption {
+		v, _ := strconv.ParseFloat(p.AnswerOption[i][1], 64)
+		a.AnswerOptions[i] = APIAnswerOption{Text: p.AnswerOption[i][0], Value: v, Colour: p.AnswerOption[i][2]}
+	}
+	if !p.Deadline.IsZero() {
+		d := p.Deadline
+		a.Deadline = &d
+	}
+	if p.FinalDate > 0 {
+		f := p.FinalDate - 1
+		a.FinalDate = &f
+	}
+	return a
+}
+
+// apiHandle serves the poll API:
+//
+//	/api/v1/polls/{key}                GET, PUT (create), DELETE
+//	/api/v1/polls/{key}/answers        GET, POST (vote)
+//	/api/v1/polls/{key}/answers/{id}   GET, PUT (change vote)
+func apiHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	if !checkAPIToken(rw, r) {
+		return
+	}
+	if !apiAcceptsJSON(r) {
+		rw.WriteHeader(http.StatusNotAcceptable)
+		rw.Write([]byte("406 Not Acceptable"))
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, strings.Join([]string{config.ServerPath, apiPollsPath}, "")), "/")
+	if parts[0] == "" || len(parts) > 3 || (len(parts) > 1 && parts[1] != "answers") || (len(parts) == 3 && parts[2] == "") {
+		writeAPIError(rw, http.StatusNotFound, "")
+		return
+	}
+	// Keys contain the server path, same as for the HTML handlers
+	key := strings.TrimLeft(strings.Join([]string{config.ServerPath, "/", parts[0]}, ""), "/")
+
+	c, err := safe.GetPollConfig(key)
+	if err != nil {
+		writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+		return
+	}
+	p, err := LoadPoll(c)
+	if err != nil {
+		writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+		return
+	}
+
+	if len(parts) == 1 && r.Method == http.MethodPut {
+		apiCreatePoll(rw, r, key, p)
+		return
+	}
+
+	tl := GetDefaultTranslation()
+	switch {
+	case !p.initialised:
+		writeAPIError(rw, http.StatusNotFound, "")
+		return
+	case p.Deleted:
+		writeAPIError(rw, http.StatusGone, tl.PollIsDeleted)
+		return
+	case p.Hidden:
+		writeAPIError(rw, http.StatusForbidden, tl.PollHidden)
+		return
+	}
+
+	switch len(parts) {
+	case 1:
+		switch r.Method {
+		case http.MethodGet:
+			writeAPIJSON(rw, http.StatusOK, p.apiPoll(key))
+		case http.MethodDelete:
+			p.apiDeletePoll(rw, r, key)
+		default:
+			writeAPIMethodNotAllowed(rw, http.MethodGet, http.MethodPut, http.MethodDelete)
+		}
+	case 2:
+		switch r.Method {
+		case http.MethodGet:
+			p.apiListAnswers(rw, key, "")
+		case http.MethodPost:
+			p.apiVote(rw, r, key, "")
+		default:
+			writeAPIMethodNotAllowed(rw, http.MethodGet, http.MethodPost)
+		}
+	case 3:
+		switch r.Method {
+		case http.MethodGet:
+			p.apiListAnswers(rw, key, parts[2])
+		case http.MethodPut:
+			p.apiVote(rw, r, key, parts[2])
+		default:
+			writeAPIMethodNotAllowed(rw, http.MethodGet, http.MethodPut)
+		}
+	}
+}
+
+// apiCreatePoll creates a new poll from the configuration in the request body.
+func apiCreatePoll(rw http.ResponseWriter, r *http.Request, key string, existing Poll) {
+	tl := GetDefaultTranslation()
+	if existing.initialised {
+		writeAPIError(rw, http.StatusConflict, "poll already exists")
+		return
+	}
+
+	user, ok := apiAuthenticate(rw, r)
+	if !ok {
+		return
+	}
+	if user != "" {
+		exceeded, err := pollQuotaExceeded(user)
+		if err != nil {
+			writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+			return
+		}
+		if exceeded {
+			writeAPIError(rw, http.StatusForbidden, fmt.Sprintf(tl.PollQuotaExceeded, config.MaxPollsPerCreator))
+			return
+		}
+	}
+
+	var n APINewPoll
+	if !readAPIBody(rw, r, &n) {
+		return
+	}
+	if !n.Consent {
+		writeAPIError(rw, http.StatusBadRequest, "Consent must be true")
+		return
+	}
+	if instanceFull(true) {
+		writeAPIError(rw, http.StatusInsufficientStorage, tl.InstanceFull)
+		return
+	}
+
+	// Only the configuration is taken over, the state is always the one of a new poll
+	p := n.Poll
+	p.Deleted = false
+	p.Hidden = false
+	p.Locked = false
+	p.CreatorClosed = false
+	p.FinalDate = 0
+	if p.FollowUp != nil {
+		p.FollowUp.Created = ""
+	}
+	if len(p.Questions) > config.MaxNumberQuestions || len(p.AnswerOption) > config.MaxNumberQuestions {
+		writeAPIError(rw, http.StatusBadRequest, tl.PollToLargeError)
+		return
+	}
+	if !VerifyPollConfig(p) {
+		writeAPIError(rw, http.StatusBadRequest, "invalid poll configuration")
+		return
+	}
+	if !p.filterPollTexts() {
+		writeAPIError(rw, http.StatusBadRequest, tl.ContentFiltered)
+		return
+	}
+	p.initialised = true
+
+	err := p.saveNewPoll(key, user)
+	if err != nil {
+		writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+		return
+	}
+	rw.Header().Set("Location", strings.Join([]string{config.ServerPath, apiPollsPath, strings.TrimPrefix(strings.TrimPrefix(key, strings.TrimLeft(config.ServerPath, "/")), "/")}, ""))
+	writeAPIJSON(rw, http.StatusCreated, p.apiPoll(key))
+}
+
+// apiDeletePoll deletes the poll. If 'OnlyCreatorCanDelete' is set, only the creator and co-organizers can delete it.
+func (p Poll) apiDeletePoll(rw http.ResponseWriter, r *http.Request, key string) {
+	user, ok := apiAuthenticate(rw, r)
+	if !ok {
+		return
+	}
+	if config.AuthenticationEnabled && config.OnlyCreatorCanDelete {
+		manager, err := isPollManager(key, user)
+		if err != nil {
+			writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+			return
+		}
+		if !manager {
+			writeAPIError(rw, http.StatusForbidden, GetDefaultTranslation().UserNotCreator)
+			return
+		}
+	}
+
+	reportLargePollDeleted(r, key)
+
+	err := p.deletePoll(key)
+	if err != nil {
+		writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// apiListAnswers writes all answers of the poll. If answerID is not empty, only that answer is written.
+// If the answers of the poll are hidden, only single answers can be requested, since the answer ID is only known to the participant.
+func (p Poll) apiListAnswers(rw http.ResponseWriter, key, answerID string) {
+	if answerID == "" && p.answersHidden() {
+		tl := GetDefaultTranslation()
+		message := tl.AnswersHiddenTotals
+		if p.resultsHidden() {
+			message = tl.ResultsHiddenUntilClosed
+		}
+		writeAPIError(rw, http.StatusForbidden, message)
+		return
+	}
+	results, names, comments, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+		return
+	}
+	answers := make([]APIAnswer, 0, len(ids))
+	for i := range ids {
+		if answerID != "" && ids[i] != answerID {
+			continue
+		}
+		answers = append(answers, APIAnswer{ID: ids[i], Name: names[i], Comment: comments[i], Answers: results[i]})
+	}
+	if answerID == "" {
+		writeAPIJSON(rw, http.StatusOK, answers)
+		return
+	}
+	if len(answers) == 0 {
+		writeAPIError(rw, http.StatusNotFound, "")
+		return
+	}
+	writeAPIJSON(rw, http.StatusOK, answers[0])
+}
+
+// apiVote saves the answer in the request body. If answerID is not empty, the existing answer is changed.
+func (p Poll) apiVote(rw http.ResponseWriter, r *http.Request, key, answerID string) {
+	tl := GetDefaultTranslation()
+	if p.Closed() {
+		writeAPIError(rw, http.StatusForbidden, p.closedMessage(tl))
+		return
+	}
+	if !apiAuthenticateParticipant(rw, r) {
+		return
+	}
+
+	var v APIVote
+	if !readAPIBody(rw, r, &v) {
+		return
+	}
+	if !v.Consent {
+		writeAPIError(rw, http.StatusBadRequest, "Consent must be true")
+		return
+	}
+	if instanceFull(false) {
+		writeAPIError(rw, http.StatusInsufficientStorage, tl.InstanceFull)
+		return
+	}
+	if v.Revision != "" && v.Revision != p.Revision() {
+		writeAPIError(rw, http.StatusConflict, tl.PollChanged)
+		return
+	}
+	if len(v.Answers) != len(p.Questions) {
+		writeAPIError(rw, http.StatusBadRequest, fmt.Sprintf("exactly %d answers required", len(p.Questions)))
+		return
+	}
+	for _, a := range v.Answers {
+		if !p.validAnswer(a) {
+			highest := len(p.AnswerOption) - 1
+			if p.MultipleChoice {
+				highest = 1<<len(p.AnswerOption) - 1
+			}
+			writeAPIError(rw, http.StatusBadRequest, fmt.Sprintf("answers must be between 0 and %d", highest))
+			return
+		}
+	}
+
+	if p.DisableComments {
+		v.Comment = ""
+	}
+	if p.Anonymous {
+		v.Name = ""
+	}
+	if !filterContent(&v.Name, &v.Comment) {
+		writeAPIError(rw, http.StatusBadRequest, tl.ContentFiltered)
+		return
+	}
+	if p.UniqueNames {
+		taken, err := nameTaken(key, v.Name, answerID)
+		if err != nil {
+			writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+			return
+		}
+		if taken {
+			writeAPIError(rw, http.StatusConflict, tl.NameAlreadyTaken)
+			return
+		}
+	}
+
+	unlockCapacity := func() {}
+	if p.hasCapacity() {
+		unlockCapacity = lockCapacity(key)
+		defer unlockCapacity()
+		exceeded, err := p.checkCapacity(key, answerID, v.Answers)
+		if err != nil {
+			writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+			return
+		}
+		if len(exceeded) != 0 {
+			writeAPIError(rw, http.StatusConflict, capacityExceededMessage(tl, exceeded))
+			return
+		}
+	}
+
+	// Hold the lock until the answer is saved, so the poll can not be edited in between
+	pollStructureMutex.RLock()
+	defer pollStructureMutex.RUnlock()
+	unchanged, err := structureUnchanged(key, p.Revision())
+	if err != nil {
+		writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+		return
+	}
+	if !unchanged {
+		writeAPIError(rw, http.StatusConflict, tl.PollChanged)
+		return
+	}
+
+	status := http.StatusCreated
+	var change string
+	if answerID == "" {
+		change = helper.GetRandomString()
+		id, err := safe.SavePollResult(key, v.Name, v.Comment, v.Answers, change)
+		if errors.Is(err, registry.ErrPollNotAvailable) {
+			writeAPIError(rw, http.StatusGone, tl.PollIsDeleted)
+			return
+		}
+		if err != nil {
+			writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+			return
+		}
+		answerID = id
+	} else {
+		_, _, _, ids, err := safe.GetPollResult(key)
+		if err != nil {
+			writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+			return
+		}
+		if !slices.Contains(ids, answerID) {
+			writeAPIError(rw, http.StatusNotFound, "")
+			return
+		}
+		change, err = safe.GetChange(key, answerID)
+		if err != nil {
+			writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+			return
+		}
+		if change == "" || subtle.ConstantTimeCompare([]byte(change), []byte(v.EditToken)) == 0 {
+			if config.LogFailedLogin {
+				log.Printf("Failed authentication from %s", GetRealIP(r))
+			}
+			writeAPIError(rw, http.StatusForbidden, "invalid EditToken")
+			return
+		}
+		err = safe.OverwritePollResult(key, answerID, v.Name, v.Comment, v.Answers, change)
+		if errors.Is(err, registry.ErrPollNotAvailable) {
+			writeAPIError(rw, http.StatusGone, tl.PollIsDeleted)
+			return
+		}
+		if err != nil {
+			writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+			return
+		}
+		status = http.StatusOK
+	}
+	// Parallel answers see the saved answer, so the capacity does not need to be locked any more
+	unlockCapacity()
+
+	err = safe.SaveConsent(key, answerID, time.Now(), p.consentVersion())
+	if err != nil {
+		writeAPIError(rw, http.StatusInternalServerError, internalErrorText(err))
+		return
+	}
+	hookAnswerSaved(key, answerID, status == http.StatusOK)
+	writeAPIJSON(rw, status, APIVoteResult{ID: answerID, EditToken: change})
+}
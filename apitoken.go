@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// APIToken represents a token which can be used by integrations to access PollGo!.
+type APIToken struct {
+	// Name of the token, used for logging and metrics.
+	Name string
+
+	// The token itself. Integrations send it as 'Authorization: Bearer TOKEN'.
+	Token string
+
+	// Number of requests allowed per minute.
+	// Value 0 represents no limit.
+	RequestsPerMinute int
+}
+
+type apiTokenState struct {
+	token APIToken
+	limit *rate.Limiter
+}
+
+var apiTokens []apiTokenState
+
+func initialiseAPITokens() error {
+	apiTokens = make([]apiTokenState, 0, len(config.APITokens))
+	known := make(map[string]bool)
+	for i := range config.APITokens {
+		t := config.APITokens[i]
+		if t.Name == "" || t.Token == "" {
+			return fmt.Errorf("api token %d: Name and Token must not be empty", i)
+		}
+		if known[t.Name] {
+			return fmt.Errorf("api token %s found more than once", t.Name)
+		}
+		known[t.Name] = true
+		s := apiTokenState{token: t}
+		if t.RequestsPerMinute <= 0 {
+			s.limit = rate.NewLimiter(rate.Inf, 0)
+		} else {
+			s.limit = rate.NewLimiter(rate.Limit(float64(t.RequestsPerMinute)/60.0), t.RequestsPerMinute)
+		}
+		apiTokens = append(apiTokens, s)
+	}
+	return nil
+}
+
+// checkAPIToken checks the API token of a request (if any) and enforces the quota of the token.
+// It returns false if the request must not be processed further. In that case, the response has already been written.
+// Requests without a token are always allowed.
+func checkAPIToken(rw http.ResponseWriter, r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return true
+	}
+	token := []byte(strings.TrimPrefix(header, "Bearer "))
+
+	var state *apiTokenState
+	for i := range apiTokens {
+		if subtle.ConstantTimeCompare(token, []byte(apiTokens[i].token.Token)) == 1 {
+			state = &apiTokens[i]
+		}
+	}
+
+	if state == nil {
+		if config.LogFailedLogin {
+			log.Printf("Unknown API token from %s", GetRealIP(r))
+		}
+		MetricsAdd("pollgo_api_unknown_token_total", "Number of requests with an unknown API token.", "", 1)
+		rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		rw.WriteHeader(http.StatusUnauthorized)
+		rw.Write([]byte("401 Unauthorized"))
+		return false
+	}
+
+	label := metricLabel("token", state.token.Name)
+	if !state.limit.Allow() {
+		MetricsAdd("pollgo_api_token_limited_total", "Number of requests rejected because the quota of the API token was exceeded.", label, 1)
+		rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		rw.Header().Set("Retry-After", "60")
+		rw.WriteHeader(http.StatusTooManyRequests)
+		rw.Write([]byte("429 Too Many Requests"))
+		return false
+	}
+	MetricsAdd("pollgo_api_token_requests_total", "Number of requests per API token.", label, 1)
+	return true
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+type cachedAsset struct {
+	data        []byte
+	etag        string
+	hashedName  string
+	contentType string
+}
+
+// assets holds all static files, indexed by their path (e.g. "js/pollgo.1.js").
+// It must only be written to during server initialisation.
+var assets = make(map[string]cachedAsset)
+
+// hashedAssetNames maps the content-hashed names of all assets back to their path.
+var hashedAssetNames = make(map[string]string)
+
+// assetURL returns the URL of the asset at path.
+// If HashedAssetNames is enabled, the URL contains the content hash of the asset so it can be cached forever.
+func assetURL(p string) string {
+	if config.HashedAssetNames {
+		if a, ok := assets[p]; ok {
+			p = a.hashedName
+		}
+	}
+	return strings.Join([]string{config.ServerPath, "/", p}, "")
+}
+
+func staticContentType(p string) string {
+	switch {
+	case strings.HasSuffix(p, ".svg"):
+		return "image/svg+xml"
+	case strings.HasSuffix(p, ".ttf"):
+		return "application/x-font-truetype"
+	case strings.HasSuffix(p, ".js"):
+		return "application/javascript"
+	case strings.HasSuffix(p, ".css"):
+		return "text/css"
+	case strings.HasSuffix(p, ".ico"):
+		return "image/vnd.microsoft.icon"
+	default:
+		return "text/plain"
+	}
+}
+
+func addAsset(p string, data []byte) {
+	h := sha256.Sum256(data)
+	hash := hex.EncodeToString(h[:8])
+	ext := path.Ext(p)
+	a := cachedAsset{
+		data:        data,
+		etag:        strings.Join([]string{"\"", hash, "\""}, ""),
+		hashedName:  strings.Join([]string{strings.TrimSuffix(p, ext), ".", hash, ext}, ""),
+		contentType: staticContentType(p),
+	}
+	assets[p] = a
+	hashedAssetNames[a.hashedName] = p
+}
+
+// loadAssets reads all embedded files and renders the css templates.
+// It must be called after the configuration is loaded.
+func loadAssets() error {
+	err := fs.WalkDir(cachedFiles, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(p, "css/") {
+			return nil
+		}
+		b, err := cachedFiles.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		addAsset(p, b)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// css files are templates which can reference other assets, so they have to be rendered last
+	for _, t := range cssTemplates.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		buf := bytes.Buffer{}
+		err := t.Execute(&buf, struct{ ServerPath, AccentColour string }{config.ServerPath, config.AccentColour})
+		if err != nil {
+			return err
+		}
+		addAsset(strings.Join([]string{"css/", t.Name()}, ""), buf.Bytes())
+	}
+	return nil
+}
+
+// etagMatches returns whether the request contains a If-None-Match header matching etag.
+func etagMatches(r *http.Request, etag string) bool {
+	v, ok := r.Header["If-None-Match"]
+	if !ok {
+		return false
+	}
+	etagCompare := strings.TrimSuffix(etag, "\"")
+	etagCompareApache := strings.Join([]string{etagCompare, "-"}, "")       // Dirty hack for apache2, who appends -gzip inside the quotes if the file is compressed, thus preventing If-None-Match matching the ETag
+	etagCompareCaddy := strings.Join([]string{"W/", etagCompare, "\""}, "") // Dirty hack for caddy, who appends W/ before the quotes if the file is compressed, thus preventing If-None-Match matching the ETag
+	for i := range v {
+		if v[i] == etag || v[i] == etagCompareCaddy || strings.HasPrefix(v[i], etagCompareApache) {
+			return true
+		}
+	}
+	return false
+}
+
+func staticHandle(rw http.ResponseWriter, r *http.Request) {
+	p := r.URL.Path
+	p = strings.TrimPrefix(p, config.ServerPath)
+	p = strings.TrimPrefix(p, "/")
+
+	immutable := false
+	if original, ok := hashedAssetNames[p]; ok {
+		p = original
+		immutable = true
+	}
+
+	a, ok := assets[p]
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if etagMatches(r, a.etag) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	rw.Header().Set("ETag", a.etag)
+	if immutable {
+		rw.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		rw.Header().Set("Cache-Control", "public, max-age=43200")
+	}
+	rw.Header().Set("Content-Type", a.contentType)
+	rw.Write(a.data)
+}
@@ -0,0 +1,298 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authenticater
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/pollgo/registry"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Parameters used for new argon2id hashes.
+const (
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+// htpasswdReloadInterval is the minimum time between two checks whether a watched user file changed.
+const htpasswdReloadInterval = 5 * time.Second
+
+// apr1Magic is the prefix of Apache MD5 hashes (htpasswd -m).
+const apr1Magic = "$apr1$"
+
+// apr1Alphabet is the alphabet used to encode Apache MD5 hashes.
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Htpasswd is an Authenticater which takes a htpasswd style file as a configuration.
+// Each line contains "user:hash". Empty lines and lines starting with '#' are ignored.
+// Supported hashes are argon2id (in the PHC string format "$argon2id$v=19$m=...,t=...,p=...$salt$hash"), bcrypt and Apache MD5 ("$apr1$"),
+// so files created with the Apache htpasswd tool can be used as they are.
+// Users can be managed with "pollgo user add/remove/passwd".
+// If WatchFile is called, changes of the file are loaded without restarting the server.
+type Htpasswd struct {
+	users map[string]string
+	l     sync.RWMutex
+
+	file    string
+	modTime time.Time
+	size    int64
+	checked time.Time
+	watchL  sync.Mutex
+}
+
+func init() {
+	err := registry.RegisterAuthenticater(&Htpasswd{}, "Htpasswd")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// LoadConfig loads the configuration.
+// It can be called again to replace the users, e.g. after users were added. It is safe for parallel usage.
+func (h *Htpasswd) LoadConfig(b []byte) error {
+	users, err := ParseHtpasswd(b)
+	if err != nil {
+		return err
+	}
+	h.l.Lock()
+	h.users = users
+	h.l.Unlock()
+	return nil
+}
+
+// WatchFile reloads the users from path when the file changes. path should be the file the configuration was loaded from.
+// Changes are detected by modification time and size, checked at most every few seconds while users log in.
+func (h *Htpasswd) WatchFile(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	h.watchL.Lock()
+	defer h.watchL.Unlock()
+	h.file = path
+	h.modTime = fi.ModTime()
+	h.size = fi.Size()
+	h.checked = time.Now()
+	return nil
+}
+
+// reloadIfChanged loads the watched file again if it changed since the last check.
+// If the file can not be loaded (e.g. while it is written), the previous users are kept.
+func (h *Htpasswd) reloadIfChanged() {
+	h.watchL.Lock()
+	defer h.watchL.Unlock()
+	if h.file == "" || time.Since(h.checked) < htpasswdReloadInterval {
+		return
+	}
+	h.checked = time.Now()
+	fi, err := os.Stat(h.file)
+	if err != nil {
+		log.Printf("htpasswd: can not check %s: %s", h.file, err.Error())
+		return
+	}
+	if fi.ModTime().Equal(h.modTime) && fi.Size() == h.size {
+		return
+	}
+	b, err := os.ReadFile(h.file)
+	if err == nil {
+		err = h.LoadConfig(b)
+	}
+	if err != nil {
+		log.Printf("htpasswd: can not reload %s: %s", h.file, err.Error())
+		return
+	}
+	h.modTime = fi.ModTime()
+	h.size = fi.Size()
+	log.Printf("htpasswd: reloaded %s", h.file)
+}
+
+// Authenticate validates a user/password configuration. It is safe for parallel usage.
+func (h *Htpasswd) Authenticate(user, password string) (bool, error) {
+	h.reloadIfChanged()
+	h.l.RLock()
+	hash, ok := h.users[user]
+	h.l.RUnlock()
+	if !ok {
+		return false, nil
+	}
+	return CheckPasswordHash(hash, password)
+}
+
+// ParseHtpasswd parses a htpasswd style file and returns a map of users to their hashes.
+func ParseHtpasswd(b []byte) (map[string]string, error) {
+	users := make(map[string]string)
+	s := bufio.NewScanner(bytes.NewReader(b))
+	line := 0
+	for s.Scan() {
+		line++
+		l := strings.TrimSpace(s.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		user, hash, ok := strings.Cut(l, ":")
+		if !ok || user == "" || hash == "" {
+			return nil, fmt.Errorf("line %d must have the format user:hash", line)
+		}
+		if _, ok := users[user]; ok {
+			return nil, fmt.Errorf("user %s found more than once", user)
+		}
+		users[user] = hash
+	}
+	return users, s.Err()
+}
+
+// HashPassword returns an argon2id hash of the password in the PHC string format.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argon2Memory, argon2Time, argon2Threads, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// CheckPasswordHash checks whether the password matches an argon2id, bcrypt or Apache MD5 hash.
+// An error is returned if the hash can not be parsed.
+func CheckPasswordHash(hash, password string) (bool, error) {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		parts := strings.Split(hash, "$")
+		if len(parts) != 6 {
+			return false, fmt.Errorf("invalid argon2id hash")
+		}
+		var version int
+		_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+		if err != nil {
+			return false, fmt.Errorf("invalid argon2id hash: %w", err)
+		}
+		if version != argon2.Version {
+			return false, fmt.Errorf("unsupported argon2id version %d", version)
+		}
+		var memory, time uint32
+		var threads uint8
+		_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads)
+		if err != nil {
+			return false, fmt.Errorf("invalid argon2id hash: %w", err)
+		}
+		salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+		if err != nil {
+			return false, fmt.Errorf("invalid argon2id hash: %w", err)
+		}
+		key, err := base64.RawStdEncoding.DecodeString(parts[5])
+		if err != nil {
+			return false, fmt.Errorf("invalid argon2id hash: %w", err)
+		}
+		if len(salt) == 0 || len(key) == 0 || time == 0 || threads == 0 {
+			return false, fmt.Errorf("invalid argon2id hash")
+		}
+		other := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1, nil
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil, nil
+	case strings.HasPrefix(hash, apr1Magic):
+		salt, _, ok := strings.Cut(strings.TrimPrefix(hash, apr1Magic), "$")
+		if !ok || salt == "" {
+			return false, fmt.Errorf("invalid apr1 hash")
+		}
+		other := apr1Hash([]byte(password), []byte(salt))
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(other)) == 1, nil
+	}
+	return false, fmt.Errorf("unsupported hash format")
+}
+
+// apr1Hash returns the Apache MD5 hash ("$apr1$salt$hash") of the password.
+// The algorithm is the MD5 based crypt of FreeBSD with a different prefix. It is only supported for existing files, new hashes use argon2id.
+func apr1Hash(password, salt []byte) string {
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+
+	alternate := md5.New()
+	alternate.Write(password)
+	alternate.Write(salt)
+	alternate.Write(password)
+	alternateSum := alternate.Sum(nil)
+
+	h := md5.New()
+	h.Write(password)
+	h.Write([]byte(apr1Magic))
+	h.Write(salt)
+	for i := len(password); i > 0; i -= md5.Size {
+		h.Write(alternateSum[:min(i, md5.Size)])
+	}
+	for i := len(password); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			h.Write([]byte{0})
+		} else {
+			h.Write(password[:1])
+		}
+	}
+	sum := h.Sum(nil)
+
+	for i := 0; i < 1000; i++ {
+		h := md5.New()
+		if i&1 != 0 {
+			h.Write(password)
+		} else {
+			h.Write(sum)
+		}
+		if i%3 != 0 {
+			h.Write(salt)
+		}
+		if i%7 != 0 {
+			h.Write(password)
+		}
+		if i&1 != 0 {
+			h.Write(sum)
+		} else {
+			h.Write(password)
+		}
+		sum = h.Sum(nil)
+	}
+
+	var b strings.Builder
+	b.WriteString(apr1Magic)
+	b.Write(salt)
+	b.WriteByte('$')
+	encode := func(v uint, n int) {
+		for ; n > 0; n-- {
+			b.WriteByte(apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	for _, i := range [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		encode(uint(sum[i[0]])<<16|uint(sum[i[1]])<<8|uint(sum[i[2]]), 4)
+	}
+	encode(uint(sum[11]), 2)
+	return b.String()
+}
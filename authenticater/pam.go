@@ -0,0 +1,189 @@
+//go:build pam
+
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authenticater
+
+/*
+#cgo LDFLAGS: -lpam
+#include <security/pam_appl.h>
+#include <stdlib.h>
+#include <string.h>
+
+// pollgo_pam_conv answers password prompts with the password given as appdata.
+// Other prompts are refused, the user name is already given to pam_start.
+static int pollgo_pam_conv(int n, const struct pam_message **msg, struct pam_response **resp, void *appdata) {
+	if (n <= 0 || n > PAM_MAX_NUM_MSG) {
+		return PAM_CONV_ERR;
+	}
+	struct pam_response *r = calloc(n, sizeof(struct pam_response));
+	if (r == NULL) {
+		return PAM_BUF_ERR;
+	}
+	for (int i = 0; i < n; i++) {
+		switch (msg[i]->msg_style) {
+		case PAM_PROMPT_ECHO_OFF:
+			r[i].resp = strdup((const char *)appdata);
+			if (r[i].resp == NULL) {
+				goto fail;
+			}
+			break;
+		case PAM_ERROR_MSG:
+		case PAM_TEXT_INFO:
+			break;
+		default:
+			goto fail;
+		}
+	}
+	*resp = r;
+	return PAM_SUCCESS;
+
+fail:
+	for (int i = 0; i < n; i++) {
+		if (r[i].resp != NULL) {
+			memset(r[i].resp, 0, strlen(r[i].resp));
+			free(r[i].resp);
+		}
+	}
+	free(r);
+	return PAM_CONV_ERR;
+}
+
+// pollgo_pam_authenticate authenticates the user through the PAM service and returns the PAM result.
+static int pollgo_pam_authenticate(const char *service, const char *user, char *password, int account) {
+	struct pam_conv conv = { pollgo_pam_conv, password };
+	pam_handle_t *h = NULL;
+	int ret = pam_start(service, user, &conv, &h);
+	if (ret != PAM_SUCCESS) {
+		return ret;
+	}
+	ret = pam_authenticate(h, PAM_SILENT | PAM_DISALLOW_NULL_AUTHTOK);
+	if (ret == PAM_SUCCESS && account) {
+		ret = pam_acct_mgmt(h, PAM_SILENT | PAM_DISALLOW_NULL_AUTHTOK);
+	}
+	pam_end(h, ret);
+	return ret;
+}
+
+// pollgo_pam_clear overwrites the password before it is freed.
+static void pollgo_pam_clear(char *s) {
+	memset(s, 0, strlen(s));
+	free(s);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unsafe"
+
+	"golang.org/x/time/rate"
+
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+func init() {
+	err := registry.RegisterAuthenticater(&PAM{}, "PAM")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// PAM is an authenticator for local system accounts using Pluggable Authentication Modules.
+// It takes a JSON object as configuration (see pam.json). Only available on systems with PAM (e.g. Linux) and when build with cgo.
+// Depending on the PAM modules, the server needs additional rights, e.g. pam_unix can only verify other users if the server can read /etc/shadow.
+type PAM struct {
+	// Name of the PAM service, i.e. the file in /etc/pam.d/ used for authentication.
+	Service string
+
+	// Whether to skip the account check (e.g. expired or locked accounts) after the password was verified.
+	SkipAccountCheck bool
+
+	// Maximum time in seconds to wait for the rate limit. Defaults to 5.
+	TimeLimit int
+
+	// Number of requests allowed per second.
+	// Value 0 represents no rate limit.
+	RateLimit int
+
+	limit rate.Limiter
+}
+
+// LoadConfig loads the PAM configuration as a JSON.
+func (p *PAM) LoadConfig(b []byte) error {
+	err := json.Unmarshal(b, p)
+	if err != nil {
+		return err
+	}
+	if p.Service == "" {
+		return fmt.Errorf("pam: Service must be set")
+	}
+	if strings.ContainsAny(p.Service, "/\x00") {
+		return fmt.Errorf("pam: invalid Service %s", p.Service)
+	}
+	if p.TimeLimit <= 0 {
+		p.TimeLimit = 5
+	}
+
+	if p.RateLimit == 0 {
+		p.limit.SetLimit(rate.Inf)
+	} else {
+		p.limit.SetLimit(rate.Limit(p.RateLimit))
+		p.limit.SetBurst(p.RateLimit)
+	}
+
+	return nil
+}
+
+// Authenticate verifies a user / password combination through PAM. It is safe for parallel usage.
+func (p *PAM) Authenticate(user, password string) (bool, error) {
+	if user == "" || password == "" || strings.Contains(user, "\x00") || strings.Contains(password, "\x00") {
+		return false, nil
+	}
+
+	// Rate limit
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.TimeLimit)*time.Second)
+	defer cancel()
+	err := p.limit.Wait(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	cService := C.CString(p.Service)
+	defer C.free(unsafe.Pointer(cService))
+	cUser := C.CString(user)
+	defer C.free(unsafe.Pointer(cUser))
+	cPassword := C.CString(password)
+	defer C.pollgo_pam_clear(cPassword)
+
+	account := C.int(1)
+	if p.SkipAccountCheck {
+		account = 0
+	}
+
+	ret := C.pollgo_pam_authenticate(cService, cUser, cPassword, account)
+	switch ret {
+	case C.PAM_SUCCESS:
+		return true, nil
+	case C.PAM_AUTH_ERR, C.PAM_USER_UNKNOWN, C.PAM_MAXTRIES, C.PAM_CRED_INSUFFICIENT, C.PAM_ACCT_EXPIRED, C.PAM_NEW_AUTHTOK_REQD, C.PAM_PERM_DENIED:
+		return false, nil
+	}
+	return false, fmt.Errorf("pam: %s", C.GoString(C.pam_strerror(nil, ret)))
+}
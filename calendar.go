@@ -0,0 +1,317 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// calendarMaxInvitations is the maximum number of calendar invitations sent for a final date of a poll.
+const calendarMaxInvitations = 200
+
+// calendarInvitationMutex ensures that invitations for a final date are only sent once.
+var calendarInvitationMutex sync.Mutex
+
+// calendarEventDuration is the duration of events with a time in calendar invitations.
+const calendarEventDuration = time.Hour
+
+// calendarYesOption is the answer option of date polls meaning that the participant is available.
+const calendarYesOption = 0
+
+var calendarEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// asksMail returns whether participants can leave an email address to receive a calendar invitation for the final date.
+func (p Poll) asksMail() bool {
+	return p.Dates != nil && !p.Anonymous && config.SMTPServer != ""
+}
+
+// finalDate returns the text of the question chosen as final date or an empty string.
+func (p Poll) finalDate() string {
+	if p.FinalDate <= 0 || p.FinalDate > len(p.Questions) {
+		return ""
+	}
+	return p.Questions[p.FinalDate-1]
+}
+
+// writeCalendarLine writes a single content line folded after 75 octets (RFC 5545, section 3.1).
+func writeCalendarLine(buf *bytes.Buffer, line string) {
+	for len(line) > 75 {
+		cut := 75
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+// calendarEvent returns the content lines describing the date of the question with the given index (starting with 0) as an event.
+// The lines are placed between BEGIN:VEVENT and END:VEVENT.
+// The times of date polls have no time zone, so they are written as floating times.
+func (p Poll) calendarEvent(key string, index int) []string {
+	tl := GetDefaultTranslation()
+	d := p.Dates[index]
+	h := sha256.Sum256([]byte(key))
+	link := strings.Join([]string{config.PublicURL, "/", key}, "")
+
+	lines := []string{
+		fmt.Sprintf("UID:%s-%d@pollgo", hex.EncodeToString(h[:16]), index+1),
+		"DTSTAMP:" + time.Now().UTC().Format("20060102T150405Z"),
+	}
+	if d.NoTime {
+		lines = append(lines, "DTSTART;VALUE=DATE:"+d.Time.Format("20060102"), "DTEND;VALUE=DATE:"+d.Time.AddDate(0, 0, 1).Format("20060102"))
+	} else {
+		lines = append(lines, "DTSTART:"+d.Time.Format("20060102T150405"), "DTEND:"+d.Time.Add(calendarEventDuration).Format("20060102T150405"))
+	}
+	lines = append(lines,
+		"SUMMARY:"+calendarEscaper.Replace(key),
+		"DESCRIPTION:"+calendarEscaper.Replace(fmt.Sprintf(tl.CalendarInvitationDescription, key, link)),
+		"URL:"+link,
+	)
+	return lines
+}
+
+// calendarInvitation returns the calendar invitation for the final date of the poll.
+func (p Poll) calendarInvitation(key, attendee string) []byte {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//pollgo//" + calendarEscaper.Replace(config.InstanceName) + "//EN",
+		"METHOD:REQUEST",
+		"BEGIN:VEVENT",
+	}
+	lines = append(lines, p.calendarEvent(key, p.FinalDate-1)...)
+	if from, err := mail.ParseAddress(config.SMTPFrom); err == nil {
+		lines = append(lines, "ORGANIZER:mailto:"+from.Address)
+	}
+	lines = append(lines,
+		"ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED;RSVP=FALSE:mailto:"+attendee,
+		"SEQUENCE:0",
+		"STATUS:CONFIRMED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+
+	var buf bytes.Buffer
+	for _, l := range lines {
+		writeCalendarLine(&buf, l)
+	}
+	return buf.Bytes()
+}
+
+// calendarExport returns a calendar for importing into calendar applications.
+// It contains the final date if one was chosen, otherwise all candidate dates as tentative events.
+func (p Poll) calendarExport(key string) []byte {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//pollgo//" + calendarEscaper.Replace(config.InstanceName) + "//EN",
+		"METHOD:PUBLISH",
+	}
+	if p.finalDate() != "" {
+		lines = append(lines, "BEGIN:VEVENT")
+		lines = append(lines, p.calendarEvent(key, p.FinalDate-1)...)
+		lines = append(lines, "STATUS:CONFIRMED", "END:VEVENT")
+	} else {
+		for i := range p.Dates {
+			lines = append(lines, "BEGIN:VEVENT")
+			lines = append(lines, p.calendarEvent(key, i)...)
+			lines = append(lines, "STATUS:TENTATIVE", "END:VEVENT")
+		}
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var buf bytes.Buffer
+	for _, l := range lines {
+		writeCalendarLine(&buf, l)
+	}
+	return buf.Bytes()
+}
+
+// handleCalendarExport writes the calendar export of the poll as a download.
+func (p Poll) handleCalendarExport(rw http.ResponseWriter, key string) {
+	if p.Dates == nil {
+		rw.WriteHeader(http.StatusNotFound)
+		t := textTemplateStruct{"404 Not Found", GetDefaultTranslation(), config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	rw.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.ics\"", strings.NewReplacer("\"", "", "/", "-", "\\", "-").Replace(key)))
+	rw.Write(p.calendarExport(key))
+}
+
+// calendarRecipients returns the email addresses of all participants who answered yes for the final date and left an email address.
+// The addresses are not verified, participants can leave any address.
+func (p Poll) calendarRecipients(key string) ([]string, error) {
+	results, _, _, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		return nil, err
+	}
+	mails, err := safe.GetAnswerMails(key)
+	if err != nil {
+		return nil, err
+	}
+
+	question := p.FinalDate - 1
+	recipients := make([]string, 0)
+	for i := range ids {
+		if question >= len(results[i]) || results[i][question] != calendarYesOption {
+			continue
+		}
+		if to := mails[ids[i]]; to != "" {
+			recipients = append(recipients, to)
+		}
+	}
+	return recipients, nil
+}
+
+// reserveCalendarInvitations records that invitations for the final date final are sent for the poll stored under key.
+// It returns false if they were already sent or the final date changed in the meantime.
+func reserveCalendarInvitations(key string, final int) (bool, error) {
+	calendarInvitationMutex.Lock()
+	defer calendarInvitationMutex.Unlock()
+
+	c, err := safe.GetPollConfig(key)
+	if err != nil {
+		return false, err
+	}
+	p, err := LoadPoll(c)
+	if err != nil {
+		return false, err
+	}
+	if p.FinalDate != final || slices.Contains(p.InvitationsSent, final) {
+		return false, nil
+	}
+	p.InvitationsSent = append(p.InvitationsSent, final)
+	b, err := p.ExportPoll()
+	if err != nil {
+		return false, err
+	}
+	return true, safe.SavePollConfig(key, b)
+}
+
+// sendCalendarInvitations sends a calendar invitation for the final date to all recipients.
+// It is run in the background, errors are logged.
+func (p Poll) sendCalendarInvitations(key string, recipients []string) {
+	tl := GetDefaultTranslation()
+	subject := fmt.Sprintf("%s: %s", config.InstanceName, fmt.Sprintf(tl.CalendarInvitationSubject, key))
+	body := fmt.Sprintf(tl.CalendarInvitationMailText, key, p.finalDate(), strings.Join([]string{config.PublicURL, "/", key}, ""))
+
+	sent := 0
+	for _, to := range recipients {
+		err := sendCalendarMail(to, subject, body, p.calendarInvitation(key, to))
+		if err != nil {
+			log.Printf("calendar: can not send invitation for %s: %s", key, err.Error())
+			continue
+		}
+		sent++
+	}
+	log.Printf("calendar: sent %d of %d invitations for %s", sent, len(recipients), key)
+}
+
+// handleFinalDate marks the question given in 'date' as the final date of the poll. An empty 'date' removes the final date.
+// If 'sendinvites' is set, calendar invitations are sent afterwards in the background (once per final date, at most calendarMaxInvitations).
+// The caller must ensure that the request was made by the creator of the poll.
+func (p Poll) handleFinalDate(rw http.ResponseWriter, r *http.Request, key string) {
+	tl := GetDefaultTranslation()
+
+	final := 0
+	if d := r.Form.Get("date"); d != "" {
+		i, err := strconv.Atoi(d)
+		if err != nil || i < 0 {
+			final = -1
+		} else {
+			final = i + 1
+		}
+	}
+	if p.Dates == nil || final < 0 || final > len(p.Dates) {
+		rw.WriteHeader(http.StatusBadRequest)
+		t := textTemplateStruct{"400 Bad Request", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	p.FinalDate = final
+	b, err := p.ExportPoll()
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	err = safe.SavePollConfig(key, b)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	if final == 0 || r.Form.Get("sendinvites") == "" || !p.asksMail() {
+		http.Redirect(rw, r, fmt.Sprintf("/%s", key), http.StatusSeeOther)
+		return
+	}
+
+	recipients, err := p.calendarRecipients(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	ok, err := reserveCalendarInvitations(key, final)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	if !ok {
+		rw.WriteHeader(http.StatusConflict)
+		text := fmt.Sprintf(`<p>%s</p><p><a href="/%s">%s</a></p>`, template.HTMLEscapeString(tl.CalendarInvitationsAlreadySent), template.HTMLEscapeString(key), template.HTMLEscapeString(tl.BackToPoll))
+		t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	total := len(recipients)
+	if total > calendarMaxInvitations {
+		log.Printf("calendar: %s has %d recipients, only sending %d invitations", key, total, calendarMaxInvitations)
+		recipients = recipients[:calendarMaxInvitations]
+	}
+	go p.sendCalendarInvitations(key, recipients)
+
+	text := fmt.Sprintf(`<p>%s</p><p><a href="/%s">%s</a></p>`, template.HTMLEscapeString(fmt.Sprintf(tl.CalendarInvitationsSent, len(recipients), total)), template.HTMLEscapeString(key), template.HTMLEscapeString(tl.BackToPoll))
+	t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
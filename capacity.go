@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A question with a capacity can only be picked by a limited number of participants.
+// An answer picks a question if the value of its answer is positive (e.g. 'yes' or 'if needed' in date polls).
+
+// capacityLocks serialises checking the capacity and saving answers per poll, so parallel answers can not exceed a capacity.
+var capacityLocks = make(map[string]*capacityLock)
+var capacityLocksMutex sync.Mutex
+
+type capacityLock struct {
+	sync.Mutex
+	users int // number of requests holding or waiting for the lock, the lock is removed once it reaches 0
+}
+
+// lockCapacity locks the capacity of the poll stored under key until the returned function is called.
+// The returned function can be called multiple times, so it can be deferred and called early once the answer is saved.
+func lockCapacity(key string) func() {
+	capacityLocksMutex.Lock()
+	l, ok := capacityLocks[key]
+	if !ok {
+		l = new(capacityLock)
+		capacityLocks[key] = l
+	}
+	l.users++
+	capacityLocksMutex.Unlock()
+
+	l.Lock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.Unlock()
+			capacityLocksMutex.Lock()
+			defer capacityLocksMutex.Unlock()
+			l.users--
+			if l.users == 0 {
+				delete(capacityLocks, key)
+			}
+		})
+	}
+}
+
+// maxCapacity is the largest capacity which can be set for a question.
+const maxCapacity = 1000000
+
+// optionValues returns the value of every answer option. Invalid values are 0.
+func (p Poll) optionValues() []float64 {
+	values := make([]float64, len(p.AnswerOption))
+	for o := range p.AnswerOption {
+		values[o], _ = strconv.ParseFloat(p.AnswerOption[o][1], 64)
+	}
+	return values
+}
+
+// hasCapacity returns whether at least one question of the poll has a capacity.
+func (p Poll) hasCapacity() bool {
+	for _, c := range p.Capacity {
+		if c > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// capacityUsage returns the number of answers picking each question. The answer exceptID is not counted.
+func (p Poll) capacityUsage(results [][]int, ids []string, exceptID string) []int {
+	values := p.optionValues()
+	used := make([]int, len(p.Questions))
+	for i := range results {
+		if i < len(ids) && exceptID != "" && ids[i] == exceptID {
+			continue
+		}
+		for q := range results[i] {
+			if q < len(used) && p.answerPoints(results[i][q], values) > 0 {
+				used[q]++
+			}
+		}
+	}
+	return used
+}
+
+// fullQuestions returns which questions of the poll reached their capacity. The answer exceptID is not counted, so it can keep its picks.
+func (p Poll) fullQuestions(results [][]int, ids []string, exceptID string) []bool {
+	full := make([]bool, len(p.Questions))
+	if !p.hasCapacity() {
+		return full
+	}
+	used := p.capacityUsage(results, ids, exceptID)
+	for q := range full {
+		full[q] = q < len(p.Capacity) && p.Capacity[q] > 0 && used[q] >= p.Capacity[q]
+	}
+	return full
+}
+
+// checkCapacity returns the questions which the answer picks although they are full.
+// The answer answerID (empty for new answers) is not counted. The capacity must be locked (lockCapacity) until the answer is saved.
+func (p Poll) checkCapacity(key, answerID string, answer []int) ([]string, error) {
+	if !p.hasCapacity() {
+		return nil, nil
+	}
+	results, _, _, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		return nil, err
+	}
+	full := p.fullQuestions(results, ids, answerID)
+	values := p.optionValues()
+	exceeded := make([]string, 0)
+	for q := range answer {
+		if q < len(full) && full[q] && p.answerPoints(answer[q], values) > 0 {
+			exceeded = append(exceeded, p.Questions[q])
+		}
+	}
+	return exceeded, nil
+}
+
+// importExceedsCapacity returns the questions which would exceed their capacity if the imported answers were added to the current usage used.
+// Questions which are not picked by any imported answer are never returned.
+func (p Poll) importExceedsCapacity(used []int, answers [][]int) []string {
+	added := p.capacityUsage(answers, nil, "")
+	exceeded := make([]string, 0)
+	for q := range added {
+		if added[q] > 0 && q < len(p.Capacity) && p.Capacity[q] > 0 && used[q]+added[q] > p.Capacity[q] {
+			exceeded = append(exceeded, p.Questions[q])
+		}
+	}
+	return exceeded
+}
+
+// checkImportCapacity returns the questions which would exceed their capacity if the imported answers were saved to the poll stored under key.
+// The capacity must be locked (lockCapacity) until the answers are saved.
+func (p Poll) checkImportCapacity(key string, answers [][]int) ([]string, error) {
+	if !p.hasCapacity() {
+		return nil, nil
+	}
+	results, _, _, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.importExceedsCapacity(p.capacityUsage(results, ids, ""), answers), nil
+}
+
+// capacityExceededMessage returns the message shown if an answer picks full questions.
+func capacityExceededMessage(tl Translation, questions []string) string {
+	return fmt.Sprintf(tl.CapacityExceeded, strings.Join(questions, ", "))
+}
+
+// parseCapacity returns the capacity given in a form. An empty value means no limit.
+func parseCapacity(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	c, err := strconv.Atoi(v)
+	if err != nil || c < 0 || c > maxCapacity {
+		return 0, fmt.Errorf("invalid capacity %s", v)
+	}
+	return c, nil
+}
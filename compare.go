@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type compareRow struct {
+	Question      string
+	OtherQuestion string // empty if the question of the other poll has the same text
+	Points        float64
+	OtherPoints   float64
+	Difference    float64
+	Percentages   [][2]float64 // [answer option][poll], only set if both polls have the same answer options
+}
+
+type compareTemplateStruct struct {
+	Key               string
+	OtherKey          string
+	Participants      int
+	OtherParticipants int
+	AnswerOptions     []string // nil if the answer options of the polls differ
+	Rows              []compareRow
+	Numbers           numberFormat
+	Translation       Translation
+}
+
+var compareTemplate = template.Must(template.New("compare").Parse(`
+<h1>{{.Translation.ComparePolls}}: <a href="/{{.Key}}">{{.Key}}</a> / <a href="/{{.OtherKey}}">{{.OtherKey}}</a></h1>
+<p>{{.Translation.Answers}}: {{.Participants}} / {{.OtherParticipants}}</p>
+<div style="width: 100%; overflow-x: auto;">
+<table>
+<thead>
+<tr>
+<th>{{.Translation.Question}}</th>
+<th>{{.Translation.Points}} ({{.Key}})</th>
+<th>{{.Translation.Points}} ({{.OtherKey}})</th>
+<th>{{.Translation.CompareDifference}}</th>
+{{range .AnswerOptions}}<th>{{.}} (%)</th>{{end}}
+</tr>
+</thead>
+<tbody>
+{{range .Rows}}
+<tr>
+<td>{{.Question}}{{if .OtherQuestion}} / {{.OtherQuestion}}{{end}}</td>
+<td class="centre">{{$.Numbers.Score .Points}}</td>
+<td class="centre">{{$.Numbers.Score .OtherPoints}}</td>
+<td class="centre">{{if gt .Difference 0.0}}+{{end}}{{$.Numbers.Score .Difference}}</td>
+{{range .Percentages}}<td class="centre">{{printf "%.0f" (index . 0)}} % / {{printf "%.0f" (index . 1)}} %</td>{{end}}
+</tr>
+{{end}}
+</tbody>
+</table>
+</div>
+`))
+
+// compareKey returns the key of the poll given by the visitor, which might be the key itself or a link to the poll.
+func compareKey(v string) (string, bool) {
+	v, _, _ = strings.Cut(strings.TrimSpace(v), "?")
+	v, _, _ = strings.Cut(v, "#")
+	v = strings.TrimRight(v, "/")
+	v = v[strings.LastIndex(v, "/")+1:]
+	if v == "" {
+		return "", false
+	}
+	// Keys contain the server path, same as for the HTML handlers
+	return strings.TrimLeft(strings.Join([]string{config.ServerPath, "/", v}, ""), "/"), true
+}
+
+// compareQuestions returns for each question in a the index of the matching question in b.
+// Questions are matched by their text if both polls have the same questions, otherwise by position (e.g. date polls of different months).
+// The bool is false if the polls have a different number of questions.
+func compareQuestions(a, b []string) ([]int, bool) {
+	if len(a) != len(b) {
+		return nil, false
+	}
+	match := make([]int, len(a))
+	used := make([]bool, len(b))
+	byText := true
+	for i := range a {
+		match[i] = -1
+		for j := range b {
+			if !used[j] && a[i] == b[j] {
+				match[i] = j
+				used[j] = true
+				break
+			}
+		}
+		if match[i] == -1 {
+			byText = false
+			break
+		}
+	}
+	if !byText {
+		for i := range match {
+			match[i] = i
+		}
+	}
+	return match, true
+}
+
+// handleCompare shows the aggregated results of the poll next to the results of the poll given in 'compare'.
+func (p Poll) handleCompare(rw http.ResponseWriter, r *http.Request, key, compare string) {
+	tl := GetDefaultTranslation()
+
+	otherKey, ok := compareKey(compare)
+	if !ok {
+		rw.WriteHeader(http.StatusBadRequest)
+		t := textTemplateStruct{"400 Bad Request", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	c, err := safe.GetPollConfig(otherKey)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	other, err := LoadPoll(c)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	if !other.initialised || other.Deleted || other.Hidden {
+		rw.WriteHeader(http.StatusNotFound)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollNotFound)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	if p.resultsHidden() || other.resultsHidden() {
+		rw.WriteHeader(http.StatusForbidden)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.ResultsHiddenUntilClosed)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	match, ok := compareQuestions(p.Questions, other.Questions)
+	if !ok {
+		rw.WriteHeader(http.StatusBadRequest)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.CompareDifferentQuestions)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	stats, err := p.GetStatistics(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	otherStats, err := other.GetStatistics(otherKey)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	td := compareTemplateStruct{
+		Key:               key,
+		OtherKey:          otherKey,
+		Participants:      stats.Participants,
+		OtherParticipants: otherStats.Participants,
+		Rows:              make([]compareRow, len(p.Questions)),
+		Numbers:           requestNumberFormat(r),
+		Translation:       tl,
+	}
+	if slices.Equal(stats.AnswerOptions, otherStats.AnswerOptions) {
+		td.AnswerOptions = stats.AnswerOptions
+	}
+
+	percentage := func(count, participants int) float64 {
+		if participants == 0 {
+			return 0
+		}
+		return float64(count) * 100 / float64(participants)
+	}
+
+	for q := range td.Rows {
+		o := match[q]
+		row := compareRow{
+			Question:    p.Questions[q],
+			Points:      stats.Points[q],
+			OtherPoints: otherStats.Points[o],
+			Difference:  stats.Points[q] - otherStats.Points[o],
+		}
+		if other.Questions[o] != p.Questions[q] {
+			row.OtherQuestion = other.Questions[o]
+		}
+		if td.AnswerOptions != nil {
+			row.Percentages = make([][2]float64, len(td.AnswerOptions))
+			for a := range row.Percentages {
+				row.Percentages[a] = [2]float64{percentage(stats.Counts[q][a], stats.Participants), percentage(otherStats.Counts[o][a], otherStats.Participants)}
+			}
+		}
+		td.Rows[q] = row
+	}
+
+	buf := bytes.Buffer{}
+	err = compareTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("compare: %s", err.Error())
+	}
+	t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
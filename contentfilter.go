@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// contentFilter holds all patterns of the blocklist at 'PathContentFilter'.
+// It must only be written to during server initialisation.
+var contentFilter []*regexp.Regexp
+
+// loadContentFilter reads the blocklist at path.
+// Each line contains a case insensitive regular expression, empty lines and lines starting with '#' are ignored.
+func loadContentFilter(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	line := 0
+	for s.Scan() {
+		line++
+		l := strings.TrimSpace(s.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		re, err := regexp.Compile(strings.Join([]string{"(?i)", l}, ""))
+		if err != nil {
+			return fmt.Errorf("content filter line %d: %w", line, err)
+		}
+		contentFilter = append(contentFilter, re)
+	}
+	return s.Err()
+}
+
+// filterContent checks all texts against the blocklist.
+// If 'ContentFilterMask' is set, all matches are replaced by '*' and true is returned.
+// Otherwise, the texts are not changed and false is returned if any text matches.
+func filterContent(texts ...*string) bool {
+	for _, t := range texts {
+		for _, re := range contentFilter {
+			if !re.MatchString(*t) {
+				continue
+			}
+			if !config.ContentFilterMask {
+				return false
+			}
+			*t = re.ReplaceAllStringFunc(*t, func(s string) string {
+				return strings.Repeat("*", utf8.RuneCountInString(s))
+			})
+		}
+	}
+	return true
+}
+
+// filterPollTexts checks the description, the questions and the answer options of the poll against the blocklist (see filterContent).
+func (p *Poll) filterPollTexts() bool {
+	texts := []*string{&p.Description}
+	for i := range p.Questions {
+		texts = append(texts, &p.Questions[i])
+	}
+	for i := range p.AnswerOption {
+		texts = append(texts, &p.AnswerOption[i][0])
+	}
+	return filterContent(texts...)
+}
+
+// writeContentFiltered informs the user that the submitted text was rejected by the content filter.
+func writeContentFiltered(rw http.ResponseWriter) {
+	rw.WriteHeader(http.StatusBadRequest)
+	tl := GetDefaultTranslation()
+	t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.ContentFiltered)), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
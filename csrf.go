@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/subtle"
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/Top-Ranger/pollgo/helper"
+)
+
+// csrfCookieName is the name of the cookie holding the CSRF token of the browser session.
+const csrfCookieName = "pollgo_csrf"
+
+// csrfFormField is the name of the form field which must repeat the CSRF token.
+const csrfFormField = "csrf"
+
+// csrfToken returns the CSRF token of the browser session. If the request does not contain one, a new token is created and set as a cookie.
+// It must be called before the response header is written.
+func csrfToken(rw http.ResponseWriter, r *http.Request) string {
+	c, err := r.Cookie(csrfCookieName)
+	if err == nil && c.Value != "" {
+		return c.Value
+	}
+
+	token := helper.GetRandomString()
+	cookie := http.Cookie{}
+	cookie.Name = csrfCookieName
+	cookie.Value = token
+	cookie.Path = "/"
+	cookie.SameSite = http.SameSiteLaxMode
+	cookie.HttpOnly = true
+	cookie.Secure = !config.InsecureAllowCookiesOverHTTP
+	http.SetCookie(rw, &cookie)
+	return token
+}
+
+// checkCSRF verifies that the form of the request contains the CSRF token of the browser session.
+// Cross-site requests can send the cookie, but can not read it, so they can not know the token.
+// The form must already be parsed.
+// It returns false if the request must not be processed further. In that case, the response has already been written.
+func checkCSRF(rw http.ResponseWriter, r *http.Request) bool {
+	if validCSRFToken(r, r.Form.Get(csrfFormField)) {
+		return true
+	}
+
+	if config.LogFailedLogin {
+		log.Printf("Invalid CSRF token from %s", GetRealIP(r))
+	}
+	tl := GetDefaultTranslation()
+	rw.WriteHeader(http.StatusForbidden)
+	t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.CSRFInvalid)), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+	return false
+}
+
+// validCSRFToken returns whether token is the CSRF token of the browser session of the request.
+func validCSRFToken(r *http.Request, token string) bool {
+	c, err := r.Cookie(csrfCookieName)
+	return err == nil && c.Value != "" && subtle.ConstantTimeCompare([]byte(c.Value), []byte(token)) == 1
+}
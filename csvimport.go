@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Top-Ranger/pollgo/helper"
+)
+
+// csvImportMaxRows is the maximum number of answers which can be imported at once.
+const csvImportMaxRows = 10000
+
+// csvImportError is returned by ImportCSV if the CSV file is invalid. All other errors are internal errors.
+type csvImportError struct {
+	err error
+}
+
+func (e csvImportError) Error() string {
+	return e.err.Error()
+}
+
+func (e csvImportError) Unwrap() error {
+	return e.err
+}
+
+type csvAnswer struct {
+	name    string
+	comment string
+	results []int
+}
+
+// parseCSVOption returns the index of the answer option given by its text (case insensitive) or index.
+func parseCSVOption(answerIndex map[string]int, options int, cell string) (int, bool) {
+	i, ok := answerIndex[strings.ToLower(cell)]
+	if ok {
+		return i, true
+	}
+	i, err := strconv.Atoi(cell)
+	if err != nil || i < 0 || i >= options {
+		return 0, false
+	}
+	return i, true
+}
+
+// ImportCSV imports answers from a CSV file and saves them to the poll stored under key.
+// The first row is a header and is ignored. All other rows must contain the name, the comment and one column per question.
+// Answers can either be given as the text of an answer option (case insensitive) or as the index of the answer option.
+// In multiple choice polls, several answer options can be separated by '|'. An empty cell selects no answer option.
+// Comments are dropped if the poll has comments disabled. If the poll requires unique names, duplicate names are rejected.
+// Nothing is saved if any row is invalid or the poll was edited since p was loaded (csvImportError). It returns the number of imported answers.
+func (p Poll) ImportCSV(key string, data io.Reader) (int, error) {
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = 2 + len(p.Questions)
+	reader.TrimLeadingSpace = true
+
+	answerIndex := make(map[string]int, len(p.AnswerOption))
+	for i := range p.AnswerOption {
+		answerIndex[strings.ToLower(p.AnswerOption[i][0])] = i
+	}
+
+	usedNames := make(map[string]bool)
+	if p.UniqueNames {
+		_, names, _, _, err := safe.GetPollResult(key)
+		if err != nil {
+			return 0, err
+		}
+		for i := range names {
+			usedNames[normaliseName(names[i])] = true
+		}
+	}
+
+	answers := make([]csvAnswer, 0)
+	header := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, csvImportError{err}
+		}
+		if header {
+			header = false
+			continue
+		}
+		if len(answers) >= csvImportMaxRows {
+			return 0, csvImportError{fmt.Errorf("more than %d answers", csvImportMaxRows)}
+		}
+
+		line, _ := reader.FieldPos(0)
+		a := csvAnswer{name: record[0], comment: record[1], results: make([]int, len(p.Questions))}
+		if p.DisableComments {
+			a.comment = ""
+		}
+		if !filterContent(&a.name, &a.comment) {
+			return 0, csvImportError{fmt.Errorf("line %d: %s", line, GetDefaultTranslation().ContentFiltered)}
+		}
+		if p.UniqueNames && normaliseName(a.name) != "" {
+			if usedNames[normaliseName(a.name)] {
+				return 0, csvImportError{fmt.Errorf("line %d: name '%s' is already used", line, a.name)}
+			}
+			usedNames[normaliseName(a.name)] = true
+		}
+		for q := range p.Questions {
+			cell := strings.TrimSpace(record[2+q])
+			if !p.MultipleChoice {
+				i, ok := parseCSVOption(answerIndex, len(p.AnswerOption), cell)
+				if !ok {
+					return 0, csvImportError{fmt.Errorf("line %d: unknown answer option '%s'", line, cell)}
+				}
+				a.results[q] = i
+				continue
+			}
+			if cell == "" {
+				// No answer option selected
+				continue
+			}
+			for _, option := range strings.Split(cell, "|") {
+				i, ok := parseCSVOption(answerIndex, len(p.AnswerOption), strings.TrimSpace(option))
+				if !ok {
+					return 0, csvImportError{fmt.Errorf("line %d: unknown answer option '%s'", line, option)}
+				}
+				a.results[q] |= 1 << i
+			}
+		}
+		answers = append(answers, a)
+	}
+
+	if p.hasCapacity() {
+		// Hold the lock until all answers are saved, so parallel answers can not exceed the capacity
+		defer lockCapacity(key)()
+		results := make([][]int, len(answers))
+		for i := range answers {
+			results[i] = answers[i].results
+		}
+		exceeded, err := p.checkImportCapacity(key, results)
+		if err != nil {
+			return 0, err
+		}
+		if len(exceeded) != 0 {
+			return 0, csvImportError{errors.New(capacityExceededMessage(GetDefaultTranslation(), exceeded))}
+		}
+	}
+
+	// Hold the lock until all answers are saved, so the poll can not be edited in between
+	pollStructureMutex.RLock()
+	defer pollStructureMutex.RUnlock()
+	unchanged, err := structureUnchanged(key, p.Revision())
+	if err != nil {
+		return 0, err
+	}
+	if !unchanged {
+		return 0, csvImportError{errors.New(GetDefaultTranslation().PollChanged)}
+	}
+
+	for i := range answers {
+		id, err := safe.SavePollResult(key, answers[i].name, answers[i].comment, answers[i].results, helper.GetRandomString())
+		if err != nil {
+			return i, err
+		}
+		hookAnswerSaved(key, id, false)
+	}
+	return len(answers), nil
+}
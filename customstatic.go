@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// customStaticHandle serves files from 'CustomStaticPath' under '/custom/'.
+// Files are read on every request, so they can be changed without a restart.
+// Directories and hidden files are not served.
+func customStaticHandle(rw http.ResponseWriter, r *http.Request) {
+	p := strings.TrimPrefix(r.URL.Path, strings.Join([]string{config.ServerPath, "/custom/"}, ""))
+	p = path.Clean(strings.Join([]string{"/", p}, ""))
+	for _, s := range strings.Split(p, "/") {
+		if strings.HasPrefix(s, ".") {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+
+	f, err := os.Open(filepath.Join(config.CustomStaticPath, filepath.FromSlash(p)))
+	if err != nil {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(p))
+	if contentType == "" {
+		b := make([]byte, 512)
+		n, _ := f.Read(b)
+		contentType = http.DetectContentType(b[:n])
+		_, err = f.Seek(0, 0)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	rw.Header().Set("Content-Type", contentType)
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	rw.Header().Set("Cache-Control", "public, max-age=43200")
+	http.ServeContent(rw, r, info.Name(), info.ModTime(), f)
+}
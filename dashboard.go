@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// dashboardLeading is the number of leading questions shown in the dashboard.
+const dashboardLeading = 3
+
+type dashboardDay struct {
+	Date   string
+	New    int
+	Total  int
+	Points []float64 // points of the leading questions at the end of the day
+}
+
+type dashboardEdit struct {
+	Name     string
+	Created  string
+	Modified string
+}
+
+type dashboardTemplateStruct struct {
+	Key         string
+	Answers     int
+	Unknown     int // answers without known creation time
+	Leading     []string
+	Days        []dashboardDay
+	Edits       []dashboardEdit
+	Access      bool // whether access statistics are shown
+	Views       int
+	Opens       int
+	UniqueOpens int
+	Numbers     numberFormat
+	Translation Translation
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`
+<h1>{{.Translation.Dashboard}}: <a href="/{{.Key}}">{{.Key}}</a></h1>
+<p>{{.Translation.Answers}}: {{.Answers}}{{if .Unknown}} ({{.Unknown}} {{.Translation.UnknownCreationTime}}){{end}}</p>
+<h2>{{.Translation.ResponsesOverTime}}</h2>
+{{if .Days}}
+<div style="width: 100%; overflow-x: auto;">
+<table>
+<thead>
+<tr>
+<th>{{.Translation.Date}}</th>
+<th>{{.Translation.NewAnswers}}</th>
+<th>{{.Translation.Answers}}</th>
+{{range .Leading}}<th>{{.}}</th>{{end}}
+</tr>
+</thead>
+<tbody>
+{{range .Days}}
+<tr>
+<td>{{.Date}}</td>
+<td class="centre">{{.New}}</td>
+<td class="centre">{{.Total}}</td>
+{{range .Points}}<td class="centre">{{$.Numbers.Score .}}</td>{{end}}
+</tr>
+{{end}}
+</tbody>
+</table>
+</div>
+{{else}}
+<p>{{.Translation.NoData}}</p>
+{{end}}
+<h2>{{.Translation.EditedAnswers}}</h2>
+{{if .Edits}}
+<table>
+<thead>
+<tr>
+<th>{{.Translation.Name}}</th>
+<th>{{.Translation.Created}}</th>
+<th>{{.Translation.Modified}}</th>
+</tr>
+</thead>
+<tbody>
+{{range .Edits}}
+<tr>
+<td>{{if .Name}}{{.Name}}{{else}}<em>[{{$.Translation.Unknown}}]</em>{{end}}</td>
+<td>{{.Created}}</td>
+<td>{{.Modified}}</td>
+</tr>
+{{end}}
+</tbody>
+</table>
+{{else}}
+<p>{{.Translation.NoData}}</p>
+{{end}}
+{{if .Access}}
+<h2>{{.Translation.AccessStatistics}}</h2>
+<table>
+<tbody>
+<tr><td>{{.Translation.PageViews}}</td><td class="centre">{{.Views}}</td></tr>
+<tr><td>{{.Translation.AnswerFormOpens}}</td><td class="centre">{{.Opens}}</td></tr>
+<tr><td>{{.Translation.UniqueAnswerFormOpens}}</td><td class="centre">{{.UniqueOpens}}</td></tr>
+</tbody>
+</table>
+<p><small>{{.Translation.AccessStatisticsNote}}</small></p>
+{{end}}
+`))
+
+// handleDashboard shows the participation dashboard of a poll.
+// Access is only allowed for the creator of the poll.
+func (p Poll) handleDashboard(rw http.ResponseWriter, r *http.Request, key string) {
+	tl := GetDefaultTranslation()
+
+	if !checkCreator(rw, r, key, true) {
+		return
+	}
+
+	values := make([]float64, len(p.AnswerOption))
+	for i := range p.AnswerOption {
+		f, err := strconv.ParseFloat(p.AnswerOption[i][1], 64)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		values[i] = f
+	}
+
+	results, names, _, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	created, modified, err := safe.GetAnswerTimes(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	views, opens, uniqueOpens, err := safe.GetPollAccess(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	points := func(r []int) []float64 {
+		pt := make([]float64, len(p.Questions))
+		for q := range r {
+			if q < len(pt) {
+				pt[q] = p.answerPoints(r[q], values)
+			}
+		}
+		return pt
+	}
+
+	td := dashboardTemplateStruct{
+		Key:         sanitiseKey(key),
+		Answers:     len(results),
+		Days:        make([]dashboardDay, 0),
+		Edits:       make([]dashboardEdit, 0),
+		Access:      config.EnableAccessStatistics,
+		Views:       views,
+		Opens:       opens,
+		UniqueOpens: uniqueOpens,
+		Numbers:     requestNumberFormat(r),
+		Translation: tl,
+	}
+
+	// Determine leading questions by current points
+	total := make([]float64, len(p.Questions))
+	for i := range results {
+		for q, v := range points(results[i]) {
+			total[q] += v
+		}
+	}
+	leading := make([]int, len(p.Questions))
+	for i := range leading {
+		leading[i] = i
+	}
+	sort.SliceStable(leading, func(i, j int) bool { return total[leading[i]] > total[leading[j]] })
+	leading = leading[:min(dashboardLeading, len(leading))]
+	for _, q := range leading {
+		td.Leading = append(td.Leading, p.Questions[q])
+	}
+
+	// Aggregate answers per day of creation
+	order := make([]int, 0, len(results))
+	running := make([]float64, len(p.Questions))
+	for i := range results {
+		if created[ids[i]].IsZero() {
+			// Answers without creation time are counted from the start
+			td.Unknown++
+			for q, v := range points(results[i]) {
+				running[q] += v
+			}
+			continue
+		}
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return created[ids[order[i]]].Before(created[ids[order[j]]]) })
+
+	count := td.Unknown
+	for _, i := range order {
+		count++
+		for q, v := range points(results[i]) {
+			running[q] += v
+		}
+		date := created[ids[i]].Format(config.DateDisplayFormat)
+		if len(td.Days) == 0 || td.Days[len(td.Days)-1].Date != date {
+			td.Days = append(td.Days, dashboardDay{Date: date})
+		}
+		d := &td.Days[len(td.Days)-1]
+		d.New++
+		d.Total = count
+		d.Points = d.Points[:0]
+		for _, q := range leading {
+			d.Points = append(d.Points, running[q])
+		}
+	}
+
+	// Edited answers
+	for i := range results {
+		c, m := created[ids[i]], modified[ids[i]]
+		if c.IsZero() || !m.After(c) {
+			continue
+		}
+		td.Edits = append(td.Edits, dashboardEdit{
+			Name:     names[i],
+			Created:  c.Format(config.DateTimeDisplayFormat),
+			Modified: m.Format(config.DateTimeDisplayFormat),
+		})
+	}
+
+	buf := bytes.Buffer{}
+	err = dashboardTemplate.Execute(&buf, td)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(fmt.Sprintf("dashboard: %s", internalErrorText(err)))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
@@ -0,0 +1,2 @@
+CREATE TABLE pollgo.invitation (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, token VARCHAR(100) NOT NULL, name MEDIUMTEXT NOT NULL, answer TINYTEXT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX ip ON pollgo.invitation (poll);
@@ -0,0 +1 @@
+ALTER TABLE pollgo.result ADD mail TINYTEXT NULL;
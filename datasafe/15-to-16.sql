@@ -0,0 +1,2 @@
+CREATE TABLE pollgo.event (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, event TINYTEXT NOT NULL, answer TINYTEXT NOT NULL, actor MEDIUMTEXT NOT NULL, time BIGINT NOT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX ep ON pollgo.event (poll);
@@ -0,0 +1 @@
+ALTER TABLE pollgo.poll ADD COLUMN views BIGINT NOT NULL DEFAULT 0, ADD COLUMN opens BIGINT NOT NULL DEFAULT 0, ADD COLUMN uniqueopens BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+CREATE TABLE pollgo.organizer (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, name MEDIUMTEXT NOT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX op ON pollgo.organizer (poll);
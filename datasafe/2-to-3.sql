@@ -0,0 +1 @@
+ALTER TABLE pollgo.poll ADD lastactivity BIGINT NULL;
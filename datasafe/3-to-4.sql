@@ -0,0 +1,2 @@
+CREATE TABLE pollgo.discussion (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, name MEDIUMTEXT NOT NULL, text MEDIUMTEXT NOT NULL, time BIGINT NOT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX dp ON pollgo.discussion (poll);
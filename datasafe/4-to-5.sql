@@ -0,0 +1 @@
+ALTER TABLE pollgo.result ADD endorsements INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE pollgo.result ADD created BIGINT NULL;
+ALTER TABLE pollgo.result ADD modified BIGINT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE pollgo.result ADD consenttime BIGINT NULL;
+ALTER TABLE pollgo.result ADD consentversion TINYTEXT NULL;
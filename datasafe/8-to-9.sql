@@ -0,0 +1 @@
+ALTER TABLE pollgo.poll ADD created BIGINT NULL;
@@ -0,0 +1,2 @@
+CREATE TABLE pollgo.report (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, reason MEDIUMTEXT NOT NULL, time BIGINT NOT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX rep ON pollgo.report (poll);
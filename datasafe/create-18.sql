@@ -0,0 +1,14 @@
+CREATE DATABASE pollgo;
+CREATE TABLE pollgo.poll (name VARCHAR(600) NOT NULL, data LONGBLOB NOT NULL, creator TEXT, deleted BOOLEAN, lastactivity BIGINT NULL, reminder BIGINT NULL, created BIGINT NULL, followup BIGINT NULL, expiry BIGINT NULL, aggregate LONGBLOB NULL, views BIGINT NOT NULL DEFAULT 0, opens BIGINT NOT NULL DEFAULT 0, uniqueopens BIGINT NOT NULL DEFAULT 0, PRIMARY KEY(name));
+CREATE TABLE pollgo.result (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, name MEDIUMTEXT NOT NULL, comment MEDIUMTEXT NOT NULL, results LONGBLOB NOT NULL, `change` TINYTEXT, endorsements INT NOT NULL DEFAULT 0, created BIGINT NULL, modified BIGINT NULL, consenttime BIGINT NULL, consentversion TINYTEXT NULL, mail TINYTEXT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX rp ON pollgo.result (poll);
+CREATE TABLE pollgo.discussion (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, name MEDIUMTEXT NOT NULL, text MEDIUMTEXT NOT NULL, time BIGINT NOT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX dp ON pollgo.discussion (poll);
+CREATE TABLE pollgo.report (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, reason MEDIUMTEXT NOT NULL, time BIGINT NOT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX rep ON pollgo.report (poll);
+CREATE TABLE pollgo.invitation (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, token VARCHAR(100) NOT NULL, name MEDIUMTEXT NOT NULL, answer TINYTEXT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX ip ON pollgo.invitation (poll);
+CREATE TABLE pollgo.event (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, event TINYTEXT NOT NULL, answer TINYTEXT NOT NULL, actor MEDIUMTEXT NOT NULL, time BIGINT NOT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX ep ON pollgo.event (poll);
+CREATE TABLE pollgo.organizer (id BIGINT UNSIGNED AUTO_INCREMENT, poll VARCHAR(600) NOT NULL, name MEDIUMTEXT NOT NULL, PRIMARY KEY (id), FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT);
+CREATE INDEX op ON pollgo.organizer (poll);
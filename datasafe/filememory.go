@@ -56,6 +56,15 @@ var ErrFileMemoryInvalidID = errors.New("filememory got invalid ID")
 // FileMemoryName contains the name of the DataSafe
 const FileMemoryName = "FileMemory"
 
+// Polls are written to the journal directory inside of Path before they are moved to their final place.
+// As it is a directory, it is skipped when reading polls from Path.
+const (
+	fileMemoryJournalDir      = ".journal"
+	fileMemoryJournalPartial  = ".partial"  // suffix of polls which are being written
+	fileMemoryJournalComplete = ".complete" // suffix of completely written polls which are not moved yet
+	fileMemoryRunningMarker   = "running"   // exists while the FileMemory is active, a leftover marker means an unclean shutdown
+)
+
 // FileMemory holds a number of polls in memory and saves all other to disk.
 type FileMemory struct {
 	// Interval in minutes when a cleanup operation is started.
@@ -77,6 +86,18 @@ type FileMemory struct {
 	//  Path where polls are saved to disk.
 	Path string
 
+	// Number of poll files the gc processes at once. Between batches, request handling continues.
+	// Setting this to 0 uses the default of 100.
+	GCBatchSize int
+
+	// Number of batches the gc processes in parallel.
+	// Setting this to 0 uses one worker.
+	GCWorkers int
+
+	// Pause in milliseconds each gc worker waits after a batch.
+	// This is used to reduce the load on the disc during gc.
+	GCBatchPause int
+
 	memory              map[string]FileMemoryPollResult
 	active              bool
 	l                   *sync.Mutex
@@ -97,6 +118,68 @@ type FileMemoryPollResult struct {
 	Change        []string
 	IDs           []string
 	AnswerCounter int
+	LastActivity  time.Time
+	Discussion    []FileMemoryDiscussionEntry
+	EntryCounter  int
+	Endorsements  map[string]int               // answer ID -> number of endorsements
+	Reminder      time.Time                    // zero if no reminder is set
+	Created       []time.Time                  // zero if not known
+	Modified      []time.Time                  // zero if not known
+	Consents      map[string]FileMemoryConsent // answer ID -> consent
+	PollCreated   time.Time                    // zero if not known
+	Reports       []FileMemoryReport
+	Invitations   []FileMemoryInvitation
+	FollowUp      time.Time         // zero if no follow-up poll is due
+	Mails         map[string]string // answer ID -> email address
+	Expiry        time.Time         // zero if the poll does not expire
+	Aggregate     []byte            // precomputed aggregate, nil if none
+	Events        []FileMemoryEvent
+	Access        FileMemoryAccess
+	Organizers    []string
+}
+
+// FileMemoryConsent is a helper struct which holds the consent given with an answer.
+type FileMemoryConsent struct {
+	Time    time.Time
+	Version string
+}
+
+// FileMemoryReport is a helper struct which holds a single abuse report of a poll.
+type FileMemoryReport struct {
+	Reason string
+	Time   time.Time
+}
+
+// FileMemoryEvent is a helper struct which holds a single entry of the activity log of a poll.
+// AnswerID is empty if the event does not belong to an answer.
+type FileMemoryEvent struct {
+	Event    string
+	AnswerID string
+	Actor    string
+	Time     time.Time
+}
+
+// FileMemoryAccess is a helper struct which holds the aggregated access counts of a poll.
+type FileMemoryAccess struct {
+	Views       int
+	Opens       int
+	UniqueOpens int
+}
+
+// FileMemoryInvitation is a helper struct which holds a single invitation to a poll.
+// AnswerID is empty if the invitation was not used yet.
+type FileMemoryInvitation struct {
+	Token    string
+	Name     string
+	AnswerID string
+}
+
+// FileMemoryDiscussionEntry is a helper struct which holds a single entry of the discussion of a poll.
+type FileMemoryDiscussionEntry struct {
+	ID   string
+	Name string
+	Text string
+	Time time.Time
 }
 
 func (fm FileMemory) getInternalID(ID string) (string, error) {
@@ -107,6 +190,10 @@ func (fm FileMemory) getInternalID(ID string) (string, error) {
 	return strings.ReplaceAll(ID, string(os.PathSeparator), "﷐"), nil
 }
 
+func (fm FileMemory) getExternalID(ID string) string {
+	return strings.ReplaceAll(ID, "﷐", string(os.PathSeparator))
+}
+
 // SavePollResult saves the results of a single poll.
 func (fm *FileMemory) SavePollResult(pollID, name, comment string, results []int, change string) (string, error) {
 	fm.l.Lock()
@@ -125,6 +212,9 @@ func (fm *FileMemory) SavePollResult(pollID, name, comment string, results []int
 	}
 
 	p := fm.memory[pollID]
+	if p.Config == nil || p.Deleted {
+		return "", registry.ErrPollNotAvailable
+	}
 	p.Data = append(p.Data, results)
 	p.Names = append(p.Names, name)
 	p.Comments = append(p.Comments, comment)
@@ -133,6 +223,9 @@ func (fm *FileMemory) SavePollResult(pollID, name, comment string, results []int
 	id := fmt.Sprintf("%d-%s", p.AnswerCounter, fm.getRandomID())
 	p.IDs = append(p.IDs, id)
 	p.LastAccess = time.Now()
+	p.LastActivity = p.LastAccess
+	p.Created = append(p.Created, p.LastAccess)
+	p.Modified = append(p.Modified, p.LastAccess)
 	fm.memory[pollID] = p
 	return id, nil
 }
@@ -156,6 +249,9 @@ func (fm *FileMemory) OverwritePollResult(pollID, answerID, name, comment string
 	}
 
 	p := fm.memory[pollID]
+	if p.Config == nil || p.Deleted {
+		return registry.ErrPollNotAvailable
+	}
 
 	for i := range p.IDs {
 		if p.IDs[i] == answerID {
@@ -164,6 +260,8 @@ func (fm *FileMemory) OverwritePollResult(pollID, answerID, name, comment string
 			p.Comments[i] = comment
 			p.Change[i] = change
 			p.LastAccess = time.Now()
+			p.LastActivity = p.LastAccess
+			p.Modified[i] = p.LastAccess
 			fm.memory[pollID] = p
 			return nil
 		}
@@ -196,6 +294,162 @@ func (fm *FileMemory) GetPollResult(pollID string) ([][]int, []string, []string,
 	return p.Data, p.Names, p.Comments, p.IDs, nil
 }
 
+// GetAnswerTimes returns the time of creation and last modification of all answers of a poll, identified by answer ID.
+func (fm *FileMemory) GetAnswerTimes(pollID string) (map[string]time.Time, map[string]time.Time, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, nil, ErrFileMemoryNotActive
+	}
+
+	err := fm.testload(pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+
+	created := make(map[string]time.Time, len(p.IDs))
+	modified := make(map[string]time.Time, len(p.IDs))
+	for i := range p.IDs {
+		created[p.IDs[i]] = p.Created[i]
+		modified[p.IDs[i]] = p.Modified[i]
+	}
+	return created, modified, nil
+}
+
+// SaveConsent records the time and version of the consent given with an answer.
+func (fm *FileMemory) SaveConsent(pollID, answerID string, t time.Time, version string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+
+	for i := range p.IDs {
+		if p.IDs[i] == answerID {
+			if p.Consents == nil {
+				p.Consents = make(map[string]FileMemoryConsent)
+			}
+			p.Consents[answerID] = FileMemoryConsent{Time: t, Version: version}
+			p.LastAccess = time.Now()
+			fm.memory[pollID] = p
+			return nil
+		}
+	}
+	return ErrFileMemoryInvalidID
+}
+
+// GetConsent returns the time and version of the consent given with an answer.
+// The zero time is returned if no consent is recorded.
+func (fm *FileMemory) GetConsent(pollID, answerID string) (time.Time, string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return time.Time{}, "", ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+
+	c := p.Consents[answerID]
+	return c.Time, c.Version, nil
+}
+
+// SaveAnswerMail saves the email address given with an answer. An empty address removes it.
+func (fm *FileMemory) SaveAnswerMail(pollID, answerID, mail string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+
+	for i := range p.IDs {
+		if p.IDs[i] == answerID {
+			if mail == "" {
+				delete(p.Mails, answerID)
+			} else {
+				if p.Mails == nil {
+					p.Mails = make(map[string]string)
+				}
+				p.Mails[answerID] = mail
+			}
+			p.LastAccess = time.Now()
+			fm.memory[pollID] = p
+			return nil
+		}
+	}
+	return ErrFileMemoryInvalidID
+}
+
+// GetAnswerMails returns the email addresses given with the answers of a poll as a map answer ID -> address.
+// Answers without an address are not contained.
+func (fm *FileMemory) GetAnswerMails(pollID string) (map[string]string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+
+	mails := make(map[string]string, len(p.Mails))
+	for k, v := range p.Mails {
+		mails[k] = v
+	}
+	return mails, nil
+}
+
 // GetSinglePollResult returns a single results of a poll identified by ID.
 func (fm *FileMemory) GetSinglePollResult(pollID, answerID string) ([]int, string, string, error) {
 	fm.l.Lock()
@@ -253,6 +507,46 @@ func (fm *FileMemory) DeleteAnswer(pollID, answerID string) error {
 			p.Comments = append(p.Comments[:i], p.Comments[i+1:]...)
 			p.Change = append(p.Change[:i], p.Change[i+1:]...)
 			p.IDs = append(p.IDs[:i], p.IDs[i+1:]...)
+			p.Created = append(p.Created[:i], p.Created[i+1:]...)
+			p.Modified = append(p.Modified[:i], p.Modified[i+1:]...)
+			delete(p.Endorsements, answerID)
+			delete(p.Consents, answerID)
+			delete(p.Mails, answerID)
+			p.LastActivity = p.LastAccess
+			fm.memory[pollID] = p
+			return nil
+		}
+	}
+	return ErrFileMemoryInvalidID
+}
+
+// EndorseAnswer increases the number of endorsements of a single answer identified by ID.
+func (fm *FileMemory) EndorseAnswer(pollID, answerID string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+
+	for i := range p.IDs {
+		if p.IDs[i] == answerID {
+			if p.Endorsements == nil {
+				p.Endorsements = make(map[string]int)
+			}
+			p.Endorsements[answerID]++
+			p.LastAccess = time.Now()
+			p.LastActivity = p.LastAccess
 			fm.memory[pollID] = p
 			return nil
 		}
@@ -260,6 +554,35 @@ func (fm *FileMemory) DeleteAnswer(pollID, answerID string) error {
 	return ErrFileMemoryInvalidID
 }
 
+// GetEndorsements returns the number of endorsements of all answers of a poll.
+// Answers without endorsements might be missing.
+func (fm *FileMemory) GetEndorsements(pollID string) (map[string]int, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+
+	e := make(map[string]int, len(p.Endorsements))
+	for k, v := range p.Endorsements {
+		e[k] = v
+	}
+	return e, nil
+}
+
 // SavePollConfig saves the poll configuration.
 func (fm *FileMemory) SavePollConfig(pollID string, config []byte) error {
 	fm.l.Lock()
@@ -280,6 +603,10 @@ func (fm *FileMemory) SavePollConfig(pollID string, config []byte) error {
 	p := fm.memory[pollID]
 	p.Config = config
 	p.LastAccess = time.Now()
+	p.LastActivity = p.LastAccess
+	if p.PollCreated.IsZero() {
+		p.PollCreated = p.LastAccess
+	}
 	fm.memory[pollID] = p
 	return nil
 }
@@ -315,46 +642,1155 @@ func (fm *FileMemory) SavePollCreator(pollID, name string) error {
 	if !fm.active {
 		return ErrFileMemoryNotActive
 	}
-	err := fm.testload(pollID)
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	p.Creator = name
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetPollCreator returns the poll creator.
+func (fm *FileMemory) GetPollCreator(pollID string) (string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return "", ErrFileMemoryNotActive
+	}
+
+	err := fm.testload(pollID)
+	if err != nil {
+		return "", err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return "", err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+	return p.Creator, nil
+
+}
+
+// SavePollOrganizers sets the co-organizers of the poll, replacing all previous co-organizers.
+func (fm *FileMemory) SavePollOrganizers(pollID string, names []string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	p.Organizers = append([]string(nil), names...)
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetPollOrganizers returns the co-organizers of the poll.
+func (fm *FileMemory) GetPollOrganizers(pollID string) ([]string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+
+	err := fm.testload(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+	return append([]string(nil), p.Organizers...), nil
+}
+
+// GetLastActivity returns the time of the last change to the poll configuration or answers.
+// The zero time is returned if the time is not known.
+func (fm *FileMemory) GetLastActivity(pollID string) (time.Time, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return time.Time{}, ErrFileMemoryNotActive
+	}
+
+	err := fm.testload(pollID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+	return p.LastActivity, nil
+}
+
+// GetPollsByCreator returns the IDs of all polls which are not deleted and were created by the given creator.
+func (fm *FileMemory) GetPollsByCreator(name string) ([]string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+
+	polls := make([]string, 0)
+	if name == "" {
+		return polls, nil
+	}
+
+	for k := range fm.memory {
+		if !fm.memory[k].Deleted && fm.memory[k].Config != nil && fm.memory[k].Creator == name {
+			polls = append(polls, fm.getExternalID(k))
+		}
+	}
+
+	// Polls which are currently not in memory must be read from disk
+	dir, err := os.Open(fm.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	files, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	for f := range files {
+		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+			continue
+		}
+		if _, ok := fm.memory[files[f].Name()]; ok {
+			continue
+		}
+		fmpr, err := fm.load(files[f].Name())
+		if err != nil {
+			return nil, err
+		}
+		if !fmpr.Deleted && fmpr.Config != nil && fmpr.Creator == name {
+			polls = append(polls, fm.getExternalID(files[f].Name()))
+		}
+	}
+
+	sort.Strings(polls)
+	return polls, nil
+}
+
+// SaveDiscussionEntry adds an entry to the discussion of a poll.
+func (fm *FileMemory) SaveDiscussionEntry(pollID, name, text string) (string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return "", ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return "", err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return "", err
+	}
+
+	p := fm.memory[pollID]
+	p.EntryCounter++
+	id := fmt.Sprintf("%d-%s", p.EntryCounter, fm.getRandomID())
+	p.LastAccess = time.Now()
+	p.Discussion = append(p.Discussion, FileMemoryDiscussionEntry{ID: id, Name: name, Text: text, Time: p.LastAccess})
+	p.LastActivity = p.LastAccess
+	fm.memory[pollID] = p
+	return id, nil
+}
+
+// GetDiscussion returns the discussion of a poll.
+func (fm *FileMemory) GetDiscussion(pollID string) ([]string, []string, []time.Time, []string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, nil, nil, nil, ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+
+	names := make([]string, len(p.Discussion))
+	texts := make([]string, len(p.Discussion))
+	times := make([]time.Time, len(p.Discussion))
+	ids := make([]string, len(p.Discussion))
+	for i := range p.Discussion {
+		names[i] = p.Discussion[i].Name
+		texts[i] = p.Discussion[i].Text
+		times[i] = p.Discussion[i].Time
+		ids[i] = p.Discussion[i].ID
+	}
+	return names, texts, times, ids, nil
+}
+
+// DeleteDiscussionEntry deletes a single entry of the discussion identified by ID.
+func (fm *FileMemory) DeleteDiscussionEntry(pollID, entryID string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+
+	for i := range p.Discussion {
+		if p.Discussion[i].ID == entryID {
+			p.LastAccess = time.Now()
+			p.LastActivity = p.LastAccess
+			p.Discussion = append(p.Discussion[:i], p.Discussion[i+1:]...)
+			fm.memory[pollID] = p
+			return nil
+		}
+	}
+	return ErrFileMemoryInvalidID
+}
+
+// PseudonymiseAnswers replaces the names of all answers last modified before the given time with a pseudonym and removes their comments.
+// The names in the activity log of the poll are replaced as well. Answers without a known modification time are not changed. It returns the number of changed answers.
+func (fm *FileMemory) PseudonymiseAnswers(before time.Time, pseudonym func(pollID, answerID string) string) (int, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return 0, ErrFileMemoryNotActive
+	}
+
+	pseudonymise := func(ID string, p *FileMemoryPollResult) int {
+		changed := 0
+		for i := range p.IDs {
+			if p.Modified[i].IsZero() || !p.Modified[i].Before(before) {
+				continue
+			}
+			name := pseudonym(fm.getExternalID(ID), p.IDs[i])
+			if p.Names[i] == name && p.Comments[i] == "" && p.Mails[p.IDs[i]] == "" {
+				continue
+			}
+			p.Names[i] = name
+			p.Comments[i] = ""
+			delete(p.Mails, p.IDs[i])
+			for e := range p.Events {
+				if p.Events[e].AnswerID == p.IDs[i] {
+					p.Events[e].Actor = name
+				}
+			}
+			changed++
+		}
+		return changed
+	}
+
+	changed := 0
+	for k := range fm.memory {
+		p := fm.memory[k]
+		if p.Deleted || p.Config == nil {
+			continue
+		}
+		if c := pseudonymise(k, &p); c != 0 {
+			fm.memory[k] = p
+			changed += c
+		}
+	}
+
+	// Polls which are currently not in memory must be read from disk
+	dir, err := os.Open(fm.Path)
+	if err != nil {
+		return changed, err
+	}
+	defer dir.Close()
+
+	files, err := dir.Readdir(-1)
+	if err != nil {
+		return changed, err
+	}
+
+	for f := range files {
+		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+			continue
+		}
+		if _, ok := fm.memory[files[f].Name()]; ok {
+			continue
+		}
+		fmpr, err := fm.load(files[f].Name())
+		if err != nil {
+			return changed, err
+		}
+		if fmpr.Deleted || fmpr.Config == nil {
+			continue
+		}
+		c := pseudonymise(files[f].Name(), &fmpr)
+		if c == 0 {
+			continue
+		}
+		// Write back directly so the memory limit is not exceeded
+		fm.memory[files[f].Name()] = fmpr
+		err = fm.save(files[f].Name())
+		delete(fm.memory, files[f].Name())
+		if err != nil {
+			return changed, err
+		}
+		changed += c
+	}
+
+	return changed, nil
+}
+
+// SetReminder sets the time at which a reminder for the poll is due. The zero time removes the reminder.
+func (fm *FileMemory) SetReminder(pollID string, t time.Time) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	p.Reminder = t
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetDueReminders returns the IDs of all polls which are not deleted and have a reminder due until the given time.
+func (fm *FileMemory) GetDueReminders(until time.Time) ([]string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+
+	due := func(fmpr FileMemoryPollResult) bool {
+		return !fmpr.Deleted && fmpr.Config != nil && !fmpr.Reminder.IsZero() && !fmpr.Reminder.After(until)
+	}
+
+	polls := make([]string, 0)
+	for k := range fm.memory {
+		if due(fm.memory[k]) {
+			polls = append(polls, fm.getExternalID(k))
+		}
+	}
+
+	// Polls which are currently not in memory must be read from disk
+	dir, err := os.Open(fm.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	files, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	for f := range files {
+		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+			continue
+		}
+		if _, ok := fm.memory[files[f].Name()]; ok {
+			continue
+		}
+		fmpr, err := fm.load(files[f].Name())
+		if err != nil {
+			return nil, err
+		}
+		if due(fmpr) {
+			polls = append(polls, fm.getExternalID(files[f].Name()))
+		}
+	}
+
+	sort.Strings(polls)
+	return polls, nil
+}
+
+// SetFollowUp sets the time at which a follow-up poll for the poll is due. The zero time removes the follow-up.
+func (fm *FileMemory) SetFollowUp(pollID string, t time.Time) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	p.FollowUp = t
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetDueFollowUps returns the IDs of all polls which are not deleted and have a follow-up poll due until the given time.
+func (fm *FileMemory) GetDueFollowUps(until time.Time) ([]string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+
+	due := func(fmpr FileMemoryPollResult) bool {
+		return !fmpr.Deleted && fmpr.Config != nil && !fmpr.FollowUp.IsZero() && !fmpr.FollowUp.After(until)
+	}
+
+	polls := make([]string, 0)
+	for k := range fm.memory {
+		if due(fm.memory[k]) {
+			polls = append(polls, fm.getExternalID(k))
+		}
+	}
+
+	// Polls which are currently not in memory must be read from disk
+	dir, err := os.Open(fm.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	files, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	for f := range files {
+		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+			continue
+		}
+		if _, ok := fm.memory[files[f].Name()]; ok {
+			continue
+		}
+		fmpr, err := fm.load(files[f].Name())
+		if err != nil {
+			return nil, err
+		}
+		if due(fmpr) {
+			polls = append(polls, fm.getExternalID(files[f].Name()))
+		}
+	}
+
+	sort.Strings(polls)
+	return polls, nil
+}
+
+// SetExpiry sets the time at which the poll expires. The zero time removes the expiry.
+func (fm *FileMemory) SetExpiry(pollID string, t time.Time) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	p.Expiry = t
+	fm.memory[pollID] = p
+	return nil
+}
+
+// SaveAggregate stores the precomputed aggregate of the poll. nil removes the aggregate.
+func (fm *FileMemory) SaveAggregate(pollID string, aggregate []byte) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	p.Aggregate = aggregate
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetAggregate returns the precomputed aggregate of the poll or nil if none is stored.
+func (fm *FileMemory) GetAggregate(pollID string) ([]byte, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+	if len(p.Aggregate) == 0 {
+		return nil, nil
+	}
+	return p.Aggregate, nil
+}
+
+// GetExpiredPolls returns the IDs of all polls which are not deleted and expire until the given time.
+func (fm *FileMemory) GetExpiredPolls(until time.Time) ([]string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+
+	due := func(fmpr FileMemoryPollResult) bool {
+		return !fmpr.Deleted && fmpr.Config != nil && !fmpr.Expiry.IsZero() && !fmpr.Expiry.After(until)
+	}
+
+	polls := make([]string, 0)
+	for k := range fm.memory {
+		if due(fm.memory[k]) {
+			polls = append(polls, fm.getExternalID(k))
+		}
+	}
+
+	// Polls which are currently not in memory must be read from disk
+	dir, err := os.Open(fm.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	files, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	for f := range files {
+		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+			continue
+		}
+		if _, ok := fm.memory[files[f].Name()]; ok {
+			continue
+		}
+		fmpr, err := fm.load(files[f].Name())
+		if err != nil {
+			return nil, err
+		}
+		if due(fmpr) {
+			polls = append(polls, fm.getExternalID(files[f].Name()))
+		}
+	}
+
+	sort.Strings(polls)
+	return polls, nil
+}
+
+// GetPollsCreatedBefore returns the IDs of all polls which are not deleted and were created before the given time.
+// For polls created by older versions, the time of the last activity is used instead.
+func (fm *FileMemory) GetPollsCreatedBefore(before time.Time) ([]string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+
+	old := func(fmpr FileMemoryPollResult) bool {
+		if fmpr.Deleted || fmpr.Config == nil {
+			return false
+		}
+		created := fmpr.PollCreated
+		if created.IsZero() {
+			created = fmpr.LastActivity
+		}
+		return created.Before(before)
+	}
+
+	polls := make([]string, 0)
+	for k := range fm.memory {
+		if old(fm.memory[k]) {
+			polls = append(polls, fm.getExternalID(k))
+		}
+	}
+
+	// Polls which are currently not in memory must be read from disk
+	dir, err := os.Open(fm.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	files, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	for f := range files {
+		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+			continue
+		}
+		if _, ok := fm.memory[files[f].Name()]; ok {
+			continue
+		}
+		fmpr, err := fm.load(files[f].Name())
+		if err != nil {
+			return nil, err
+		}
+		if old(fmpr) {
+			polls = append(polls, fm.getExternalID(files[f].Name()))
+		}
+	}
+
+	sort.Strings(polls)
+	return polls, nil
+}
+
+// ListPolls returns the IDs of all polls which are not deleted.
+func (fm *FileMemory) ListPolls() ([]string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+
+	polls := make([]string, 0)
+	for k := range fm.memory {
+		if !fm.memory[k].Deleted && fm.memory[k].Config != nil {
+			polls = append(polls, fm.getExternalID(k))
+		}
+	}
+
+	// Polls which are currently not in memory must be read from disk
+	dir, err := os.Open(fm.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	files, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	for f := range files {
+		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+			continue
+		}
+		if _, ok := fm.memory[files[f].Name()]; ok {
+			continue
+		}
+		fmpr, err := fm.load(files[f].Name())
+		if err != nil {
+			return nil, err
+		}
+		if !fmpr.Deleted && fmpr.Config != nil {
+			polls = append(polls, fm.getExternalID(files[f].Name()))
+		}
+	}
+
+	sort.Strings(polls)
+	return polls, nil
+}
+
+// GetPollStats returns the number of answers and the time of the last change to the poll configuration or answers.
+func (fm *FileMemory) GetPollStats(pollID string) (int, time.Time, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return 0, time.Time{}, ErrFileMemoryNotActive
+	}
+
+	err := fm.testload(pollID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+	return len(p.Data), p.LastActivity, nil
+}
+
+// SaveReport adds an abuse report to a poll.
+func (fm *FileMemory) SaveReport(pollID, reason string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	if p.Config == nil || p.Deleted {
+		return registry.ErrPollNotAvailable
+	}
+	p.LastAccess = time.Now()
+	p.Reports = append(p.Reports, FileMemoryReport{Reason: reason, Time: p.LastAccess})
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetReports returns all abuse reports of a poll.
+func (fm *FileMemory) GetReports(pollID string) ([]string, []time.Time, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, nil, ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+
+	reasons := make([]string, len(p.Reports))
+	times := make([]time.Time, len(p.Reports))
+	for i := range p.Reports {
+		reasons[i] = p.Reports[i].Reason
+		times[i] = p.Reports[i].Time
+	}
+	return reasons, times, nil
+}
+
+// DeleteReports removes all abuse reports of a poll.
+func (fm *FileMemory) DeleteReports(pollID string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	p.Reports = nil
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetReportedPolls returns the IDs of all polls which are not deleted and have at least one abuse report.
+func (fm *FileMemory) GetReportedPolls() ([]string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, ErrFileMemoryNotActive
+	}
+
+	reported := func(fmpr FileMemoryPollResult) bool {
+		return !fmpr.Deleted && fmpr.Config != nil && len(fmpr.Reports) > 0
+	}
+
+	polls := make([]string, 0)
+	for k := range fm.memory {
+		if reported(fm.memory[k]) {
+			polls = append(polls, fm.getExternalID(k))
+		}
+	}
+
+	// Polls which are currently not in memory must be read from disk
+	dir, err := os.Open(fm.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	files, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	for f := range files {
+		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+			continue
+		}
+		if _, ok := fm.memory[files[f].Name()]; ok {
+			continue
+		}
+		fmpr, err := fm.load(files[f].Name())
+		if err != nil {
+			return nil, err
+		}
+		if reported(fmpr) {
+			polls = append(polls, fm.getExternalID(files[f].Name()))
+		}
+	}
+
+	sort.Strings(polls)
+	return polls, nil
+}
+
+// SavePollEvent adds an entry to the activity log of a poll. answerID is empty if the event does not belong to an answer.
+func (fm *FileMemory) SavePollEvent(pollID, event, answerID, actor string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	if p.Config == nil || p.Deleted {
+		return registry.ErrPollNotAvailable
+	}
+	p.LastAccess = time.Now()
+	p.Events = append(p.Events, FileMemoryEvent{Event: event, AnswerID: answerID, Actor: actor, Time: p.LastAccess})
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetPollEvents returns the activity log of a poll, oldest entry first.
+func (fm *FileMemory) GetPollEvents(pollID string) ([]string, []string, []string, []time.Time, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, nil, nil, nil, ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+
+	events := make([]string, len(p.Events))
+	answerIDs := make([]string, len(p.Events))
+	actors := make([]string, len(p.Events))
+	times := make([]time.Time, len(p.Events))
+	for i := range p.Events {
+		events[i] = p.Events[i].Event
+		answerIDs[i] = p.Events[i].AnswerID
+		actors[i] = p.Events[i].Actor
+		times[i] = p.Events[i].Time
+	}
+	return events, answerIDs, actors, times, nil
+}
+
+// AddPollAccess adds to the access counts of a poll.
+func (fm *FileMemory) AddPollAccess(pollID string, views, opens, uniqueOpens int) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	if p.Config == nil || p.Deleted {
+		return registry.ErrPollNotAvailable
+	}
+	p.LastAccess = time.Now()
+	p.Access.Views += views
+	p.Access.Opens += opens
+	p.Access.UniqueOpens += uniqueOpens
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetPollAccess returns the access counts of a poll.
+func (fm *FileMemory) GetPollAccess(pollID string) (int, int, int, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return 0, 0, 0, ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+	return p.Access.Views, p.Access.Opens, p.Access.UniqueOpens, nil
+}
+
+// SaveInvitation adds an invitation to a poll.
+func (fm *FileMemory) SaveInvitation(pollID, token, name string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	if p.Config == nil || p.Deleted {
+		return registry.ErrPollNotAvailable
+	}
+	p.LastAccess = time.Now()
+	p.Invitations = append(p.Invitations, FileMemoryInvitation{Token: token, Name: name})
+	fm.memory[pollID] = p
+	return nil
+}
+
+// GetInvitations returns all invitations of a poll in the order they were added.
+func (fm *FileMemory) GetInvitations(pollID string) ([]string, []string, []string, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return nil, nil, nil, ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	p := fm.memory[pollID]
+	p.LastAccess = time.Now()
+	fm.memory[pollID] = p
+
+	tokens := make([]string, len(p.Invitations))
+	names := make([]string, len(p.Invitations))
+	answerIDs := make([]string, len(p.Invitations))
+	for i := range p.Invitations {
+		tokens[i] = p.Invitations[i].Token
+		names[i] = p.Invitations[i].Name
+		answerIDs[i] = p.Invitations[i].AnswerID
+	}
+	return tokens, names, answerIDs, nil
+}
+
+// SetInvitationAnswer links an invitation to an answer if it is currently linked to previousAnswerID.
+func (fm *FileMemory) SetInvitationAnswer(pollID, token, previousAnswerID, answerID string) error {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return ErrFileMemoryNotActive
+	}
+	err := fm.testload(pollID)
+	if err != nil {
+		return err
+	}
+
+	pollID, err = fm.getInternalID(pollID)
+	if err != nil {
+		return err
+	}
+
+	p := fm.memory[pollID]
+	for i := range p.Invitations {
+		if p.Invitations[i].Token != token {
+			continue
+		}
+		if p.Invitations[i].AnswerID != previousAnswerID {
+			return registry.ErrInvitationChanged
+		}
+		p.LastAccess = time.Now()
+		p.Invitations[i].AnswerID = answerID
+		fm.memory[pollID] = p
+		return nil
+	}
+	return registry.ErrInvitationChanged
+}
+
+// GetStorageUsage returns the number of polls and the size of all poll files in bytes.
+// Polls which are only in memory are not included in the size. Deleted polls count until the next garbage collection.
+func (fm *FileMemory) GetStorageUsage() (int, int64, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return 0, 0, ErrFileMemoryNotActive
+	}
+
+	dir, err := os.Open(fm.Path)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
+	defer dir.Close()
 
-	pollID, err = fm.getInternalID(pollID)
+	files, err := dir.Readdir(-1)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
-	p := fm.memory[pollID]
-	p.Creator = name
-	p.LastAccess = time.Now()
-	fm.memory[pollID] = p
-	return nil
+	polls := 0
+	var size int64
+	onDisk := make(map[string]bool, len(files))
+	for f := range files {
+		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+			continue
+		}
+		onDisk[files[f].Name()] = true
+		size += files[f].Size()
+		if m, ok := fm.memory[files[f].Name()]; ok && m.Deleted {
+			continue
+		}
+		polls++
+	}
+	for k := range fm.memory {
+		if !onDisk[k] && !fm.memory[k].Deleted && fm.memory[k].Config != nil {
+			polls++
+		}
+	}
+	return polls, size, nil
 }
 
-// GetPollCreator returns the poll creator.
-func (fm *FileMemory) GetPollCreator(pollID string) (string, error) {
+// HealthCheck tests whether files can be written to Path.
+// The lock is held so that the test file is never seen as a poll by other methods.
+func (fm *FileMemory) HealthCheck() error {
 	fm.l.Lock()
 	defer fm.l.Unlock()
 	if !fm.active {
-		return "", ErrFileMemoryNotActive
+		return ErrFileMemoryNotActive
 	}
 
-	err := fm.testload(pollID)
+	f, err := os.CreateTemp(fm.Path, ".health-*")
 	if err != nil {
-		return "", err
+		return err
 	}
-
-	pollID, err = fm.getInternalID(pollID)
+	defer os.Remove(f.Name())
+	_, err = f.Write([]byte("health"))
 	if err != nil {
-		return "", err
+		f.Close()
+		return err
 	}
-
-	p := fm.memory[pollID]
-	p.LastAccess = time.Now()
-	fm.memory[pollID] = p
-	return p.Creator, nil
-
+	return f.Close()
 }
 
 // MarkPollDeleted marks a poll as deleted. It is not deleted imidiately, but on next garbage collect.
@@ -411,10 +1847,11 @@ func (fm *FileMemory) GetChange(pollID, answerID string) (string, error) {
 }
 
 // RunGC runs the garbage collection and removes deleted polls.
+// Poll files are processed in batches of GCBatchSize by GCWorkers workers, so other requests are not blocked during the gc.
 func (fm *FileMemory) RunGC() error {
 	fm.l.Lock()
-	defer fm.l.Unlock()
 	if !fm.active {
+		fm.l.Unlock()
 		return ErrFileMemoryNotActive
 	}
 
@@ -423,49 +1860,144 @@ func (fm *FileMemory) RunGC() error {
 		if fm.memory[k].Deleted {
 			err := fm.save(k)
 			if err != nil {
+				fm.l.Unlock()
 				return err
 			}
 			delete(fm.memory, k)
 		}
 	}
+	fm.l.Unlock()
 
-	// Test all files
+	// Test all files. The global lock is only held while a candidate for removal is verified and removed, so requests are served while the gc is running.
 	dir, err := os.Open(fm.Path)
 	if err != nil {
 		return err
 	}
-	defer dir.Close()
-
-	files, err := dir.Readdir(-1)
+	files, err := dir.Readdirnames(-1)
+	dir.Close()
 	if err != nil {
 		return err
 	}
 
+	batches := make(chan []string)
+	var deleted int
+	var firstErr error
+	var resultLock sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < fm.gcWorkers(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				d, err := fm.gcBatch(batch)
+				resultLock.Lock()
+				deleted += d
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				resultLock.Unlock()
+				if fm.GCBatchPause > 0 {
+					time.Sleep(time.Duration(fm.GCBatchPause) * time.Millisecond)
+				}
+			}
+		}()
+	}
+
+	batchSize := fm.gcBatchSize()
+	for start := 0; start < len(files); start += batchSize {
+		end := start + batchSize
+		if end > len(files) {
+			end = len(files)
+		}
+		batches <- files[start:end]
+	}
+	close(batches)
+	wg.Wait()
+
+	log.Printf("filememory: gc removed %d resources from disc", deleted)
+
+	return firstErr
+}
+
+// gcWorkers returns the number of batches processed in parallel by the gc.
+func (fm *FileMemory) gcWorkers() int {
+	if fm.GCWorkers <= 0 {
+		return 1
+	}
+	return fm.GCWorkers
+}
+
+// gcBatchSize returns the number of files processed by the gc at once.
+func (fm *FileMemory) gcBatchSize() int {
+	if fm.GCBatchSize <= 0 {
+		return 100
+	}
+	return fm.GCBatchSize
+}
+
+// gcBatch removes all deleted polls in the batch from disc and returns the number of removed files.
+// Files are read without holding the global lock. Files which might be removed are read again while holding the lock, since they could have changed in between.
+// Errors do not stop the batch, the first error is returned.
+func (fm *FileMemory) gcBatch(batch []string) (int, error) {
 	deleted := 0
+	var firstErr error
 
-	for f := range files {
-		if files[f].IsDir() || !files[f].Mode().IsRegular() {
+	for _, name := range batch {
+		info, err := os.Lstat(filepath.Join(fm.Path, name))
+		if err != nil || !info.Mode().IsRegular() {
 			continue
 		}
-		fmpr, err := fm.load(files[f].Name())
-		if err != nil {
-			return err
+		fmpr, err := fm.load(name)
+		// A failed read might be caused by a concurrent write and is checked again
+		if err == nil && !fmpr.Deleted && fmpr.Config != nil {
+			continue
 		}
-		// File is deleted if either it is marked as deleted or there was never a configuration written to it (e.g. never a poll created).
-		// Second check is included for old PollGo versions
-		if fmpr.Deleted || fmpr.Config == nil {
-			// Delete file
-			err := os.Remove(filepath.Join(fm.Path, files[f].Name()))
-			if err != nil {
-				return err
+
+		d, err := fm.gcFile(name)
+		if err != nil {
+			if errors.Is(err, ErrFileMemoryNotActive) {
+				return deleted, err
+			}
+			if firstErr == nil {
+				firstErr = err
 			}
+			continue
+		}
+		if d {
 			deleted++
 		}
 	}
+	return deleted, firstErr
+}
 
-	log.Printf("filememory: gc removed %d resources from disc", deleted)
+// gcFile removes the file of the poll if it is deleted. It returns whether the file was removed.
+func (fm *FileMemory) gcFile(name string) (bool, error) {
+	fm.l.Lock()
+	defer fm.l.Unlock()
+	if !fm.active {
+		return false, ErrFileMemoryNotActive
+	}
 
-	return nil
+	// Polls in memory are in use and are newer than the file
+	if _, ok := fm.memory[name]; ok {
+		return false, nil
+	}
+
+	fmpr, err := fm.load(name)
+	if err != nil {
+		return false, err
+	}
+	// File is deleted if either it is marked as deleted or there was never a configuration written to it (e.g. never a poll created).
+	// Second check is included for old PollGo versions
+	if !fmpr.Deleted && fmpr.Config != nil {
+		return false, nil
+	}
+	err = os.Remove(filepath.Join(fm.Path, name))
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // LoadConfig loads the configuration of the FileMemory from JSON encoded data.
@@ -490,6 +2022,16 @@ func (fm *FileMemory) LoadConfig(data []byte) error {
 		return errors.New("filememory: ClearInterval must be positive or zero")
 	}
 
+	if fm.GCBatchSize < 0 {
+		return errors.New("filememory: GCBatchSize must be positive or zero")
+	}
+	if fm.GCWorkers < 0 {
+		return errors.New("filememory: GCWorkers must be positive or zero")
+	}
+	if fm.GCBatchPause < 0 {
+		return errors.New("filememory: GCBatchPause must be positive or zero")
+	}
+
 	if fm.ClearAfterRatio < 0.0 || fm.ClearAfterRatio > 1.0 {
 		return errors.New("filememory: ClearAfterRatio must be between 0.0 and 1.0")
 	}
@@ -503,6 +2045,11 @@ func (fm *FileMemory) LoadConfig(data []byte) error {
 		return err
 	}
 
+	err = fm.recoverJournal()
+	if err != nil {
+		return err
+	}
+
 	go fm.worker()
 	fm.active = true
 	return nil
@@ -557,10 +2104,11 @@ func (fm *FileMemory) worker() {
 	fm.l.Unlock()
 	clear := time.NewTicker(durationClear)
 	defer clear.Stop()
-	var sync time.Ticker
+	var syncC <-chan time.Time // nil channel blocks forever if syncing is disabled
 	if durationSync != 0 {
-		sync = *time.NewTicker(durationSync)
+		sync := time.NewTicker(durationSync)
 		defer sync.Stop()
+		syncC = sync.C
 	}
 	for {
 		select {
@@ -593,7 +2141,7 @@ func (fm *FileMemory) worker() {
 				}
 				log.Printf("filememory: freed %d resources from memory", i)
 			}()
-		case <-sync.C:
+		case <-syncC:
 			func() {
 				fm.l.Lock()
 				defer fm.l.Unlock()
@@ -607,12 +2155,18 @@ func (fm *FileMemory) worker() {
 			func() {
 				fm.l.Lock()
 				defer fm.l.Unlock()
+				clean := true
 				for k := range fm.memory {
 					err := fm.save(k)
 					if err != nil {
+						clean = false
 						log.Printf("filememory: error saving %s: %s", k, err.Error())
 					}
 				}
+				if clean {
+					// Everything is on disc, the next start does not need to check for unfinished writes
+					os.Remove(filepath.Join(fm.Path, fileMemoryJournalDir, fileMemoryRunningMarker))
+				}
 				fm.memory = make(map[string]FileMemoryPollResult, 0)
 				fm.active = false
 			}()
@@ -622,6 +2176,77 @@ func (fm *FileMemory) worker() {
 	}
 }
 
+// recoverJournal reconciles writes interrupted by an unclean shutdown and logs a summary for the operator.
+// Completely written polls are moved to their final place, partially written polls are removed (the last completely written version is kept).
+// It must be called before the FileMemory is active.
+func (fm *FileMemory) recoverJournal() error {
+	dir := filepath.Join(fm.Path, fileMemoryJournalDir)
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(filepath.Join(dir, fileMemoryRunningMarker))
+	unclean := err == nil
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	restored := make([]string, 0)
+	discarded := make([]string, 0)
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case e.IsDir() || name == fileMemoryRunningMarker:
+			continue
+		case strings.HasSuffix(name, fileMemoryJournalComplete):
+			ID := strings.TrimSuffix(name, fileMemoryJournalComplete)
+			err = os.Rename(filepath.Join(dir, name), filepath.Join(fm.Path, ID))
+			if err != nil {
+				return err
+			}
+			restored = append(restored, fm.getExternalID(ID))
+		case strings.HasSuffix(name, fileMemoryJournalPartial):
+			err = os.Remove(filepath.Join(dir, name))
+			if err != nil {
+				return err
+			}
+			discarded = append(discarded, fm.getExternalID(strings.TrimSuffix(name, fileMemoryJournalPartial)))
+		default:
+			log.Printf("filememory: unknown file %s in journal", name)
+		}
+	}
+
+	// Files of interrupted health checks would be read as polls
+	health, err := filepath.Glob(filepath.Join(fm.Path, ".health-*"))
+	if err != nil {
+		return err
+	}
+	for i := range health {
+		err = os.Remove(health[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	if unclean || len(restored) != 0 || len(discarded) != 0 {
+		sort.Strings(restored)
+		sort.Strings(discarded)
+		log.Printf("filememory: recovered from unclean shutdown - %d polls restored from journal %v, %d unfinished writes discarded %v, %d health check files removed", len(restored), restored, len(discarded), discarded, len(health))
+		if len(discarded) != 0 {
+			log.Printf("filememory: polls with discarded writes lost changes made since their last sync to disc")
+		}
+	}
+
+	f, err := os.Create(filepath.Join(dir, fileMemoryRunningMarker))
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 // caller has to lock
 func (fm *FileMemory) testload(pollID string) error {
 	pollID, err := fm.getInternalID(pollID)
@@ -665,6 +2290,24 @@ func (fm *FileMemory) load(ID string) (FileMemoryPollResult, error) {
 	var change []string
 	var ids []string
 	var answerCounter int
+	var lastActivity time.Time
+	var discussion []FileMemoryDiscussionEntry
+	var entryCounter int
+	var endorsements map[string]int
+	var reminder time.Time
+	var created []time.Time
+	var modified []time.Time
+	var consents map[string]FileMemoryConsent
+	var pollCreated time.Time
+	var reports []FileMemoryReport
+	var invitations []FileMemoryInvitation
+	var followUp time.Time
+	var mails map[string]string
+	var expiry time.Time
+	var aggregate []byte
+	var events []FileMemoryEvent
+	var access FileMemoryAccess
+	var organizers []string
 	err = dec.Decode(&data)
 	if err != nil && err != io.EOF {
 		return FileMemoryPollResult{LastAccess: time.Now()}, err
@@ -701,6 +2344,78 @@ func (fm *FileMemory) load(ID string) (FileMemoryPollResult, error) {
 	if err != nil && err != io.EOF {
 		return FileMemoryPollResult{LastAccess: time.Now()}, err
 	}
+	err = dec.Decode(&lastActivity)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&discussion)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&entryCounter)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&endorsements)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&reminder)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&created)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&modified)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&consents)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&pollCreated)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&reports)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&invitations)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&followUp)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&mails)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&expiry)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&aggregate)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&events)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&access)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
+	err = dec.Decode(&organizers)
+	if err != nil && err != io.EOF {
+		return FileMemoryPollResult{LastAccess: time.Now()}, err
+	}
 
 	for len(change) < len(names) {
 		change = append(change, "")
@@ -708,6 +2423,12 @@ func (fm *FileMemory) load(ID string) (FileMemoryPollResult, error) {
 	for len(ids) < len(names) {
 		ids = append(ids, "")
 	}
+	for len(created) < len(names) {
+		created = append(created, time.Time{})
+	}
+	for len(modified) < len(names) {
+		modified = append(modified, time.Time{})
+	}
 	fmpr := FileMemoryPollResult{
 		Data:          data,
 		Names:         names,
@@ -719,6 +2440,24 @@ func (fm *FileMemory) load(ID string) (FileMemoryPollResult, error) {
 		Change:        change,
 		IDs:           ids,
 		AnswerCounter: answerCounter,
+		LastActivity:  lastActivity,
+		Discussion:    discussion,
+		EntryCounter:  entryCounter,
+		Endorsements:  endorsements,
+		Reminder:      reminder,
+		Created:       created,
+		Modified:      modified,
+		Consents:      consents,
+		PollCreated:   pollCreated,
+		Reports:       reports,
+		Invitations:   invitations,
+		FollowUp:      followUp,
+		Mails:         mails,
+		Expiry:        expiry,
+		Aggregate:     aggregate,
+		Events:        events,
+		Access:        access,
+		Organizers:    organizers,
 	}
 	return fmpr, nil
 }
@@ -734,16 +2473,36 @@ func (fm *FileMemory) save(ID string) error {
 		return nil
 	}
 
-	// Save poll
-	f, err := os.Create(filepath.Join(fm.Path, ID))
+	// Save poll. The poll is written to the journal first, so an unclean shutdown never leaves a partially written poll behind.
+	journal := filepath.Join(fm.Path, fileMemoryJournalDir, ID)
+	f, err := os.Create(journal + fileMemoryJournalPartial)
 	if err != nil {
 		// some file error
 		return err
 	}
-	defer f.Close()
+	err = encodePoll(f, &p)
+	if err == nil {
+		err = f.Sync()
+	}
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(journal + fileMemoryJournalPartial)
+		return err
+	}
+	err = os.Rename(journal+fileMemoryJournalPartial, journal+fileMemoryJournalComplete)
+	if err != nil {
+		return err
+	}
+	return os.Rename(journal+fileMemoryJournalComplete, filepath.Join(fm.Path, ID))
+}
 
-	enc := gob.NewEncoder(f)
-	err = enc.Encode(&p.Data)
+// encodePoll writes the poll in the format read by load.
+func encodePoll(w io.Writer, p *FileMemoryPollResult) error {
+	enc := gob.NewEncoder(w)
+	err := enc.Encode(&p.Data)
 	if err != nil {
 		return err
 	}
@@ -779,6 +2538,78 @@ func (fm *FileMemory) save(ID string) error {
 	if err != nil {
 		return err
 	}
+	err = enc.Encode(&p.LastActivity)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Discussion)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.EntryCounter)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Endorsements)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Reminder)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Created)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Modified)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Consents)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.PollCreated)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Reports)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Invitations)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.FollowUp)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Mails)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Expiry)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Aggregate)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Events)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Access)
+	if err != nil {
+		return err
+	}
+	err = enc.Encode(&p.Organizers)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -19,6 +19,7 @@ package datasafe
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/gob"
 	"errors"
@@ -60,6 +61,12 @@ type MySQL struct {
 	db  *sql.DB
 }
 
+// updateLastActivity sets the last activity of a poll to the current time.
+func (m *MySQL) updateLastActivity(pollID string) error {
+	_, err := m.db.Exec("UPDATE poll SET lastactivity=? WHERE name=?", time.Now().Unix(), pollID)
+	return err
+}
+
 func (m *MySQL) SavePollResult(pollID, name, comment string, results []int, change string) (string, error) {
 	if m.db == nil {
 		return "", ErrMySQLNotConfigured
@@ -76,7 +83,18 @@ func (m *MySQL) SavePollResult(pollID, name, comment string, results []int, chan
 		return "", fmt.Errorf("mysql: can not convert results: %w", err)
 	}
 	b := buf.Bytes()
-	r, err := m.db.Exec("INSERT INTO result (poll, name, comment, results, `change`) VALUES (?,?,?,?,?)", pollID, name, comment, b, change)
+	now := time.Now().Unix()
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	err = lockAvailablePoll(tx, pollID)
+	if err != nil {
+		return "", err
+	}
+	r, err := tx.Exec("INSERT INTO result (poll, name, comment, results, `change`, created, modified) VALUES (?,?,?,?,?,?,?)", pollID, name, comment, b, change, now, now)
 	if err != nil {
 		return "", err
 	}
@@ -84,6 +102,14 @@ func (m *MySQL) SavePollResult(pollID, name, comment string, results []int, chan
 	if err != nil {
 		return "", err
 	}
+	err = tx.Commit()
+	if err != nil {
+		return "", err
+	}
+	err = m.updateLastActivity(pollID)
+	if err != nil {
+		return "", err
+	}
 	return strconv.FormatInt(lastInserted, 10), nil
 }
 
@@ -109,8 +135,42 @@ func (m *MySQL) OverwritePollResult(pollID, answerID, name, comment string, resu
 		return fmt.Errorf("mysql: can not convert results: %w", err)
 	}
 	b := buf.Bytes()
-	_, err = m.db.Exec("UPDATE result SET name=?, comment=?, results=?, `change`=? WHERE poll=? AND id=?", name, comment, b, change, pollID, id)
-	return err
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailablePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE result SET name=?, comment=?, results=?, `change`=?, modified=? WHERE poll=? AND id=?", name, comment, b, change, time.Now().Unix(), pollID, id)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	return m.updateLastActivity(pollID)
+}
+
+// lockAvailablePoll locks the poll for the transaction so it can not be deleted concurrently.
+// It returns registry.ErrPollNotAvailable if the poll does not exist or is marked as deleted.
+func lockAvailablePoll(tx *sql.Tx, pollID string) error {
+	var deleted sql.NullBool
+	err := tx.QueryRow("SELECT deleted FROM poll WHERE name=? FOR UPDATE", pollID).Scan(&deleted)
+	if err == sql.ErrNoRows {
+		return registry.ErrPollNotAvailable
+	}
+	if err != nil {
+		return err
+	}
+	if deleted.Valid && deleted.Bool {
+		return registry.ErrPollNotAvailable
+	}
+	return nil
 }
 
 func (m *MySQL) GetPollResult(pollID string) ([][]int, []string, []string, []string, error) {
@@ -158,6 +218,169 @@ func (m *MySQL) GetPollResult(pollID string) ([][]int, []string, []string, []str
 	return results, names, comments, ids, nil
 }
 
+// GetAnswerTimes returns the time of creation and last modification of all answers of a poll, identified by answer ID.
+func (m *MySQL) GetAnswerTimes(pollID string) (map[string]time.Time, map[string]time.Time, error) {
+	if m.db == nil {
+		return nil, nil, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return nil, nil, ErrMySQLIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT id, created, modified FROM result WHERE poll=?", pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	created := make(map[string]time.Time)
+	modified := make(map[string]time.Time)
+	for rows.Next() {
+		var id int64
+		var c, mod sql.NullInt64
+		err = rows.Scan(&id, &c, &mod)
+		if err != nil {
+			return nil, nil, err
+		}
+		if c.Valid {
+			created[strconv.FormatInt(id, 10)] = time.Unix(c.Int64, 0)
+		}
+		if mod.Valid {
+			modified[strconv.FormatInt(id, 10)] = time.Unix(mod.Int64, 0)
+		}
+	}
+	return created, modified, nil
+}
+
+// SaveConsent records the time and version of the consent given with an answer.
+func (m *MySQL) SaveConsent(pollID, answerID string, t time.Time, version string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("mysql: can not convert id '%s': %w", answerID, err)
+	}
+
+	r, err := m.db.Exec("UPDATE result SET consenttime=?, consentversion=? WHERE poll=? AND id=?", t.Unix(), version, pollID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrMySQLUnknownID
+	}
+	return nil
+}
+
+// SaveAnswerMail saves the email address given with an answer. An empty address removes it.
+func (m *MySQL) SaveAnswerMail(pollID, answerID, mail string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("mysql: can not convert id '%s': %w", answerID, err)
+	}
+
+	r, err := m.db.Exec("UPDATE result SET mail=? WHERE poll=? AND id=?", sql.NullString{String: mail, Valid: mail != ""}, pollID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrMySQLUnknownID
+	}
+	return nil
+}
+
+// GetAnswerMails returns the email addresses given with the answers of a poll as a map answer ID -> address.
+// Answers without an address are not contained.
+func (m *MySQL) GetAnswerMails(pollID string) (map[string]string, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return nil, ErrMySQLIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT id, mail FROM result WHERE poll=? AND mail IS NOT NULL", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mails := make(map[string]string)
+	for rows.Next() {
+		var id int64
+		var mail string
+		err = rows.Scan(&id, &mail)
+		if err != nil {
+			return nil, err
+		}
+		mails[strconv.FormatInt(id, 10)] = mail
+	}
+	return mails, nil
+}
+
+// GetConsent returns the time and version of the consent given with an answer.
+// The zero time is returned if no consent is recorded.
+func (m *MySQL) GetConsent(pollID, answerID string) (time.Time, string, error) {
+	if m.db == nil {
+		return time.Time{}, "", ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return time.Time{}, "", ErrMySQLIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("mysql: can not convert id '%s': %w", answerID, err)
+	}
+
+	rows, err := m.db.Query("SELECT consenttime, consentversion FROM result WHERE poll=? AND id=?", pollID, id)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return time.Time{}, "", ErrMySQLUnknownID
+	}
+	var t sql.NullInt64
+	var v sql.NullString
+	err = rows.Scan(&t, &v)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if !t.Valid {
+		return time.Time{}, "", nil
+	}
+	return time.Unix(t.Int64, 0), v.String, nil
+}
+
 func (m *MySQL) GetSinglePollResult(pollID, answerID string) ([]int, string, string, error) {
 	if m.db == nil {
 		return nil, "", "", ErrMySQLNotConfigured
@@ -228,7 +451,64 @@ func (m *MySQL) DeleteAnswer(pollID, answerID string) error {
 	if affected > 1 {
 		return fmt.Errorf("mysql: delete for (%s, %d) was too large: %d", pollID, id, affected)
 	}
-	return nil
+	return m.updateLastActivity(pollID)
+}
+
+func (m *MySQL) EndorseAnswer(pollID, answerID string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("mysql: can not convert id '%s': %w", answerID, err)
+	}
+
+	r, err := m.db.Exec("UPDATE result SET endorsements=endorsements+1 WHERE poll=? AND id=?", pollID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrMySQLUnknownID
+	}
+	return m.updateLastActivity(pollID)
+}
+
+func (m *MySQL) GetEndorsements(pollID string) (map[string]int, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return nil, ErrMySQLIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT id, endorsements FROM result WHERE poll=? AND endorsements>0", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	e := make(map[string]int)
+	for rows.Next() {
+		var id int64
+		var c int
+		err = rows.Scan(&id, &c)
+		if err != nil {
+			return nil, err
+		}
+		e[strconv.FormatInt(id, 10)] = c
+	}
+	return e, nil
 }
 
 func (m *MySQL) SavePollConfig(pollID string, config []byte) error {
@@ -240,7 +520,8 @@ func (m *MySQL) SavePollConfig(pollID string, config []byte) error {
 		return ErrMySQLIDtooLong
 	}
 
-	_, err := m.db.Exec("INSERT INTO poll (name, data, deleted) VALUES (?,?,?) ON DUPLICATE KEY UPDATE data=?", pollID, config, false, config)
+	now := time.Now().Unix()
+	_, err := m.db.Exec("INSERT INTO poll (name, data, deleted, lastactivity, created) VALUES (?,?,?,?,?) ON DUPLICATE KEY UPDATE data=?, lastactivity=?", pollID, config, false, now, now, config, now)
 
 	return err
 }
@@ -317,6 +598,89 @@ func (m *MySQL) GetPollCreator(pollID string) (string, error) {
 	return c.String, nil
 }
 
+// SavePollOrganizers sets the co-organizers of the poll, replacing all previous co-organizers.
+func (m *MySQL) SavePollOrganizers(pollID string, names []string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("DELETE FROM organizer WHERE poll=?", pollID)
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		_, err = tx.Exec("INSERT INTO organizer (poll, name) VALUES (?,?)", pollID, n)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPollOrganizers returns the co-organizers of the poll in the order they were added.
+func (m *MySQL) GetPollOrganizers(pollID string) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return nil, ErrMySQLIDtooLong
+	}
+
+	names := make([]string, 0)
+	rows, err := m.db.Query("SELECT name FROM organizer WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n string
+		err = rows.Scan(&n)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+	}
+	return names, nil
+}
+
+func (m *MySQL) GetPollsByCreator(name string) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	polls := make([]string, 0)
+	if name == "" {
+		return polls, nil
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE creator=? AND deleted=? ORDER BY name ASC", name, false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
 func (m *MySQL) MarkPollDeleted(pollID string) error {
 	if m.db == nil {
 		return ErrMySQLNotConfigured
@@ -368,6 +732,756 @@ func (m *MySQL) GetChange(pollID, answerID string) (string, error) {
 	return c.String, nil
 }
 
+func (m *MySQL) GetLastActivity(pollID string) (time.Time, error) {
+	if m.db == nil {
+		return time.Time{}, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return time.Time{}, ErrMySQLIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT lastactivity FROM poll WHERE name=?", pollID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return time.Time{}, ErrMySQLUnknownID
+	}
+	var l sql.NullInt64
+	err = rows.Scan(&l)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !l.Valid {
+		return time.Time{}, nil
+	}
+	return time.Unix(l.Int64, 0), nil
+}
+
+func (m *MySQL) SaveDiscussionEntry(pollID, name, text string) (string, error) {
+	if m.db == nil {
+		return "", ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return "", ErrMySQLIDtooLong
+	}
+
+	r, err := m.db.Exec("INSERT INTO discussion (poll, name, text, time) VALUES (?,?,?,?)", pollID, name, text, time.Now().Unix())
+	if err != nil {
+		return "", err
+	}
+	lastInserted, err := r.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+	err = m.updateLastActivity(pollID)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(lastInserted, 10), nil
+}
+
+func (m *MySQL) GetDiscussion(pollID string) ([]string, []string, []time.Time, []string, error) {
+	if m.db == nil {
+		return nil, nil, nil, nil, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return nil, nil, nil, nil, ErrMySQLIDtooLong
+	}
+
+	names := make([]string, 0)
+	texts := make([]string, 0)
+	times := make([]time.Time, 0)
+	ids := make([]string, 0)
+
+	rows, err := m.db.Query("SELECT id, name, text, time FROM discussion WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n, t string
+		var id, ti int64
+		err = rows.Scan(&id, &n, &t, &ti)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		names = append(names, n)
+		texts = append(texts, t)
+		times = append(times, time.Unix(ti, 0))
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+
+	return names, texts, times, ids, nil
+}
+
+func (m *MySQL) DeleteDiscussionEntry(pollID, entryID string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(entryID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("mysql: can not convert id '%s': %w", entryID, err)
+	}
+
+	r, err := m.db.Exec("DELETE FROM discussion WHERE poll=? AND id=?", pollID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrMySQLUnknownID
+	}
+	return m.updateLastActivity(pollID)
+}
+
+// PseudonymiseAnswers replaces the names of all answers last modified before the given time with a pseudonym and removes their comments.
+// The names in the activity log of the poll are replaced as well. Answers without a known modification time are not changed. It returns the number of changed answers.
+func (m *MySQL) PseudonymiseAnswers(before time.Time, pseudonym func(pollID, answerID string) string) (int, error) {
+	if m.db == nil {
+		return 0, ErrMySQLNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT result.id, result.poll, result.name, result.comment, result.mail FROM result INNER JOIN poll ON result.poll=poll.name WHERE result.modified IS NOT NULL AND result.modified<? AND poll.deleted=?", before.Unix(), false)
+	if err != nil {
+		return 0, err
+	}
+
+	type answer struct {
+		id   int64
+		poll string
+	}
+	update := make([]answer, 0)
+	names := make([]string, 0)
+	for rows.Next() {
+		var a answer
+		var n, c string
+		var mail sql.NullString
+		err = rows.Scan(&a.id, &a.poll, &n, &c, &mail)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		name := pseudonym(a.poll, strconv.FormatInt(a.id, 10))
+		if n == name && c == "" && !mail.Valid {
+			continue
+		}
+		update = append(update, a)
+		names = append(names, name)
+	}
+	rows.Close()
+
+	for i := range update {
+		_, err = m.db.Exec("UPDATE result SET name=?, comment=?, mail=NULL WHERE id=?", names[i], "", update[i].id)
+		if err != nil {
+			return i, err
+		}
+		_, err = m.db.Exec("UPDATE event SET actor=? WHERE poll=? AND answer=?", names[i], update[i].poll, strconv.FormatInt(update[i].id, 10))
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(update), nil
+}
+
+// SetReminder sets the time at which a reminder for the poll is due. The zero time removes the reminder.
+func (m *MySQL) SetReminder(pollID string, t time.Time) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	reminder := sql.NullInt64{Int64: t.Unix(), Valid: !t.IsZero()}
+	_, err := m.db.Exec("UPDATE poll SET reminder=? WHERE name=?", reminder, pollID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDueReminders returns the IDs of all polls which are not deleted and have a reminder due until the given time.
+func (m *MySQL) GetDueReminders(until time.Time) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE reminder IS NOT NULL AND reminder<=? AND deleted=? ORDER BY name ASC", until.Unix(), false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// SetFollowUp sets the time at which a follow-up poll for the poll is due. The zero time removes the follow-up.
+func (m *MySQL) SetFollowUp(pollID string, t time.Time) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	followUp := sql.NullInt64{Int64: t.Unix(), Valid: !t.IsZero()}
+	_, err := m.db.Exec("UPDATE poll SET followup=? WHERE name=?", followUp, pollID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDueFollowUps returns the IDs of all polls which are not deleted and have a follow-up poll due until the given time.
+func (m *MySQL) GetDueFollowUps(until time.Time) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE followup IS NOT NULL AND followup<=? AND deleted=? ORDER BY name ASC", until.Unix(), false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// SetExpiry sets the time at which the poll expires. The zero time removes the expiry.
+func (m *MySQL) SetExpiry(pollID string, t time.Time) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	expiry := sql.NullInt64{Int64: t.Unix(), Valid: !t.IsZero()}
+	_, err := m.db.Exec("UPDATE poll SET expiry=? WHERE name=?", expiry, pollID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveAggregate stores the precomputed aggregate of the poll. nil removes the aggregate.
+func (m *MySQL) SaveAggregate(pollID string, aggregate []byte) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	_, err := m.db.Exec("UPDATE poll SET aggregate=? WHERE name=?", aggregate, pollID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAggregate returns the precomputed aggregate of the poll or nil if none is stored.
+func (m *MySQL) GetAggregate(pollID string) ([]byte, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return nil, ErrMySQLIDtooLong
+	}
+
+	r, err := m.db.Query("SELECT aggregate FROM poll WHERE name=?", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	if !r.Next() {
+		return nil, nil
+	}
+	var aggregate []byte
+	err = r.Scan(&aggregate)
+	if err != nil {
+		return nil, err
+	}
+	if len(aggregate) == 0 {
+		return nil, nil
+	}
+	return aggregate, nil
+}
+
+// GetExpiredPolls returns the IDs of all polls which are not deleted and expire until the given time.
+func (m *MySQL) GetExpiredPolls(until time.Time) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE expiry IS NOT NULL AND expiry<=? AND deleted=? ORDER BY name ASC", until.Unix(), false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// GetPollsCreatedBefore returns the IDs of all polls which are not deleted and were created before the given time.
+// For polls created by older versions, the time of the last activity is used instead.
+func (m *MySQL) GetPollsCreatedBefore(before time.Time) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE COALESCE(created, lastactivity)<? AND deleted=? ORDER BY name ASC", before.Unix(), false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// ListPolls returns the IDs of all polls which are not deleted.
+func (m *MySQL) ListPolls() ([]string, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE deleted=? ORDER BY name ASC", false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// GetPollStats returns the number of answers and the time of the last change to the poll configuration or answers.
+func (m *MySQL) GetPollStats(pollID string) (int, time.Time, error) {
+	if m.db == nil {
+		return 0, time.Time{}, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return 0, time.Time{}, ErrMySQLIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT (SELECT COUNT(*) FROM result WHERE poll=?), lastactivity FROM poll WHERE name=?", pollID, pollID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, time.Time{}, ErrMySQLUnknownID
+	}
+	var answers int
+	var l sql.NullInt64
+	err = rows.Scan(&answers, &l)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if !l.Valid {
+		return answers, time.Time{}, nil
+	}
+	return answers, time.Unix(l.Int64, 0), nil
+}
+
+// SaveReport adds an abuse report to a poll.
+func (m *MySQL) SaveReport(pollID, reason string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailablePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO report (poll, reason, time) VALUES (?,?,?)", pollID, reason, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetReports returns all abuse reports of a poll.
+func (m *MySQL) GetReports(pollID string) ([]string, []time.Time, error) {
+	if m.db == nil {
+		return nil, nil, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return nil, nil, ErrMySQLIDtooLong
+	}
+
+	reasons := make([]string, 0)
+	times := make([]time.Time, 0)
+
+	rows, err := m.db.Query("SELECT reason, time FROM report WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r string
+		var t int64
+		err = rows.Scan(&r, &t)
+		if err != nil {
+			return nil, nil, err
+		}
+		reasons = append(reasons, r)
+		times = append(times, time.Unix(t, 0))
+	}
+	return reasons, times, nil
+}
+
+// DeleteReports removes all abuse reports of a poll.
+func (m *MySQL) DeleteReports(pollID string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	_, err := m.db.Exec("DELETE FROM report WHERE poll=?", pollID)
+	return err
+}
+
+// GetReportedPolls returns the IDs of all polls which are not deleted and have at least one abuse report.
+func (m *MySQL) GetReportedPolls() ([]string, error) {
+	if m.db == nil {
+		return nil, ErrMySQLNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT DISTINCT poll.name FROM poll INNER JOIN report ON report.poll=poll.name WHERE poll.deleted=? ORDER BY poll.name ASC", false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// SavePollEvent adds an entry to the activity log of a poll. answerID is empty if the event does not belong to an answer.
+func (m *MySQL) SavePollEvent(pollID, event, answerID, actor string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailablePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO event (poll, event, answer, actor, time) VALUES (?,?,?,?,?)", pollID, event, answerID, actor, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetPollEvents returns the activity log of a poll, oldest entry first.
+func (m *MySQL) GetPollEvents(pollID string) ([]string, []string, []string, []time.Time, error) {
+	if m.db == nil {
+		return nil, nil, nil, nil, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return nil, nil, nil, nil, ErrMySQLIDtooLong
+	}
+
+	events := make([]string, 0)
+	answerIDs := make([]string, 0)
+	actors := make([]string, 0)
+	times := make([]time.Time, 0)
+
+	rows, err := m.db.Query("SELECT event, answer, actor, time FROM event WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e, a, n string
+		var t int64
+		err = rows.Scan(&e, &a, &n, &t)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		events = append(events, e)
+		answerIDs = append(answerIDs, a)
+		actors = append(actors, n)
+		times = append(times, time.Unix(t, 0))
+	}
+	return events, answerIDs, actors, times, nil
+}
+
+// AddPollAccess adds to the access counts of a poll.
+func (m *MySQL) AddPollAccess(pollID string, views, opens, uniqueOpens int) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailablePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE poll SET views=views+?, opens=opens+?, uniqueopens=uniqueopens+? WHERE name=?", views, opens, uniqueOpens, pollID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetPollAccess returns the access counts of a poll.
+func (m *MySQL) GetPollAccess(pollID string) (int, int, int, error) {
+	if m.db == nil {
+		return 0, 0, 0, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return 0, 0, 0, ErrMySQLIDtooLong
+	}
+
+	r, err := m.db.Query("SELECT views, opens, uniqueopens FROM poll WHERE name=?", pollID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer r.Close()
+
+	if !r.Next() {
+		return 0, 0, 0, nil
+	}
+	var views, opens, uniqueOpens int
+	err = r.Scan(&views, &opens, &uniqueOpens)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return views, opens, uniqueOpens, nil
+}
+
+// SaveInvitation adds an invitation to a poll.
+func (m *MySQL) SaveInvitation(pollID, token, name string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailablePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO invitation (poll, token, name) VALUES (?,?,?)", pollID, token, name)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetInvitations returns all invitations of a poll in the order they were added.
+func (m *MySQL) GetInvitations(pollID string) ([]string, []string, []string, error) {
+	if m.db == nil {
+		return nil, nil, nil, ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return nil, nil, nil, ErrMySQLIDtooLong
+	}
+
+	tokens := make([]string, 0)
+	names := make([]string, 0)
+	answerIDs := make([]string, 0)
+
+	rows, err := m.db.Query("SELECT token, name, answer FROM invitation WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t, n string
+		var a sql.NullString
+		err = rows.Scan(&t, &n, &a)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		tokens = append(tokens, t)
+		names = append(names, n)
+		answerIDs = append(answerIDs, a.String)
+	}
+	return tokens, names, answerIDs, nil
+}
+
+// SetInvitationAnswer links an invitation to an answer if it is currently linked to previousAnswerID.
+func (m *MySQL) SetInvitationAnswer(pollID, token, previousAnswerID, answerID string) error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	if len(pollID) > MySQLMaxLengthID {
+		return ErrMySQLIDtooLong
+	}
+
+	r, err := m.db.Exec("UPDATE invitation SET answer=? WHERE poll=? AND token=? AND COALESCE(answer, '')=?", answerID, pollID, token, previousAnswerID)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return registry.ErrInvitationChanged
+	}
+	return nil
+}
+
+// GetStorageUsage returns the number of polls not marked as deleted and the size of all stored data in bytes.
+func (m *MySQL) GetStorageUsage() (int, int64, error) {
+	if m.db == nil {
+		return 0, 0, ErrMySQLNotConfigured
+	}
+
+	var polls int
+	err := m.db.QueryRow("SELECT COUNT(*) FROM poll WHERE deleted=?", false).Scan(&polls)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var size int64
+	err = m.db.QueryRow("SELECT (SELECT COALESCE(SUM(LENGTH(data)), 0) FROM poll) + (SELECT COALESCE(SUM(LENGTH(name) + LENGTH(comment) + LENGTH(results)), 0) FROM result) + (SELECT COALESCE(SUM(LENGTH(name) + LENGTH(text)), 0) FROM discussion)").Scan(&size)
+	if err != nil {
+		return 0, 0, err
+	}
+	return polls, size, nil
+}
+
+// HealthCheck pings the database. Broken connections are re-established by the connection pool, so the DataSafe recovers once the database is reachable again.
+func (m *MySQL) HealthCheck() error {
+	if m.db == nil {
+		return ErrMySQLNotConfigured
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return m.db.PingContext(ctx)
+}
+
 func (m *MySQL) RunGC() error {
 	if m.db == nil {
 		return ErrMySQLNotConfigured
@@ -0,0 +1,1561 @@
+//go:build sqlite
+
+package datasafe
+
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+func init() {
+	s := new(SQLite)
+	err := registry.RegisterDataSafe(s, SQLiteName)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SQLiteName contains the name of the DataSafe
+const SQLiteName = "SQLite"
+
+// SQLiteMaxLengthID is the maximum supported poll id length
+const SQLiteMaxLengthID = 500
+
+// ErrSQLiteUnknownID is returned when the requested poll is not in the database
+var ErrSQLiteIDtooLong = errors.New("sqlite: id is too long")
+
+// ErrSQLiteUnknownID is returned when the requested poll/answer is not in the database
+var ErrSQLiteUnknownID = errors.New("sqlite: unknown id")
+
+// ErrSQLiteNotConfigured is returned when the database is used before it is configured
+var ErrSQLiteNotConfigured = errors.New("sqlite: usage before configuration is used")
+
+// sqliteSchema creates all tables if they do not exist yet.
+// IDs are never reused (AUTOINCREMENT) since they are used in edit cookies.
+var sqliteSchema = []string{
+	"CREATE TABLE IF NOT EXISTS poll (name TEXT NOT NULL, data BLOB NOT NULL, creator TEXT, deleted BOOLEAN, lastactivity INTEGER NULL, reminder INTEGER NULL, created INTEGER NULL, followup INTEGER NULL, expiry INTEGER NULL, aggregate BLOB NULL, views INTEGER NOT NULL DEFAULT 0, opens INTEGER NOT NULL DEFAULT 0, uniqueopens INTEGER NOT NULL DEFAULT 0, PRIMARY KEY(name))",
+	"CREATE TABLE IF NOT EXISTS result (id INTEGER PRIMARY KEY AUTOINCREMENT, poll TEXT NOT NULL, name TEXT NOT NULL, comment TEXT NOT NULL, results BLOB NOT NULL, `change` TEXT, endorsements INTEGER NOT NULL DEFAULT 0, created INTEGER NULL, modified INTEGER NULL, consenttime INTEGER NULL, consentversion TEXT NULL, mail TEXT NULL, FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT)",
+	"CREATE INDEX IF NOT EXISTS rp ON result (poll)",
+	"CREATE TABLE IF NOT EXISTS discussion (id INTEGER PRIMARY KEY AUTOINCREMENT, poll TEXT NOT NULL, name TEXT NOT NULL, text TEXT NOT NULL, time INTEGER NOT NULL, FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT)",
+	"CREATE INDEX IF NOT EXISTS dp ON discussion (poll)",
+	"CREATE TABLE IF NOT EXISTS report (id INTEGER PRIMARY KEY AUTOINCREMENT, poll TEXT NOT NULL, reason TEXT NOT NULL, time INTEGER NOT NULL, FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT)",
+	"CREATE INDEX IF NOT EXISTS rep ON report (poll)",
+	"CREATE TABLE IF NOT EXISTS invitation (id INTEGER PRIMARY KEY AUTOINCREMENT, poll TEXT NOT NULL, token TEXT NOT NULL, name TEXT NOT NULL, answer TEXT NULL, FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT)",
+	"CREATE INDEX IF NOT EXISTS ip ON invitation (poll)",
+	"CREATE TABLE IF NOT EXISTS event (id INTEGER PRIMARY KEY AUTOINCREMENT, poll TEXT NOT NULL, event TEXT NOT NULL, answer TEXT NOT NULL, actor TEXT NOT NULL, time INTEGER NOT NULL, FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT)",
+	"CREATE INDEX IF NOT EXISTS ep ON event (poll)",
+	"CREATE TABLE IF NOT EXISTS organizer (id INTEGER PRIMARY KEY AUTOINCREMENT, poll TEXT NOT NULL, name TEXT NOT NULL, FOREIGN KEY (poll) REFERENCES poll (name) ON DELETE CASCADE ON UPDATE RESTRICT)",
+	"CREATE INDEX IF NOT EXISTS op ON organizer (poll)",
+}
+
+// sqliteMigrations adds columns to databases created by older versions.
+// Errors about existing columns are ignored, so all migrations can be run on every start.
+var sqliteMigrations = []string{
+	"ALTER TABLE poll ADD COLUMN expiry INTEGER NULL",
+	"ALTER TABLE poll ADD COLUMN aggregate BLOB NULL",
+	"ALTER TABLE poll ADD COLUMN views INTEGER NOT NULL DEFAULT 0",
+	"ALTER TABLE poll ADD COLUMN opens INTEGER NOT NULL DEFAULT 0",
+	"ALTER TABLE poll ADD COLUMN uniqueopens INTEGER NOT NULL DEFAULT 0",
+}
+
+// SQLite is a DataSafe storing all data in a single SQLite database file.
+// The configuration is the path of the database file. The database is created if it does not exist.
+type SQLite struct {
+	path string
+	db   *sql.DB
+}
+
+// updateLastActivity sets the last activity of a poll to the current time.
+func (m *SQLite) updateLastActivity(pollID string) error {
+	_, err := m.db.Exec("UPDATE poll SET lastactivity=? WHERE name=?", time.Now().Unix(), pollID)
+	return err
+}
+
+func (m *SQLite) SavePollResult(pollID, name, comment string, results []int, change string) (string, error) {
+	if m.db == nil {
+		return "", ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return "", ErrSQLiteIDtooLong
+	}
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	err := enc.Encode(results)
+	if err != nil {
+		return "", fmt.Errorf("sqlite: can not convert results: %w", err)
+	}
+	b := buf.Bytes()
+	now := time.Now().Unix()
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	err = lockAvailableSQLitePoll(tx, pollID)
+	if err != nil {
+		return "", err
+	}
+	r, err := tx.Exec("INSERT INTO result (poll, name, comment, results, `change`, created, modified) VALUES (?,?,?,?,?,?,?)", pollID, name, comment, b, change, now, now)
+	if err != nil {
+		return "", err
+	}
+	lastInserted, err := r.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return "", err
+	}
+	err = m.updateLastActivity(pollID)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(lastInserted, 10), nil
+}
+
+func (m *SQLite) OverwritePollResult(pollID, answerID, name, comment string, results []int, change string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("sqlite: can not convert id '%s': %w", answerID, err)
+	}
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	err = enc.Encode(results)
+	if err != nil {
+		return fmt.Errorf("sqlite: can not convert results: %w", err)
+	}
+	b := buf.Bytes()
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailableSQLitePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE result SET name=?, comment=?, results=?, `change`=?, modified=? WHERE poll=? AND id=?", name, comment, b, change, time.Now().Unix(), pollID, id)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	return m.updateLastActivity(pollID)
+}
+
+// lockAvailableSQLitePoll checks inside of the transaction whether the poll is available.
+// SQLite has no row locks, but all transactions use the same connection, so the poll can not be deleted concurrently.
+// It returns registry.ErrPollNotAvailable if the poll does not exist or is marked as deleted.
+func lockAvailableSQLitePoll(tx *sql.Tx, pollID string) error {
+	var deleted sql.NullBool
+	err := tx.QueryRow("SELECT deleted FROM poll WHERE name=?", pollID).Scan(&deleted)
+	if err == sql.ErrNoRows {
+		return registry.ErrPollNotAvailable
+	}
+	if err != nil {
+		return err
+	}
+	if deleted.Valid && deleted.Bool {
+		return registry.ErrPollNotAvailable
+	}
+	return nil
+}
+
+func (m *SQLite) GetPollResult(pollID string) ([][]int, []string, []string, []string, error) {
+	if m.db == nil {
+		return nil, nil, nil, nil, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, nil, nil, nil, ErrSQLiteIDtooLong
+	}
+
+	ids := make([]string, 0)
+	results := make([][]int, 0)
+	names := make([]string, 0)
+	comments := make([]string, 0)
+
+	rows, err := m.db.Query("SELECT id, name, comment, results FROM result WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r []byte
+		var n, c string
+		var id int64
+		err = rows.Scan(&id, &n, &c, &r)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		buf := bytes.NewBuffer(r)
+		dec := gob.NewDecoder(buf)
+		var singleResult []int
+		err := dec.Decode(&singleResult)
+		if err != nil {
+			log.Printf("sqlite: can not decode results (ignoring it): %s", err.Error())
+			continue
+		}
+		results = append(results, singleResult)
+		names = append(names, n)
+		comments = append(comments, c)
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+
+	return results, names, comments, ids, nil
+}
+
+// GetAnswerTimes returns the time of creation and last modification of all answers of a poll, identified by answer ID.
+func (m *SQLite) GetAnswerTimes(pollID string) (map[string]time.Time, map[string]time.Time, error) {
+	if m.db == nil {
+		return nil, nil, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, nil, ErrSQLiteIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT id, created, modified FROM result WHERE poll=?", pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	created := make(map[string]time.Time)
+	modified := make(map[string]time.Time)
+	for rows.Next() {
+		var id int64
+		var c, mod sql.NullInt64
+		err = rows.Scan(&id, &c, &mod)
+		if err != nil {
+			return nil, nil, err
+		}
+		if c.Valid {
+			created[strconv.FormatInt(id, 10)] = time.Unix(c.Int64, 0)
+		}
+		if mod.Valid {
+			modified[strconv.FormatInt(id, 10)] = time.Unix(mod.Int64, 0)
+		}
+	}
+	return created, modified, nil
+}
+
+// SaveConsent records the time and version of the consent given with an answer.
+func (m *SQLite) SaveConsent(pollID, answerID string, t time.Time, version string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("sqlite: can not convert id '%s': %w", answerID, err)
+	}
+
+	r, err := m.db.Exec("UPDATE result SET consenttime=?, consentversion=? WHERE poll=? AND id=?", t.Unix(), version, pollID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSQLiteUnknownID
+	}
+	return nil
+}
+
+// SaveAnswerMail saves the email address given with an answer. An empty address removes it.
+func (m *SQLite) SaveAnswerMail(pollID, answerID, mail string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("sqlite: can not convert id '%s': %w", answerID, err)
+	}
+
+	r, err := m.db.Exec("UPDATE result SET mail=? WHERE poll=? AND id=?", sql.NullString{String: mail, Valid: mail != ""}, pollID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSQLiteUnknownID
+	}
+	return nil
+}
+
+// GetAnswerMails returns the email addresses given with the answers of a poll as a map answer ID -> address.
+// Answers without an address are not contained.
+func (m *SQLite) GetAnswerMails(pollID string) (map[string]string, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, ErrSQLiteIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT id, mail FROM result WHERE poll=? AND mail IS NOT NULL", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mails := make(map[string]string)
+	for rows.Next() {
+		var id int64
+		var mail string
+		err = rows.Scan(&id, &mail)
+		if err != nil {
+			return nil, err
+		}
+		mails[strconv.FormatInt(id, 10)] = mail
+	}
+	return mails, nil
+}
+
+// GetConsent returns the time and version of the consent given with an answer.
+// The zero time is returned if no consent is recorded.
+func (m *SQLite) GetConsent(pollID, answerID string) (time.Time, string, error) {
+	if m.db == nil {
+		return time.Time{}, "", ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return time.Time{}, "", ErrSQLiteIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("sqlite: can not convert id '%s': %w", answerID, err)
+	}
+
+	rows, err := m.db.Query("SELECT consenttime, consentversion FROM result WHERE poll=? AND id=?", pollID, id)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return time.Time{}, "", ErrSQLiteUnknownID
+	}
+	var t sql.NullInt64
+	var v sql.NullString
+	err = rows.Scan(&t, &v)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if !t.Valid {
+		return time.Time{}, "", nil
+	}
+	return time.Unix(t.Int64, 0), v.String, nil
+}
+
+func (m *SQLite) GetSinglePollResult(pollID, answerID string) ([]int, string, string, error) {
+	if m.db == nil {
+		return nil, "", "", ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, "", "", ErrSQLiteIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("sqlite: can not convert id '%s': %w", answerID, err)
+	}
+
+	rows, err := m.db.Query("SELECT name, comment, results FROM result WHERE poll=? AND id=?", pollID, id)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var r []byte
+		var n, c string
+		err = rows.Scan(&n, &c, &r)
+		if err != nil {
+			return nil, "", "", err
+		}
+		buf := bytes.NewBuffer(r)
+		dec := gob.NewDecoder(buf)
+		var singleResult []int
+		err := dec.Decode(&singleResult)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("sqlite: can not decode results: %w", err)
+		}
+		return singleResult, n, c, nil
+	}
+
+	return nil, "", "", ErrSQLiteUnknownID
+}
+
+func (m *SQLite) DeleteAnswer(pollID, answerID string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("sqlite: can not convert id '%s': %w", answerID, err)
+	}
+
+	r, err := m.db.Exec("DELETE FROM result WHERE poll=? AND id=?", pollID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSQLiteUnknownID
+	}
+	if affected > 1 {
+		return fmt.Errorf("sqlite: delete for (%s, %d) was too large: %d", pollID, id, affected)
+	}
+	return m.updateLastActivity(pollID)
+}
+
+func (m *SQLite) EndorseAnswer(pollID, answerID string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("sqlite: can not convert id '%s': %w", answerID, err)
+	}
+
+	r, err := m.db.Exec("UPDATE result SET endorsements=endorsements+1 WHERE poll=? AND id=?", pollID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSQLiteUnknownID
+	}
+	return m.updateLastActivity(pollID)
+}
+
+func (m *SQLite) GetEndorsements(pollID string) (map[string]int, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, ErrSQLiteIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT id, endorsements FROM result WHERE poll=? AND endorsements>0", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	e := make(map[string]int)
+	for rows.Next() {
+		var id int64
+		var c int
+		err = rows.Scan(&id, &c)
+		if err != nil {
+			return nil, err
+		}
+		e[strconv.FormatInt(id, 10)] = c
+	}
+	return e, nil
+}
+
+func (m *SQLite) SavePollConfig(pollID string, config []byte) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	now := time.Now().Unix()
+	_, err := m.db.Exec("INSERT INTO poll (name, data, deleted, lastactivity, created) VALUES (?,?,?,?,?) ON CONFLICT(name) DO UPDATE SET data=excluded.data, lastactivity=excluded.lastactivity", pollID, config, false, now, now)
+
+	return err
+}
+
+func (m *SQLite) GetPollConfig(pollID string) ([]byte, error) {
+	if m.db == nil {
+		return []byte{}, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return []byte{}, ErrSQLiteIDtooLong
+	}
+
+	r, err := m.db.Query("SELECT data FROM poll WHERE name=?", pollID)
+	if err != nil {
+		return []byte{}, err
+	}
+	defer r.Close()
+
+	if !r.Next() {
+		return []byte{}, nil
+	}
+	var data []byte
+	err = r.Scan(&data)
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+func (m *SQLite) SavePollCreator(pollID, name string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	_, err := m.db.Exec("UPDATE poll SET creator=? WHERE name=?", name, pollID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *SQLite) GetPollCreator(pollID string) (string, error) {
+	if m.db == nil {
+		return "", ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return "", ErrSQLiteIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT creator FROM poll WHERE name=?", pollID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return "", ErrSQLiteUnknownID
+	}
+	var c sql.NullString
+	err = rows.Scan(&c)
+	if err != nil {
+		return "", err
+	}
+	if !c.Valid {
+		return "", nil
+	}
+	return c.String, nil
+}
+
+// SavePollOrganizers sets the co-organizers of the poll, replacing all previous co-organizers.
+func (m *SQLite) SavePollOrganizers(pollID string, names []string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("DELETE FROM organizer WHERE poll=?", pollID)
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		_, err = tx.Exec("INSERT INTO organizer (poll, name) VALUES (?,?)", pollID, n)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPollOrganizers returns the co-organizers of the poll in the order they were added.
+func (m *SQLite) GetPollOrganizers(pollID string) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	names := make([]string, 0)
+	rows, err := m.db.Query("SELECT name FROM organizer WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n string
+		err = rows.Scan(&n)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+	}
+	return names, nil
+}
+
+func (m *SQLite) GetPollsByCreator(name string) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	polls := make([]string, 0)
+	if name == "" {
+		return polls, nil
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE creator=? AND deleted=? ORDER BY name ASC", name, false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+func (m *SQLite) MarkPollDeleted(pollID string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	_, err := m.db.Exec("UPDATE poll SET deleted=?, creator=? WHERE name=?", true, sql.NullString{Valid: false}, pollID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *SQLite) GetChange(pollID, answerID string) (string, error) {
+	if m.db == nil {
+		return "", ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return "", ErrSQLiteIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(answerID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("sqlite: can not convert id '%s': %w", answerID, err)
+	}
+
+	rows, err := m.db.Query("SELECT `change` FROM result WHERE poll=? AND id=?", pollID, id)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return "", ErrSQLiteUnknownID
+	}
+	var c sql.NullString
+	err = rows.Scan(&c)
+	if err != nil {
+		return "", err
+	}
+	if !c.Valid {
+		return "", nil
+	}
+	return c.String, nil
+}
+
+func (m *SQLite) GetLastActivity(pollID string) (time.Time, error) {
+	if m.db == nil {
+		return time.Time{}, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return time.Time{}, ErrSQLiteIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT lastactivity FROM poll WHERE name=?", pollID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return time.Time{}, ErrSQLiteUnknownID
+	}
+	var l sql.NullInt64
+	err = rows.Scan(&l)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !l.Valid {
+		return time.Time{}, nil
+	}
+	return time.Unix(l.Int64, 0), nil
+}
+
+func (m *SQLite) SaveDiscussionEntry(pollID, name, text string) (string, error) {
+	if m.db == nil {
+		return "", ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return "", ErrSQLiteIDtooLong
+	}
+
+	r, err := m.db.Exec("INSERT INTO discussion (poll, name, text, time) VALUES (?,?,?,?)", pollID, name, text, time.Now().Unix())
+	if err != nil {
+		return "", err
+	}
+	lastInserted, err := r.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+	err = m.updateLastActivity(pollID)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(lastInserted, 10), nil
+}
+
+func (m *SQLite) GetDiscussion(pollID string) ([]string, []string, []time.Time, []string, error) {
+	if m.db == nil {
+		return nil, nil, nil, nil, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, nil, nil, nil, ErrSQLiteIDtooLong
+	}
+
+	names := make([]string, 0)
+	texts := make([]string, 0)
+	times := make([]time.Time, 0)
+	ids := make([]string, 0)
+
+	rows, err := m.db.Query("SELECT id, name, text, time FROM discussion WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n, t string
+		var id, ti int64
+		err = rows.Scan(&id, &n, &t, &ti)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		names = append(names, n)
+		texts = append(texts, t)
+		times = append(times, time.Unix(ti, 0))
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+
+	return names, texts, times, ids, nil
+}
+
+func (m *SQLite) DeleteDiscussionEntry(pollID, entryID string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	var id int64
+	id, err := strconv.ParseInt(entryID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("sqlite: can not convert id '%s': %w", entryID, err)
+	}
+
+	r, err := m.db.Exec("DELETE FROM discussion WHERE poll=? AND id=?", pollID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSQLiteUnknownID
+	}
+	return m.updateLastActivity(pollID)
+}
+
+// PseudonymiseAnswers replaces the names of all answers last modified before the given time with a pseudonym and removes their comments.
+// The names in the activity log of the poll are replaced as well. Answers without a known modification time are not changed. It returns the number of changed answers.
+func (m *SQLite) PseudonymiseAnswers(before time.Time, pseudonym func(pollID, answerID string) string) (int, error) {
+	if m.db == nil {
+		return 0, ErrSQLiteNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT result.id, result.poll, result.name, result.comment, result.mail FROM result INNER JOIN poll ON result.poll=poll.name WHERE result.modified IS NOT NULL AND result.modified<? AND poll.deleted=?", before.Unix(), false)
+	if err != nil {
+		return 0, err
+	}
+
+	type answer struct {
+		id   int64
+		poll string
+	}
+	update := make([]answer, 0)
+	names := make([]string, 0)
+	for rows.Next() {
+		var a answer
+		var n, c string
+		var mail sql.NullString
+		err = rows.Scan(&a.id, &a.poll, &n, &c, &mail)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		name := pseudonym(a.poll, strconv.FormatInt(a.id, 10))
+		if n == name && c == "" && !mail.Valid {
+			continue
+		}
+		update = append(update, a)
+		names = append(names, name)
+	}
+	rows.Close()
+
+	for i := range update {
+		_, err = m.db.Exec("UPDATE result SET name=?, comment=?, mail=NULL WHERE id=?", names[i], "", update[i].id)
+		if err != nil {
+			return i, err
+		}
+		_, err = m.db.Exec("UPDATE event SET actor=? WHERE poll=? AND answer=?", names[i], update[i].poll, strconv.FormatInt(update[i].id, 10))
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(update), nil
+}
+
+// SetReminder sets the time at which a reminder for the poll is due. The zero time removes the reminder.
+func (m *SQLite) SetReminder(pollID string, t time.Time) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	reminder := sql.NullInt64{Int64: t.Unix(), Valid: !t.IsZero()}
+	_, err := m.db.Exec("UPDATE poll SET reminder=? WHERE name=?", reminder, pollID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDueReminders returns the IDs of all polls which are not deleted and have a reminder due until the given time.
+func (m *SQLite) GetDueReminders(until time.Time) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE reminder IS NOT NULL AND reminder<=? AND deleted=? ORDER BY name ASC", until.Unix(), false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// SetFollowUp sets the time at which a follow-up poll for the poll is due. The zero time removes the follow-up.
+func (m *SQLite) SetFollowUp(pollID string, t time.Time) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	followUp := sql.NullInt64{Int64: t.Unix(), Valid: !t.IsZero()}
+	_, err := m.db.Exec("UPDATE poll SET followup=? WHERE name=?", followUp, pollID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDueFollowUps returns the IDs of all polls which are not deleted and have a follow-up poll due until the given time.
+func (m *SQLite) GetDueFollowUps(until time.Time) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE followup IS NOT NULL AND followup<=? AND deleted=? ORDER BY name ASC", until.Unix(), false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// SetExpiry sets the time at which the poll expires. The zero time removes the expiry.
+func (m *SQLite) SetExpiry(pollID string, t time.Time) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	expiry := sql.NullInt64{Int64: t.Unix(), Valid: !t.IsZero()}
+	_, err := m.db.Exec("UPDATE poll SET expiry=? WHERE name=?", expiry, pollID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveAggregate stores the precomputed aggregate of the poll. nil removes the aggregate.
+func (m *SQLite) SaveAggregate(pollID string, aggregate []byte) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	_, err := m.db.Exec("UPDATE poll SET aggregate=? WHERE name=?", aggregate, pollID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAggregate returns the precomputed aggregate of the poll or nil if none is stored.
+func (m *SQLite) GetAggregate(pollID string) ([]byte, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, ErrSQLiteIDtooLong
+	}
+
+	r, err := m.db.Query("SELECT aggregate FROM poll WHERE name=?", pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	if !r.Next() {
+		return nil, nil
+	}
+	var aggregate []byte
+	err = r.Scan(&aggregate)
+	if err != nil {
+		return nil, err
+	}
+	if len(aggregate) == 0 {
+		return nil, nil
+	}
+	return aggregate, nil
+}
+
+// GetExpiredPolls returns the IDs of all polls which are not deleted and expire until the given time.
+func (m *SQLite) GetExpiredPolls(until time.Time) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE expiry IS NOT NULL AND expiry<=? AND deleted=? ORDER BY name ASC", until.Unix(), false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// GetPollsCreatedBefore returns the IDs of all polls which are not deleted and were created before the given time.
+// For polls created by older versions, the time of the last activity is used instead.
+func (m *SQLite) GetPollsCreatedBefore(before time.Time) ([]string, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE COALESCE(created, lastactivity)<? AND deleted=? ORDER BY name ASC", before.Unix(), false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// ListPolls returns the IDs of all polls which are not deleted.
+func (m *SQLite) ListPolls() ([]string, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT name FROM poll WHERE deleted=? ORDER BY name ASC", false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// GetPollStats returns the number of answers and the time of the last change to the poll configuration or answers.
+func (m *SQLite) GetPollStats(pollID string) (int, time.Time, error) {
+	if m.db == nil {
+		return 0, time.Time{}, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return 0, time.Time{}, ErrSQLiteIDtooLong
+	}
+
+	rows, err := m.db.Query("SELECT (SELECT COUNT(*) FROM result WHERE poll=?), lastactivity FROM poll WHERE name=?", pollID, pollID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, time.Time{}, ErrSQLiteUnknownID
+	}
+	var answers int
+	var l sql.NullInt64
+	err = rows.Scan(&answers, &l)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if !l.Valid {
+		return answers, time.Time{}, nil
+	}
+	return answers, time.Unix(l.Int64, 0), nil
+}
+
+// SaveReport adds an abuse report to a poll.
+func (m *SQLite) SaveReport(pollID, reason string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailableSQLitePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO report (poll, reason, time) VALUES (?,?,?)", pollID, reason, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetReports returns all abuse reports of a poll.
+func (m *SQLite) GetReports(pollID string) ([]string, []time.Time, error) {
+	if m.db == nil {
+		return nil, nil, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, nil, ErrSQLiteIDtooLong
+	}
+
+	reasons := make([]string, 0)
+	times := make([]time.Time, 0)
+
+	rows, err := m.db.Query("SELECT reason, time FROM report WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r string
+		var t int64
+		err = rows.Scan(&r, &t)
+		if err != nil {
+			return nil, nil, err
+		}
+		reasons = append(reasons, r)
+		times = append(times, time.Unix(t, 0))
+	}
+	return reasons, times, nil
+}
+
+// DeleteReports removes all abuse reports of a poll.
+func (m *SQLite) DeleteReports(pollID string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	_, err := m.db.Exec("DELETE FROM report WHERE poll=?", pollID)
+	return err
+}
+
+// GetReportedPolls returns the IDs of all polls which are not deleted and have at least one abuse report.
+func (m *SQLite) GetReportedPolls() ([]string, error) {
+	if m.db == nil {
+		return nil, ErrSQLiteNotConfigured
+	}
+
+	rows, err := m.db.Query("SELECT DISTINCT poll.name FROM poll INNER JOIN report ON report.poll=poll.name WHERE poll.deleted=? ORDER BY poll.name ASC", false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := make([]string, 0)
+	for rows.Next() {
+		var p string
+		err = rows.Scan(&p)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, nil
+}
+
+// SavePollEvent adds an entry to the activity log of a poll. answerID is empty if the event does not belong to an answer.
+func (m *SQLite) SavePollEvent(pollID, event, answerID, actor string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailableSQLitePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO event (poll, event, answer, actor, time) VALUES (?,?,?,?,?)", pollID, event, answerID, actor, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetPollEvents returns the activity log of a poll, oldest entry first.
+func (m *SQLite) GetPollEvents(pollID string) ([]string, []string, []string, []time.Time, error) {
+	if m.db == nil {
+		return nil, nil, nil, nil, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, nil, nil, nil, ErrSQLiteIDtooLong
+	}
+
+	events := make([]string, 0)
+	answerIDs := make([]string, 0)
+	actors := make([]string, 0)
+	times := make([]time.Time, 0)
+
+	rows, err := m.db.Query("SELECT event, answer, actor, time FROM event WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e, a, n string
+		var t int64
+		err = rows.Scan(&e, &a, &n, &t)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		events = append(events, e)
+		answerIDs = append(answerIDs, a)
+		actors = append(actors, n)
+		times = append(times, time.Unix(t, 0))
+	}
+	return events, answerIDs, actors, times, nil
+}
+
+// AddPollAccess adds to the access counts of a poll.
+func (m *SQLite) AddPollAccess(pollID string, views, opens, uniqueOpens int) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailableSQLitePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE poll SET views=views+?, opens=opens+?, uniqueopens=uniqueopens+? WHERE name=?", views, opens, uniqueOpens, pollID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetPollAccess returns the access counts of a poll.
+func (m *SQLite) GetPollAccess(pollID string) (int, int, int, error) {
+	if m.db == nil {
+		return 0, 0, 0, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return 0, 0, 0, ErrSQLiteIDtooLong
+	}
+
+	r, err := m.db.Query("SELECT views, opens, uniqueopens FROM poll WHERE name=?", pollID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer r.Close()
+
+	if !r.Next() {
+		return 0, 0, 0, nil
+	}
+	var views, opens, uniqueOpens int
+	err = r.Scan(&views, &opens, &uniqueOpens)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return views, opens, uniqueOpens, nil
+}
+
+// SaveInvitation adds an invitation to a poll.
+func (m *SQLite) SaveInvitation(pollID, token, name string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = lockAvailableSQLitePoll(tx, pollID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO invitation (poll, token, name) VALUES (?,?,?)", pollID, token, name)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetInvitations returns all invitations of a poll in the order they were added.
+func (m *SQLite) GetInvitations(pollID string) ([]string, []string, []string, error) {
+	if m.db == nil {
+		return nil, nil, nil, ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return nil, nil, nil, ErrSQLiteIDtooLong
+	}
+
+	tokens := make([]string, 0)
+	names := make([]string, 0)
+	answerIDs := make([]string, 0)
+
+	rows, err := m.db.Query("SELECT token, name, answer FROM invitation WHERE poll=? ORDER BY id ASC", pollID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t, n string
+		var a sql.NullString
+		err = rows.Scan(&t, &n, &a)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		tokens = append(tokens, t)
+		names = append(names, n)
+		answerIDs = append(answerIDs, a.String)
+	}
+	return tokens, names, answerIDs, nil
+}
+
+// SetInvitationAnswer links an invitation to an answer if it is currently linked to previousAnswerID.
+func (m *SQLite) SetInvitationAnswer(pollID, token, previousAnswerID, answerID string) error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	if len(pollID) > SQLiteMaxLengthID {
+		return ErrSQLiteIDtooLong
+	}
+
+	r, err := m.db.Exec("UPDATE invitation SET answer=? WHERE poll=? AND token=? AND COALESCE(answer, '')=?", answerID, pollID, token, previousAnswerID)
+	if err != nil {
+		return err
+	}
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return registry.ErrInvitationChanged
+	}
+	return nil
+}
+
+// GetStorageUsage returns the number of polls not marked as deleted and the size of all stored data in bytes.
+func (m *SQLite) GetStorageUsage() (int, int64, error) {
+	if m.db == nil {
+		return 0, 0, ErrSQLiteNotConfigured
+	}
+
+	var polls int
+	err := m.db.QueryRow("SELECT COUNT(*) FROM poll WHERE deleted=?", false).Scan(&polls)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var size int64
+	err = m.db.QueryRow("SELECT (SELECT COALESCE(SUM(LENGTH(data)), 0) FROM poll) + (SELECT COALESCE(SUM(LENGTH(name) + LENGTH(comment) + LENGTH(results)), 0) FROM result) + (SELECT COALESCE(SUM(LENGTH(name) + LENGTH(text)), 0) FROM discussion)").Scan(&size)
+	if err != nil {
+		return 0, 0, err
+	}
+	return polls, size, nil
+}
+
+// HealthCheck pings the database.
+func (m *SQLite) HealthCheck() error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return m.db.PingContext(ctx)
+}
+
+func (m *SQLite) RunGC() error {
+	if m.db == nil {
+		return ErrSQLiteNotConfigured
+	}
+
+	_, err := m.db.Exec("DELETE FROM poll WHERE deleted=?", true)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *SQLite) LoadConfig(data []byte) error {
+	m.path = strings.TrimSpace(string(data))
+	if m.path == "" {
+		return errors.New("sqlite: path of the database must not be empty")
+	}
+	// Foreign keys are required for deleting answers together with their poll
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", m.path))
+	if err != nil {
+		return fmt.Errorf("sqlite: can not open '%s': %w", m.path, err)
+	}
+	// SQLite only allows a single writer, so all requests share one connection
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	for _, stmt := range sqliteSchema {
+		_, err = db.Exec(stmt)
+		if err != nil {
+			db.Close()
+			return fmt.Errorf("sqlite: can not create schema: %w", err)
+		}
+	}
+	for _, stmt := range sqliteMigrations {
+		_, err = db.Exec(stmt)
+		if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			db.Close()
+			return fmt.Errorf("sqlite: can not migrate schema: %w", err)
+		}
+	}
+	m.db = db
+	return nil
+}
+
+func (m *SQLite) FlushAndClose() {
+	if m.db == nil {
+		return
+	}
+
+	err := m.db.Close()
+	if err != nil {
+		log.Printf("sqlite: error closing db: %s", err.Error())
+	}
+}
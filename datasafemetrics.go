@@ -0,0 +1,410 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+// slowDataSafeCall is the duration after which a data safe call is logged.
+const slowDataSafeCall = time.Second
+
+// instrumentedDataSafe wraps a DataSafe and records call counts, errors and latencies of all methods.
+type instrumentedDataSafe struct {
+	safe registry.DataSafe
+}
+
+// record records a single call of method which started at start.
+func (i instrumentedDataSafe) record(method string, start time.Time, err error) {
+	d := time.Since(start)
+	label := metricLabel("method", method)
+	MetricsAdd("pollgo_datasafe_calls_total", "Number of data safe calls.", label, 1)
+	MetricsAdd("pollgo_datasafe_seconds_total", "Time spent in data safe calls.", label, d.Seconds())
+	if err != nil {
+		MetricsAdd("pollgo_datasafe_errors_total", "Number of failed data safe calls.", label, 1)
+	}
+	if d >= slowDataSafeCall {
+		log.Printf("datasafe: slow call %s (%s)", method, d.String())
+	}
+}
+
+func (i instrumentedDataSafe) SavePollResult(pollID, name, comment string, results []int, change string) (string, error) {
+	start := time.Now()
+	id, err := i.safe.SavePollResult(pollID, name, comment, results, change)
+	i.record("SavePollResult", start, err)
+	return id, err
+}
+
+func (i instrumentedDataSafe) OverwritePollResult(pollID, answerID, name, comment string, results []int, change string) error {
+	start := time.Now()
+	err := i.safe.OverwritePollResult(pollID, answerID, name, comment, results, change)
+	i.record("OverwritePollResult", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetPollResult(pollID string) ([][]int, []string, []string, []string, error) {
+	start := time.Now()
+	results, names, comments, answerIDs, err := i.safe.GetPollResult(pollID)
+	i.record("GetPollResult", start, err)
+	return results, names, comments, answerIDs, err
+}
+
+func (i instrumentedDataSafe) GetSinglePollResult(pollID, answerID string) ([]int, string, string, error) {
+	start := time.Now()
+	result, name, comment, err := i.safe.GetSinglePollResult(pollID, answerID)
+	i.record("GetSinglePollResult", start, err)
+	return result, name, comment, err
+}
+
+func (i instrumentedDataSafe) DeleteAnswer(pollID, answerID string) error {
+	start := time.Now()
+	err := i.safe.DeleteAnswer(pollID, answerID)
+	i.record("DeleteAnswer", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) EndorseAnswer(pollID, answerID string) error {
+	start := time.Now()
+	err := i.safe.EndorseAnswer(pollID, answerID)
+	i.record("EndorseAnswer", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetEndorsements(pollID string) (map[string]int, error) {
+	start := time.Now()
+	e, err := i.safe.GetEndorsements(pollID)
+	i.record("GetEndorsements", start, err)
+	return e, err
+}
+
+func (i instrumentedDataSafe) GetAnswerTimes(pollID string) (map[string]time.Time, map[string]time.Time, error) {
+	start := time.Now()
+	created, modified, err := i.safe.GetAnswerTimes(pollID)
+	i.record("GetAnswerTimes", start, err)
+	return created, modified, err
+}
+
+func (i instrumentedDataSafe) SaveConsent(pollID, answerID string, t time.Time, version string) error {
+	start := time.Now()
+	err := i.safe.SaveConsent(pollID, answerID, t, version)
+	i.record("SaveConsent", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetConsent(pollID, answerID string) (time.Time, string, error) {
+	start := time.Now()
+	t, version, err := i.safe.GetConsent(pollID, answerID)
+	i.record("GetConsent", start, err)
+	return t, version, err
+}
+
+func (i instrumentedDataSafe) SaveAnswerMail(pollID, answerID, mail string) error {
+	start := time.Now()
+	err := i.safe.SaveAnswerMail(pollID, answerID, mail)
+	i.record("SaveAnswerMail", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetAnswerMails(pollID string) (map[string]string, error) {
+	start := time.Now()
+	mails, err := i.safe.GetAnswerMails(pollID)
+	i.record("GetAnswerMails", start, err)
+	return mails, err
+}
+
+func (i instrumentedDataSafe) PseudonymiseAnswers(before time.Time, pseudonym func(pollID, answerID string) string) (int, error) {
+	start := time.Now()
+	n, err := i.safe.PseudonymiseAnswers(before, pseudonym)
+	i.record("PseudonymiseAnswers", start, err)
+	return n, err
+}
+
+func (i instrumentedDataSafe) SavePollConfig(pollID string, config []byte) error {
+	start := time.Now()
+	err := i.safe.SavePollConfig(pollID, config)
+	i.record("SavePollConfig", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetPollConfig(pollID string) ([]byte, error) {
+	start := time.Now()
+	c, err := i.safe.GetPollConfig(pollID)
+	i.record("GetPollConfig", start, err)
+	return c, err
+}
+
+func (i instrumentedDataSafe) SavePollCreator(pollID, name string) error {
+	start := time.Now()
+	err := i.safe.SavePollCreator(pollID, name)
+	i.record("SavePollCreator", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) SavePollOrganizers(pollID string, names []string) error {
+	start := time.Now()
+	err := i.safe.SavePollOrganizers(pollID, names)
+	i.record("SavePollOrganizers", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetPollOrganizers(pollID string) ([]string, error) {
+	start := time.Now()
+	o, err := i.safe.GetPollOrganizers(pollID)
+	i.record("GetPollOrganizers", start, err)
+	return o, err
+}
+
+func (i instrumentedDataSafe) GetPollCreator(pollID string) (string, error) {
+	start := time.Now()
+	name, err := i.safe.GetPollCreator(pollID)
+	i.record("GetPollCreator", start, err)
+	return name, err
+}
+
+func (i instrumentedDataSafe) GetPollsByCreator(name string) ([]string, error) {
+	start := time.Now()
+	polls, err := i.safe.GetPollsByCreator(name)
+	i.record("GetPollsByCreator", start, err)
+	return polls, err
+}
+
+func (i instrumentedDataSafe) MarkPollDeleted(pollID string) error {
+	start := time.Now()
+	err := i.safe.MarkPollDeleted(pollID)
+	i.record("MarkPollDeleted", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetChange(pollID, answerID string) (string, error) {
+	start := time.Now()
+	change, err := i.safe.GetChange(pollID, answerID)
+	i.record("GetChange", start, err)
+	return change, err
+}
+
+func (i instrumentedDataSafe) GetLastActivity(pollID string) (time.Time, error) {
+	start := time.Now()
+	t, err := i.safe.GetLastActivity(pollID)
+	i.record("GetLastActivity", start, err)
+	return t, err
+}
+
+func (i instrumentedDataSafe) SaveDiscussionEntry(pollID, name, text string) (string, error) {
+	start := time.Now()
+	id, err := i.safe.SaveDiscussionEntry(pollID, name, text)
+	i.record("SaveDiscussionEntry", start, err)
+	return id, err
+}
+
+func (i instrumentedDataSafe) GetDiscussion(pollID string) ([]string, []string, []time.Time, []string, error) {
+	start := time.Now()
+	names, texts, times, entryIDs, err := i.safe.GetDiscussion(pollID)
+	i.record("GetDiscussion", start, err)
+	return names, texts, times, entryIDs, err
+}
+
+func (i instrumentedDataSafe) DeleteDiscussionEntry(pollID, entryID string) error {
+	start := time.Now()
+	err := i.safe.DeleteDiscussionEntry(pollID, entryID)
+	i.record("DeleteDiscussionEntry", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) SetReminder(pollID string, t time.Time) error {
+	start := time.Now()
+	err := i.safe.SetReminder(pollID, t)
+	i.record("SetReminder", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetDueReminders(until time.Time) ([]string, error) {
+	start := time.Now()
+	polls, err := i.safe.GetDueReminders(until)
+	i.record("GetDueReminders", start, err)
+	return polls, err
+}
+
+func (i instrumentedDataSafe) SetFollowUp(pollID string, t time.Time) error {
+	start := time.Now()
+	err := i.safe.SetFollowUp(pollID, t)
+	i.record("SetFollowUp", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetDueFollowUps(until time.Time) ([]string, error) {
+	start := time.Now()
+	polls, err := i.safe.GetDueFollowUps(until)
+	i.record("GetDueFollowUps", start, err)
+	return polls, err
+}
+
+func (i instrumentedDataSafe) SetExpiry(pollID string, t time.Time) error {
+	start := time.Now()
+	err := i.safe.SetExpiry(pollID, t)
+	i.record("SetExpiry", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetExpiredPolls(until time.Time) ([]string, error) {
+	start := time.Now()
+	polls, err := i.safe.GetExpiredPolls(until)
+	i.record("GetExpiredPolls", start, err)
+	return polls, err
+}
+
+func (i instrumentedDataSafe) SaveAggregate(pollID string, aggregate []byte) error {
+	start := time.Now()
+	err := i.safe.SaveAggregate(pollID, aggregate)
+	i.record("SaveAggregate", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetAggregate(pollID string) ([]byte, error) {
+	start := time.Now()
+	aggregate, err := i.safe.GetAggregate(pollID)
+	i.record("GetAggregate", start, err)
+	return aggregate, err
+}
+
+func (i instrumentedDataSafe) GetPollsCreatedBefore(before time.Time) ([]string, error) {
+	start := time.Now()
+	polls, err := i.safe.GetPollsCreatedBefore(before)
+	i.record("GetPollsCreatedBefore", start, err)
+	return polls, err
+}
+
+func (i instrumentedDataSafe) ListPolls() ([]string, error) {
+	start := time.Now()
+	polls, err := i.safe.ListPolls()
+	i.record("ListPolls", start, err)
+	return polls, err
+}
+
+func (i instrumentedDataSafe) GetPollStats(pollID string) (int, time.Time, error) {
+	start := time.Now()
+	answers, lastModified, err := i.safe.GetPollStats(pollID)
+	i.record("GetPollStats", start, err)
+	return answers, lastModified, err
+}
+
+func (i instrumentedDataSafe) SaveReport(pollID, reason string) error {
+	start := time.Now()
+	err := i.safe.SaveReport(pollID, reason)
+	i.record("SaveReport", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetReports(pollID string) ([]string, []time.Time, error) {
+	start := time.Now()
+	reasons, times, err := i.safe.GetReports(pollID)
+	i.record("GetReports", start, err)
+	return reasons, times, err
+}
+
+func (i instrumentedDataSafe) DeleteReports(pollID string) error {
+	start := time.Now()
+	err := i.safe.DeleteReports(pollID)
+	i.record("DeleteReports", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetReportedPolls() ([]string, error) {
+	start := time.Now()
+	polls, err := i.safe.GetReportedPolls()
+	i.record("GetReportedPolls", start, err)
+	return polls, err
+}
+
+func (i instrumentedDataSafe) SavePollEvent(pollID, event, answerID, actor string) error {
+	start := time.Now()
+	err := i.safe.SavePollEvent(pollID, event, answerID, actor)
+	i.record("SavePollEvent", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetPollEvents(pollID string) ([]string, []string, []string, []time.Time, error) {
+	start := time.Now()
+	events, answerIDs, actors, times, err := i.safe.GetPollEvents(pollID)
+	i.record("GetPollEvents", start, err)
+	return events, answerIDs, actors, times, err
+}
+
+func (i instrumentedDataSafe) AddPollAccess(pollID string, views, opens, uniqueOpens int) error {
+	start := time.Now()
+	err := i.safe.AddPollAccess(pollID, views, opens, uniqueOpens)
+	i.record("AddPollAccess", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetPollAccess(pollID string) (int, int, int, error) {
+	start := time.Now()
+	views, opens, uniqueOpens, err := i.safe.GetPollAccess(pollID)
+	i.record("GetPollAccess", start, err)
+	return views, opens, uniqueOpens, err
+}
+
+func (i instrumentedDataSafe) SaveInvitation(pollID, token, name string) error {
+	start := time.Now()
+	err := i.safe.SaveInvitation(pollID, token, name)
+	i.record("SaveInvitation", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetInvitations(pollID string) ([]string, []string, []string, error) {
+	start := time.Now()
+	tokens, names, answerIDs, err := i.safe.GetInvitations(pollID)
+	i.record("GetInvitations", start, err)
+	return tokens, names, answerIDs, err
+}
+
+func (i instrumentedDataSafe) SetInvitationAnswer(pollID, token, previousAnswerID, answerID string) error {
+	start := time.Now()
+	err := i.safe.SetInvitationAnswer(pollID, token, previousAnswerID, answerID)
+	i.record("SetInvitationAnswer", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) GetStorageUsage() (int, int64, error) {
+	start := time.Now()
+	polls, bytes, err := i.safe.GetStorageUsage()
+	i.record("GetStorageUsage", start, err)
+	return polls, bytes, err
+}
+
+func (i instrumentedDataSafe) HealthCheck() error {
+	start := time.Now()
+	err := i.safe.HealthCheck()
+	i.record("HealthCheck", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) RunGC() error {
+	start := time.Now()
+	err := i.safe.RunGC()
+	i.record("RunGC", start, err)
+	return err
+}
+
+func (i instrumentedDataSafe) LoadConfig(data []byte) error {
+	return i.safe.LoadConfig(data)
+}
+
+func (i instrumentedDataSafe) FlushAndClose() {
+	i.safe.FlushAndClose()
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// demoCheckInterval is the interval in which expired demo polls are deleted.
+const demoCheckInterval = 10 * time.Minute
+
+// deleteExpiredDemoPolls deletes all polls older than 'DemoPollLifetimeHours'.
+func deleteExpiredDemoPolls() {
+	keys, err := safe.GetPollsCreatedBefore(time.Now().Add(-time.Duration(config.DemoPollLifetimeHours) * time.Hour))
+	if err != nil {
+		log.Printf("demo: can not get expired polls: %s", err.Error())
+		return
+	}
+
+	for _, key := range keys {
+		b, err := safe.GetPollConfig(key)
+		if err != nil {
+			log.Printf("demo: can not load poll %s: %s", key, err.Error())
+			continue
+		}
+		p, err := LoadPoll(b)
+		if err != nil {
+			log.Printf("demo: can not load poll %s: %s", key, err.Error())
+			continue
+		}
+		err = p.deletePoll(key)
+		if err != nil {
+			log.Printf("demo: can not delete poll %s: %s", key, err.Error())
+		}
+	}
+	if len(keys) > 0 {
+		log.Printf("demo: deleted %d expired polls", len(keys))
+	}
+}
+
+// demoWorker periodically deletes expired demo polls. It never returns.
+func demoWorker() {
+	t := time.NewTicker(demoCheckInterval)
+	defer t.Stop()
+	for {
+		deleteExpiredDemoPolls()
+		<-t.C
+	}
+}
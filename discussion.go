@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// discussionMaxEntries is the maximum number of discussion entries per poll.
+const discussionMaxEntries = 500
+
+// discussionMaxLength is the maximum length of a single discussion entry in characters.
+const discussionMaxLength = 1000
+
+// discussionMaxNameLength is the maximum length of the name of a discussion entry in characters.
+const discussionMaxNameLength = 150
+
+// handleDiscussion handles adding ('discussion=add') and deleting ('discussion=delete') discussion entries of a poll.
+// Adding entries is not possible while the poll is closed. Deleting entries is only allowed for the creator of the poll.
+func (p Poll) handleDiscussion(rw http.ResponseWriter, r *http.Request, key string) {
+	tl := GetDefaultTranslation()
+
+	switch r.Form.Get("discussion") {
+	case "add":
+		if p.Closed() {
+			rw.WriteHeader(http.StatusForbidden)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(p.closedMessage(tl))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if r.Form.Get("dsgvo") == "" {
+			rw.WriteHeader(http.StatusForbidden)
+			t := textTemplateStruct{"403 Forbidden", tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		text := strings.TrimSpace(r.Form.Get("text"))
+		if text == "" || utf8.RuneCountInString(text) > discussionMaxLength {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{"400 Bad Request", tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		name := strings.TrimSpace(r.Form.Get("name"))
+		if utf8.RuneCountInString(name) > discussionMaxNameLength {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{"400 Bad Request", tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if !filterContent(&name, &text) {
+			writeContentFiltered(rw)
+			return
+		}
+
+		if instanceFull(false) {
+			writeInstanceFull(rw)
+			return
+		}
+
+		n, _, _, _, err := safe.GetDiscussion(key)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if len(n) >= discussionMaxEntries {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.DiscussionFull)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		_, err = safe.SaveDiscussionEntry(key, name, text)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+	case "delete":
+		if !checkCreator(rw, r, key, true) {
+			return
+		}
+
+		err := safe.DeleteDiscussionEntry(key, r.Form.Get("entryID"))
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+	default:
+		rw.WriteHeader(http.StatusBadRequest)
+		t := textTemplateStruct{"400 Bad Request", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	http.Redirect(rw, r, fmt.Sprintf("/%s#discussion", key), http.StatusSeeOther)
+}
@@ -0,0 +1,387 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// duplicateMaxCompare is the maximum number of answers which are compared pairwise for similar names.
+// Larger polls only detect names which are equal apart from case, spaces and punctuation.
+const duplicateMaxCompare = 2000
+
+// duplicateMaxGroup is the maximum number of answers which can be merged or deleted at once.
+const duplicateMaxGroup = 100
+
+type duplicateAnswer struct {
+	ID       string
+	Name     string
+	Comment  string
+	Summary  string
+	Modified string
+}
+
+type duplicateTemplateStruct struct {
+	Key         string
+	Groups      [][]duplicateAnswer
+	HasPassword bool
+	User        string
+	CSRF        string
+	Translation Translation
+}
+
+var duplicateTemplate = template.Must(template.New("duplicates").Parse(`
+<h1>{{.Translation.Duplicates}} - {{.Key}}</h1>
+{{if .Groups}}
+<p>{{.Translation.DuplicatesDescription}}</p>
+{{range $group := .Groups}}
+<form method="POST">
+<input type="hidden" name="csrf" value="{{$.CSRF}}">
+<input type="hidden" name="duplicates" value="true">
+<table>
+<thead>
+<tr>
+<th>{{$.Translation.DuplicatesKeep}}</th>
+<th>{{$.Translation.Name}}</th>
+<th>{{$.Translation.Comment}}</th>
+<th>{{$.Translation.Answers}}</th>
+<th>{{$.Translation.DuplicatesModified}}</th>
+</tr>
+</thead>
+<tbody>
+{{range $i, $a := $group}}
+<tr>
+<td class="centre"><input type="radio" name="keep" value="{{$a.ID}}" aria-label="{{$.Translation.DuplicatesKeep}} {{$a.Name}}"{{if eq $i 0}} checked{{end}} required><input type="hidden" name="group" value="{{$a.ID}}"></td>
+<td>{{$a.Name}}</td>
+<td>{{$a.Comment}}</td>
+<td>{{$a.Summary}}</td>
+<td>{{$a.Modified}}</td>
+</tr>
+{{end}}
+</tbody>
+</table>
+{{if $.HasPassword}}
+<table style="border: none;">
+  <tr style="border: none; background-color: inherit;">
+    <td style="border: none;"><label>{{$.Translation.Username}}: <input type="text" name="user" maxlength="500" value="{{$.User}}" required></label></td>
+  </tr>
+  <tr style="border: none; background-color: inherit;">
+    <td style="border: none;"><label>{{$.Translation.Password}}: <input type="password" name="pw" maxlength="500" required></label></td>
+  </tr>
+</table>
+{{end}}
+<p><button type="submit" name="duplicateaction" value="merge" title="{{$.Translation.DuplicatesMergeDescription}}">{{$.Translation.DuplicatesMerge}}</button> <button type="submit" name="duplicateaction" value="delete" title="{{$.Translation.DuplicatesDeleteDescription}}">{{$.Translation.DuplicatesDelete}}</button></p>
+</form>
+<hr>
+{{end}}
+{{else}}
+<p>{{.Translation.NoDuplicates}}</p>
+{{end}}
+<p><a href="/{{.Key}}">{{.Translation.BackToPoll}}</a></p>
+`))
+
+// compactName returns the name without case, spaces and punctuation.
+func compactName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// editDistance returns the Levenshtein distance between a and b. If it is larger than limit, limit+1 is returned.
+func editDistance(a, b []rune, limit int) int {
+	if len(a)-len(b) > limit || len(b)-len(a) > limit {
+		return limit + 1
+	}
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		previous, current = current, previous
+	}
+	return min(previous[len(b)], limit+1)
+}
+
+// similarNames returns whether two compacted names probably belong to the same participant.
+// Short names must be equal, longer names may contain a typo.
+func similarNames(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, rb := []rune(a), []rune(b)
+	shorter := min(len(ra), len(rb))
+	switch {
+	case shorter < 4:
+		return false
+	case shorter < 10:
+		return editDistance(ra, rb, 1) <= 1
+	default:
+		return editDistance(ra, rb, 2) <= 2
+	}
+}
+
+// duplicateGroups returns groups of indices of answers with the same or similar names. Answers without names are never grouped.
+// Groups are ordered by their first answer.
+func duplicateGroups(names []string) [][]int {
+	parent := make([]int, len(names))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		i, j = find(i), find(j)
+		if i < j {
+			parent[j] = i
+		} else if j < i {
+			parent[i] = j
+		}
+	}
+
+	compact := make([]string, len(names))
+	first := make(map[string]int)
+	for i := range names {
+		compact[i] = compactName(names[i])
+		if compact[i] == "" {
+			continue
+		}
+		if f, ok := first[compact[i]]; ok {
+			union(f, i)
+			continue
+		}
+		first[compact[i]] = i
+	}
+
+	if len(names) <= duplicateMaxCompare {
+		// Only one answer per distinct name needs to be compared
+		distinct := make([]int, 0, len(first))
+		for _, i := range first {
+			distinct = append(distinct, i)
+		}
+		for x := range distinct {
+			for y := x + 1; y < len(distinct); y++ {
+				if similarNames(compact[distinct[x]], compact[distinct[y]]) {
+					union(distinct[x], distinct[y])
+				}
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	roots := make([]int, 0)
+	for i := range names {
+		if compact[i] == "" {
+			continue
+		}
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+
+	groups := make([][]int, 0)
+	for _, root := range roots {
+		if len(members[root]) > 1 {
+			groups = append(groups, members[root])
+		}
+	}
+	return groups
+}
+
+// handleDuplicates shows groups of probable duplicate answers of a poll ('duplicates=true') and merges or deletes them ('duplicateaction').
+// Access is only allowed for the creator of the poll.
+func (p Poll) handleDuplicates(rw http.ResponseWriter, r *http.Request, key string) {
+	tl := GetDefaultTranslation()
+
+	if !checkCreator(rw, r, key, true) {
+		return
+	}
+
+	if action := r.Form.Get("duplicateaction"); action != "" {
+		status, err := p.resolveDuplicates(r, key, action, r.Form.Get("keep"), r.Form["group"])
+		if err != nil {
+			rw.WriteHeader(status)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(errorText(status, err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+	}
+
+	results, names, comments, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	created, modified, err := safe.GetAnswerTimes(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	td := duplicateTemplateStruct{
+		Key:         key,
+		Groups:      make([][]duplicateAnswer, 0),
+		HasPassword: managementRequiresPassword(r),
+		User:        r.Form.Get("user"),
+		CSRF:        csrfToken(rw, r),
+		Translation: tl,
+	}
+	for _, group := range duplicateGroups(names) {
+		g := make([]duplicateAnswer, 0, len(group))
+		for _, i := range group[:min(len(group), duplicateMaxGroup)] {
+			summary := make([]string, len(results[i]))
+			for q := range results[i] {
+				summary[q] = p.answerText(results[i][q])
+			}
+			changed := modified[ids[i]]
+			if changed.IsZero() {
+				changed = created[ids[i]]
+			}
+			a := duplicateAnswer{ID: ids[i], Name: names[i], Comment: comments[i], Summary: strings.Join(summary, " | ")}
+			if !changed.IsZero() {
+				a.Modified = FormatTimeDisplay(changed, config.DateTimeDisplayFormat)
+			}
+			g = append(g, a)
+		}
+		td.Groups = append(td.Groups, g)
+	}
+
+	buf := bytes.Buffer{}
+	err = duplicateTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("duplicates: %s", err.Error())
+	}
+	t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
+
+// resolveDuplicates keeps the answer keep of the group and deletes all other answers of the group.
+// With action "merge", the kept answer takes the results of the most recently changed answer of the group and the comments of all answers.
+// With action "delete", the kept answer is not changed.
+// On error, the HTTP status to report is returned as well.
+func (p Poll) resolveDuplicates(r *http.Request, key, action, keep string, group []string) (int, error) {
+	tl := GetDefaultTranslation()
+	if action != "merge" && action != "delete" {
+		return http.StatusBadRequest, errors.New(tl.DuplicatesInvalid)
+	}
+	if len(group) < 2 || len(group) > duplicateMaxGroup {
+		return http.StatusBadRequest, errors.New(tl.DuplicatesInvalid)
+	}
+
+	results, names, comments, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	_, modified, err := safe.GetAnswerTimes(key)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	index := make(map[string]int, len(ids))
+	for i := range ids {
+		index[ids[i]] = i
+	}
+
+	// The group must only contain existing answers, so nothing is deleted if the answers changed in between
+	seen := make(map[string]bool, len(group))
+	keepFound := false
+	latest := -1
+	for _, id := range group {
+		i, ok := index[id]
+		if !ok || seen[id] {
+			return http.StatusConflict, errors.New(tl.DuplicatesInvalid)
+		}
+		seen[id] = true
+		keepFound = keepFound || id == keep
+		if latest == -1 || modified[id].After(modified[ids[latest]]) {
+			latest = i
+		}
+	}
+	if !keepFound {
+		return http.StatusBadRequest, errors.New(tl.DuplicatesInvalid)
+	}
+
+	if action == "merge" {
+		merged := make([]string, 0, len(group))
+		for _, id := range group {
+			c := strings.TrimSpace(comments[index[id]])
+			if c != "" && !slices.Contains(merged, c) {
+				merged = append(merged, c)
+			}
+		}
+		change, err := safe.GetChange(key, keep)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		comment := ""
+		if !p.DisableComments {
+			comment = strings.Join(merged, "; ")
+		}
+		err = safe.OverwritePollResult(key, keep, names[index[keep]], comment, results[latest], change)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		hookAnswerSaved(key, keep, true)
+	}
+
+	for _, id := range group {
+		if id == keep {
+			continue
+		}
+		err = safe.DeleteAnswer(key, id)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		countAnswerDeletion(r, key)
+		recordPollEvent(key, pollEventDeleted, id, names[index[id]])
+	}
+	markAggregateDirty(key)
+	publishLiveUpdate(key, liveAnswerDeleted)
+	return http.StatusOK, nil
+}
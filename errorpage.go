@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// Internal errors might contain paths, DSNs or other details of the instance.
+// They are logged together with a unique ID and only the ID is shown to the visitor, unless 'DebugErrors' is set.
+
+// newErrorID returns a random ID identifying an error in the log.
+func newErrorID() string {
+	b := make([]byte, 6)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// internalErrorText logs the internal error err and returns the text shown to the visitor.
+func internalErrorText(err error) string {
+	id := newErrorID()
+	log.Printf("error %s: %s", id, err.Error())
+	text := fmt.Sprintf(GetDefaultTranslation().InternalError, id)
+	if config.DebugErrors {
+		text = fmt.Sprintf("%s (%s)", text, err.Error())
+	}
+	return text
+}
+
+// errorText returns the text shown to the visitor for err reported with the HTTP status.
+// Server errors are handled by internalErrorText, all other errors are shown as they are.
+func errorText(status int, err error) string {
+	if status >= http.StatusInternalServerError {
+		return internalErrorText(err)
+	}
+	return err.Error()
+}
+
+// recoverHandler shows an error page instead of closing the connection if h panics.
+func recoverHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				// Used to abort the response on purpose
+				panic(v)
+			}
+			text := internalErrorText(fmt.Errorf("panic serving %s: %v\n%s", r.URL.Path, v, debug.Stack()))
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(text)), GetDefaultTranslation(), config.ServerPath}
+			textTemplate.Execute(rw, t)
+		}()
+		h.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+)
+
+// The activity log of a poll records who created the poll, who answered, changed or deleted an answer and when the poll was closed or edited.
+// It allows creators to resolve disputes about changed answers. It is only shown to the creator.
+
+// Types of activity log events. They are stored in the data safe, so they must not be changed.
+const (
+	pollEventCreated    = "created"
+	pollEventAnswered   = "answered"
+	pollEventEdited     = "edited"
+	pollEventDeleted    = "deleted"
+	pollEventClosed     = "closed"
+	pollEventReopened   = "reopened"
+	pollEventPollEdited = "polledited"
+)
+
+type pollEventRow struct {
+	Time     string
+	Event    string
+	Name     string
+	AnswerID string
+}
+
+type pollEventsTemplateStruct struct {
+	Key         string
+	Events      []pollEventRow
+	Translation Translation
+}
+
+var pollEventsTemplate = template.Must(template.New("events").Parse(`
+<h1>{{.Translation.ActivityLog}}: <a href="/{{.Key}}">{{.Key}}</a></h1>
+{{if .Events}}
+<div style="width: 100%; overflow-x: auto;">
+<table>
+<thead>
+<tr>
+<th>{{.Translation.Time}}</th>
+<th>{{.Translation.ActivityLogEvent}}</th>
+<th>{{.Translation.Name}}</th>
+<th>{{.Translation.ActivityLogAnswer}}</th>
+</tr>
+</thead>
+<tbody>
+{{range .Events}}
+<tr>
+<td>{{.Time}}</td>
+<td>{{.Event}}</td>
+<td>{{.Name}}</td>
+<td>{{.AnswerID}}</td>
+</tr>
+{{end}}
+</tbody>
+</table>
+</div>
+{{else}}
+<p>{{.Translation.ActivityLogEmpty}}</p>
+{{end}}
+`))
+
+// recordPollEvent adds an event to the activity log of the poll stored under key.
+// Errors are only logged, a missing log entry must not fail the action itself.
+func recordPollEvent(key, event, answerID, actor string) {
+	err := safe.SavePollEvent(key, event, answerID, actor)
+	if err != nil {
+		log.Printf("activity log (%s): can not save event %s: %s", key, event, err.Error())
+	}
+}
+
+// recordAnswerEvent adds an event about the answer answerID to the activity log. The name of the answer is used as actor.
+func recordAnswerEvent(key, event, answerID string) {
+	_, name, _, err := safe.GetSinglePollResult(key, answerID)
+	if err != nil {
+		log.Printf("activity log (%s): can not get name of answer %s: %s", key, answerID, err.Error())
+	}
+	recordPollEvent(key, event, answerID, name)
+}
+
+// pollEventText returns the translated description of an activity log event.
+func pollEventText(tl Translation, event string) string {
+	switch event {
+	case pollEventCreated:
+		return tl.ActivityLogCreated
+	case pollEventAnswered:
+		return tl.ActivityLogAnswered
+	case pollEventEdited:
+		return tl.ActivityLogEdited
+	case pollEventDeleted:
+		return tl.ActivityLogDeleted
+	case pollEventClosed:
+		return tl.ActivityLogClosed
+	case pollEventReopened:
+		return tl.ActivityLogReopened
+	case pollEventPollEdited:
+		return tl.ActivityLogPollEdited
+	}
+	return event
+}
+
+// handlePollEvents shows the activity log of the poll stored under key. The caller must ensure that the request is made by the creator.
+func handlePollEvents(rw http.ResponseWriter, key string) {
+	tl := GetDefaultTranslation()
+
+	events, answerIDs, actors, times, err := safe.GetPollEvents(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	if len(events) != len(answerIDs) || len(events) != len(actors) || len(events) != len(times) {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(fmt.Errorf("handlePollEvents (%s): inconsistent activity log", key)))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	td := pollEventsTemplateStruct{
+		Key:         key,
+		Events:      make([]pollEventRow, len(events)),
+		Translation: tl,
+	}
+	for i := range events {
+		td.Events[i] = pollEventRow{
+			Time:     times[i].Format(config.DateTimeDisplayFormat),
+			Event:    pollEventText(tl, events[i]),
+			Name:     actors[i],
+			AnswerID: answerIDs[i],
+		}
+	}
+
+	buf := bytes.Buffer{}
+	err = pollEventsTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("activity log: %s", err.Error())
+	}
+	t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// expiryCheckInterval is the interval in which expired polls are archived or deleted.
+const expiryCheckInterval = time.Hour
+
+// Possible values of 'ExpiredPolls'.
+const (
+	expiredPollsKeep    = ""
+	expiredPollsArchive = "archive"
+	expiredPollsDelete  = "delete"
+)
+
+// Expired returns whether the expiry date of the poll has passed.
+func (p Poll) Expired() bool {
+	return !p.Expiry.IsZero() && time.Now().After(p.Expiry)
+}
+
+// archivePoll writes the poll together with all answers to 'ArchivePath'.
+func (p Poll) archivePoll(key string) error {
+	b, err := p.ExportFull(key)
+	if err != nil {
+		return err
+	}
+	name := strings.NewReplacer("/", "-", "\\", "-").Replace(key)
+	path := filepath.Join(config.ArchivePath, fmt.Sprintf("%s-%s.json", name, time.Now().Format("20060102-150405")))
+	return os.WriteFile(path, b, 0600)
+}
+
+// processExpiredPoll archives and / or deletes a single expired poll depending on 'ExpiredPolls'.
+func processExpiredPoll(key string) error {
+	b, err := safe.GetPollConfig(key)
+	if err != nil {
+		return err
+	}
+	p, err := LoadPoll(b)
+	if err != nil {
+		return err
+	}
+
+	if !p.initialised || p.Deleted || p.Expiry.IsZero() {
+		return safe.SetExpiry(key, time.Time{})
+	}
+	if !p.Expired() {
+		// The expiry was moved since it was scheduled
+		return safe.SetExpiry(key, p.Expiry)
+	}
+
+	if config.ExpiredPolls == expiredPollsArchive {
+		err = p.archivePoll(key)
+		if err != nil {
+			return err
+		}
+	}
+	err = p.deletePoll(key)
+	if err != nil {
+		return err
+	}
+	return safe.SetExpiry(key, time.Time{})
+}
+
+// processExpiredPolls archives and / or deletes all expired polls depending on 'ExpiredPolls'.
+// Deleted polls are removed from the data safe on the next garbage collection.
+func processExpiredPolls() {
+	if config.ExpiredPolls == expiredPollsKeep {
+		return
+	}
+
+	keys, err := safe.GetExpiredPolls(time.Now())
+	if err != nil {
+		log.Printf("expiry: can not get expired polls: %s", err.Error())
+		return
+	}
+
+	n := 0
+	for _, key := range keys {
+		err = processExpiredPoll(key)
+		if err != nil {
+			log.Printf("expiry: can not process expired poll %s: %s", key, err.Error())
+			continue
+		}
+		n++
+	}
+	if n != 0 {
+		log.Printf("expiry: processed %d expired polls (%s)", n, config.ExpiredPolls)
+	}
+}
+
+// expiryWorker periodically archives or deletes expired polls. It never returns.
+func expiryWorker() {
+	t := time.NewTicker(expiryCheckInterval)
+	defer t.Stop()
+	for {
+		processExpiredPolls()
+		<-t.C
+	}
+}
@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// followUpCheckInterval is the interval in which due follow-up polls are created.
+const followUpCheckInterval = 5 * time.Minute
+
+// followUpMaxDays is the maximum interval of follow-up polls in days.
+const followUpMaxDays = 366
+
+// followUpKeyAttempts is the number of keys tried for a follow-up poll before giving up.
+const followUpKeyAttempts = 10
+
+// followUpSuffix matches the date suffix added to the keys of follow-up polls.
+var followUpSuffix = regexp.MustCompile(`-\d{4}-\d{2}-\d{2}(-\d+)?$`)
+
+// errFollowUpKeyTaken is returned if no free key for a follow-up poll could be found.
+var errFollowUpKeyTaken = errors.New("no free key for follow-up poll")
+
+// followUpPoll returns the follow-up of the date poll with all dates and the deadline shifted by the interval of the follow-up.
+// The poll must have a follow-up.
+func (p Poll) followUpPoll() Poll {
+	days := p.FollowUp.Days
+	n := p
+	n.Questions = make([]string, len(p.Questions))
+	n.Sections = nil
+	n.Deadline = p.Deadline.AddDate(0, 0, days)
+	if !p.Expiry.IsZero() {
+		n.Expiry = p.Expiry.AddDate(0, 0, days)
+	}
+	n.Hidden = false
+	n.Locked = false
+	n.CreatorClosed = false
+	n.FinalDate = 0
+	n.InvitationsSent = nil
+	n.Dates = make([]PollDate, len(p.Dates))
+	n.FollowUp = &PollFollowUp{Days: days}
+
+	tl := GetDefaultTranslation()
+	lastWeek := -1
+	for i := range p.Dates {
+		t := p.Dates[i].Time.AddDate(0, 0, days)
+		n.Dates[i] = PollDate{Time: t, NoTime: p.Dates[i].NoTime}
+		if p.Dates[i].NoTime {
+			n.Questions[i] = FormatTimeDisplay(t, config.DateDisplayFormat)
+		} else {
+			n.Questions[i] = FormatTimeDisplay(t, config.DateTimeDisplayFormat)
+		}
+		// Grouping by calendar week must be redone as the shifted dates might fall into other weeks
+		if _, week := t.ISOWeek(); len(p.Sections) != 0 && week != lastWeek {
+			n.Sections = append(n.Sections, PollSection{Title: fmt.Sprintf("%s %d", tl.CalendarWeek, week), Start: i})
+			lastWeek = week
+		}
+	}
+	if len(n.Sections) == 1 {
+		n.Sections = nil
+	}
+	return n
+}
+
+// followUpKey returns a free key for the follow-up of the poll.
+// The key is derived from the key of the poll and the deadline of the follow-up.
+func followUpKey(key string, deadline time.Time) (string, error) {
+	base := fmt.Sprintf("%s-%s", followUpSuffix.ReplaceAllString(key, ""), deadline.Format("2006-01-02"))
+	for i := 0; i < followUpKeyAttempts; i++ {
+		k := base
+		if i > 0 {
+			k = fmt.Sprintf("%s-%d", base, i+1)
+		}
+		b, err := safe.GetPollConfig(k)
+		if err != nil {
+			return "", err
+		}
+		if len(b) == 0 {
+			return k, nil
+		}
+	}
+	return "", errFollowUpKeyTaken
+}
+
+// createFollowUp creates the follow-up poll of a single poll and notifies the creator.
+func createFollowUp(key string) error {
+	b, err := safe.GetPollConfig(key)
+	if err != nil {
+		return err
+	}
+	p, err := LoadPoll(b)
+	if err != nil {
+		return err
+	}
+
+	if p.Deleted || p.FollowUp == nil || p.FollowUp.Created != "" {
+		return safe.SetFollowUp(key, time.Time{})
+	}
+	if p.Deadline.After(time.Now()) {
+		// The deadline was moved since the follow-up was scheduled
+		return safe.SetFollowUp(key, p.Deadline)
+	}
+	if instanceFull(true) {
+		// Retry later
+		return errors.New("instance is full")
+	}
+
+	n := p.followUpPoll()
+	newKey, err := followUpKey(key, n.Deadline)
+	if err != nil {
+		return err
+	}
+	nb, err := n.ExportPoll()
+	if err != nil {
+		return err
+	}
+	err = safe.SavePollConfig(newKey, nb)
+	if err != nil {
+		return err
+	}
+
+	creator, err := safe.GetPollCreator(key)
+	if err != nil {
+		return err
+	}
+	if creator != "" {
+		err = safe.SavePollCreator(newKey, creator)
+		if err != nil {
+			return err
+		}
+	}
+	organizers, err := safe.GetPollOrganizers(key)
+	if err != nil {
+		return err
+	}
+	if len(organizers) != 0 {
+		err = safe.SavePollOrganizers(newKey, organizers)
+		if err != nil {
+			return err
+		}
+	}
+	err = safe.SetFollowUp(newKey, n.Deadline)
+	if err != nil {
+		return err
+	}
+	if config.ReminderWebhook != "" {
+		err = safe.SetReminder(newKey, n.Deadline.Add(-time.Duration(config.ReminderHours)*time.Hour))
+		if err != nil {
+			return err
+		}
+	}
+	if !n.Expiry.IsZero() {
+		err = safe.SetExpiry(newKey, n.Expiry)
+		if err != nil {
+			return err
+		}
+	}
+	hookPollCreated(newKey, creator)
+
+	p.FollowUp.Created = newKey
+	b, err = p.ExportPoll()
+	if err != nil {
+		return err
+	}
+	err = safe.SavePollConfig(key, b)
+	if err != nil {
+		return err
+	}
+	err = safe.SetFollowUp(key, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("follow-up: created %s for %s", newKey, key)
+	notifyFollowUp(creator, key, newKey)
+	return nil
+}
+
+// notifyFollowUp notifies the creator about a created follow-up poll by email.
+// Creators are only notified if their username is an email address and an SMTP server is configured.
+func notifyFollowUp(creator, key, newKey string) {
+	if config.SMTPServer == "" || !validMailAddress(creator) {
+		return
+	}
+	tl := GetDefaultTranslation()
+	link := strings.Join([]string{config.PublicURL, "/", newKey}, "")
+	err := sendMail(creator, fmt.Sprintf("%s: %s", config.InstanceName, tl.FollowUpCreated), fmt.Sprintf(tl.FollowUpMailText, key, link))
+	if err != nil {
+		log.Printf("follow-up: can not notify creator of %s: %s", key, err.Error())
+	}
+}
+
+// createDueFollowUps creates all follow-up polls which are due.
+// Follow-up polls which could not be created are retried later.
+func createDueFollowUps() {
+	keys, err := safe.GetDueFollowUps(time.Now())
+	if err != nil {
+		log.Printf("follow-up: can not get due follow-up polls: %s", err.Error())
+		return
+	}
+
+	for _, key := range keys {
+		err = createFollowUp(key)
+		if err != nil {
+			log.Printf("follow-up: can not create follow-up poll for %s: %s", key, err.Error())
+		}
+	}
+}
+
+// followUpWorker periodically creates due follow-up polls. It never returns.
+func followUpWorker() {
+	t := time.NewTicker(followUpCheckInterval)
+	defer t.Stop()
+	for {
+		createDueFollowUps()
+		<-t.C
+	}
+}
@@ -16,15 +16,13 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
 	"html/template"
+	"regexp"
 	"time"
 
+	"github.com/Top-Ranger/pollgo/registry"
 	"github.com/microcosm-cc/bluemonday"
-	"github.com/yuin/goldmark"
-	"github.com/yuin/goldmark/extension"
-	"github.com/yuin/goldmark/renderer/html"
 )
 
 var policy *bluemonday.Policy
@@ -37,19 +35,26 @@ func init() {
 	policy.AllowAttrs("href").OnElements("a")
 	policy.RequireNoReferrerOnLinks(true)
 	policy.AllowTables()
-	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	policy.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	policy.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input") // GFM task lists
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	policy.AddTargetBlankToFullyQualifiedLinks(true) // also adds rel="noopener"
 }
 
-// Format returns a save html version of the Markdown input.
+// formatter renders all texts. It is selected through 'Formatter'.
+var formatter registry.Formatter
+
+// Format returns a save html version of the input, rendered by the configured formatter (Markdown by default).
+// Raw HTML is not supported.
 func Format(b []byte) template.HTML {
-	buf := bytes.NewBuffer(make([]byte, 0, len(b)*2))
-	md := goldmark.New(goldmark.WithExtensions(extension.GFM), goldmark.WithRendererOptions(html.WithHardWraps()))
-	err := md.Convert(b, buf)
+	out, err := formatter.Format(b)
 	if err != nil {
-		return template.HTML(policy.Sanitize(fmt.Sprintf("Error rendering markdown: %s", err.Error())))
+		return template.HTML(policy.Sanitize(fmt.Sprintf("Error rendering text: %s", err.Error())))
 	}
 
-	return template.HTML(policy.SanitizeBytes(buf.Bytes()))
+	return template.HTML(policy.SanitizeBytes(out))
 }
 
 // FormatTimeDisplay returns a translated representation of the date.
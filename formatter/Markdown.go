@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formatter
+
+import (
+	"bytes"
+
+	"github.com/Top-Ranger/pollgo/registry"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// Markdown is a Formatter which parses the input as CommonMark with GitHub Flavored Markdown extensions (tables, task lists, strikethrough, autolinks).
+// Raw HTML is removed by the sanitiser. It needs no configuration.
+type Markdown struct {
+	md goldmark.Markdown
+}
+
+func init() {
+	err := registry.RegisterFormatter(&Markdown{}, "Markdown")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// LoadConfig initialises the renderer. The configuration is ignored.
+func (m *Markdown) LoadConfig(b []byte) error {
+	m.md = goldmark.New(
+		goldmark.WithExtensions(extension.Linkify, extension.Strikethrough, extension.TaskList, extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute))),
+		goldmark.WithRendererOptions(html.WithHardWraps()),
+	)
+	return nil
+}
+
+// Format renders the Markdown input as HTML.
+func (m *Markdown) Format(b []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, len(b)*2))
+	err := m.md.Convert(b, buf)
+	return buf.Bytes(), err
+}
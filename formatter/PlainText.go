@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formatter
+
+import (
+	"bytes"
+	"html"
+	"strings"
+
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+// PlainText is a restricted Formatter which shows the input as it is. No markup is interpreted.
+// Empty lines separate paragraphs, all other line breaks are kept. It needs no configuration.
+type PlainText struct{}
+
+func init() {
+	err := registry.RegisterFormatter(&PlainText{}, "PlainText")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// LoadConfig does nothing, as PlainText has no configuration.
+func (p *PlainText) LoadConfig(b []byte) error {
+	return nil
+}
+
+// Format returns the escaped input as HTML paragraphs.
+func (p *PlainText) Format(b []byte) ([]byte, error) {
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	buf := bytes.NewBuffer(make([]byte, 0, len(b)*2))
+	for _, paragraph := range bytes.Split(b, []byte("\n\n")) {
+		paragraph = bytes.Trim(paragraph, "\n")
+		if len(paragraph) == 0 {
+			continue
+		}
+		buf.WriteString("<p>")
+		buf.WriteString(strings.ReplaceAll(html.EscapeString(string(paragraph)), "\n", "<br>\n"))
+		buf.WriteString("</p>\n")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// errFormListCount is returned if the number of entries of a form list is missing or invalid.
+var errFormListCount = errors.New("invalid number of entries")
+
+// errFormListTooLarge is returned if a form list contains more entries than allowed.
+var errFormListTooLarge = errors.New("too many entries")
+
+// formListEntry is a single entry of a form list.
+// Values contains the values of all requested fields in the requested order, the first one is never empty.
+type formListEntry struct {
+	ID     int
+	Values []string
+}
+
+// parseFormList reads a list of numbered fields as sent by the poll creation form.
+// The field countField contains the highest used number, the entries are named '<field>1', '<field>2', ... for every field in fields.
+// Entries where the first field is empty are skipped.
+// At most limit entries are returned. To allow for a few blank fields, the count may be up to twice the limit.
+func parseFormList(form url.Values, countField string, limit int, fields ...string) ([]formListEntry, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("parseFormList: no fields given")
+	}
+	count, err := strconv.Atoi(form.Get(countField))
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("%w (%s)", errFormListCount, countField)
+	}
+	if count > limit*2 {
+		return nil, errFormListTooLarge
+	}
+
+	entries := make([]formListEntry, 0)
+	// The form might count one entry less than it sends
+	for id := 1; id <= count+1; id++ {
+		first := form.Get(fmt.Sprintf("%s%d", fields[0], id))
+		if first == "" {
+			continue
+		}
+		if len(entries) == limit {
+			return nil, errFormListTooLarge
+		}
+		e := formListEntry{ID: id, Values: make([]string, len(fields))}
+		e.Values[0] = first
+		for i := 1; i < len(fields); i++ {
+			e.Values[i] = form.Get(fmt.Sprintf("%s%d", fields[i], id))
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// writeFormListError writes the error returned by parseFormList to rw.
+func writeFormListError(rw http.ResponseWriter, err error) {
+	rw.WriteHeader(http.StatusBadRequest)
+	tl := GetDefaultTranslation()
+	msg := err.Error()
+	if errors.Is(err, errFormListTooLarge) {
+		msg = tl.PollToLargeError
+	}
+	t := textTemplateStruct{template.HTML(template.HTMLEscapeString(msg)), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
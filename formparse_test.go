@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestParseFormList(t *testing.T) {
+	tests := []struct {
+		name    string
+		form    url.Values
+		limit   int
+		ids     []int
+		values  [][]string
+		wantErr error
+	}{
+		{
+			name:   "simple",
+			form:   url.Values{"q": {"2"}, "q1": {"a"}, "v1": {"1"}, "q2": {"b"}, "v2": {"2"}},
+			limit:  10,
+			ids:    []int{1, 2},
+			values: [][]string{{"a", "1"}, {"b", "2"}},
+		},
+		{
+			name:   "one entry more than counted",
+			form:   url.Values{"q": {"1"}, "q1": {"a"}, "q2": {"b"}},
+			limit:  10,
+			ids:    []int{1, 2},
+			values: [][]string{{"a", ""}, {"b", ""}},
+		},
+		{
+			name:   "entries beyond count+1 are ignored",
+			form:   url.Values{"q": {"1"}, "q1": {"a"}, "q3": {"c"}},
+			limit:  10,
+			ids:    []int{1},
+			values: [][]string{{"a", ""}},
+		},
+		{
+			name:   "gaps are skipped",
+			form:   url.Values{"q": {"5"}, "q1": {"a"}, "q2": {""}, "v2": {"ignored"}, "q4": {"d"}, "v4": {"4"}},
+			limit:  10,
+			ids:    []int{1, 4},
+			values: [][]string{{"a", ""}, {"d", "4"}},
+		},
+		{
+			name:   "zero count",
+			form:   url.Values{"q": {"0"}, "q1": {"a"}},
+			limit:  10,
+			ids:    []int{1},
+			values: [][]string{{"a", ""}},
+		},
+		{
+			name:   "no entries",
+			form:   url.Values{"q": {"3"}},
+			limit:  10,
+			ids:    []int{},
+			values: [][]string{},
+		},
+		{
+			name:    "missing count",
+			form:    url.Values{"q1": {"a"}},
+			limit:   10,
+			wantErr: errFormListCount,
+		},
+		{
+			name:    "invalid count",
+			form:    url.Values{"q": {"abc"}, "q1": {"a"}},
+			limit:   10,
+			wantErr: errFormListCount,
+		},
+		{
+			name:    "negative count",
+			form:    url.Values{"q": {"-1"}, "q1": {"a"}},
+			limit:   10,
+			wantErr: errFormListCount,
+		},
+		{
+			name:    "huge count",
+			form:    url.Values{"q": {"99999999999999999999"}, "q1": {"a"}},
+			limit:   10,
+			wantErr: errFormListCount,
+		},
+		{
+			name:    "count above twice the limit",
+			form:    url.Values{"q": {"21"}, "q1": {"a"}},
+			limit:   10,
+			wantErr: errFormListTooLarge,
+		},
+		{
+			name:   "count at twice the limit",
+			form:   url.Values{"q": {"20"}, "q1": {"a"}},
+			limit:  10,
+			ids:    []int{1},
+			values: [][]string{{"a", ""}},
+		},
+		{
+			name:   "entry limit reached",
+			form:   url.Values{"q": {"4"}, "q1": {"a"}, "q2": {"b"}},
+			limit:  2,
+			ids:    []int{1, 2},
+			values: [][]string{{"a", ""}, {"b", ""}},
+		},
+		{
+			name:    "entry limit exceeded",
+			form:    url.Values{"q": {"4"}, "q1": {"a"}, "q2": {"b"}, "q3": {"c"}},
+			limit:   2,
+			wantErr: errFormListTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := parseFormList(tt.form, "q", tt.limit, "q", "v")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entries) != len(tt.ids) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.ids))
+			}
+			for i := range entries {
+				if entries[i].ID != tt.ids[i] {
+					t.Errorf("entry %d: got ID %d, want %d", i, entries[i].ID, tt.ids[i])
+				}
+				if !slices.Equal(entries[i].Values, tt.values[i]) {
+					t.Errorf("entry %d: got values %v, want %v", i, entries[i].Values, tt.values[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseFormListNoFields(t *testing.T) {
+	_, err := parseFormList(url.Values{"q": {"1"}}, "q", 10)
+	if err == nil {
+		t.Fatal("expected error without fields")
+	}
+}
+
+func FuzzParseFormList(f *testing.F) {
+	f.Add("2", "a", "", "b", 10)
+	f.Add("-1", "a", "b", "c", 10)
+	f.Add("99999999999999999999", "a", "b", "c", 1)
+	f.Add("0", "", "", "", 0)
+	f.Add("3", "a", "b", "c", 2)
+	f.Fuzz(func(t *testing.T, count, v1, v2, v3 string, limit int) {
+		if limit < 0 || limit > 1000 {
+			t.Skip()
+		}
+		form := url.Values{"q": {count}, "q1": {v1}, "q2": {v2}, "q3": {v3}}
+		entries, err := parseFormList(form, "q", limit, "q", "v")
+		if err != nil {
+			if entries != nil {
+				t.Fatalf("entries returned together with error %v", err)
+			}
+			return
+		}
+		n, convErr := strconv.Atoi(count)
+		if convErr != nil || n < 0 || n > limit*2 {
+			t.Fatalf("count %q accepted with limit %d", count, limit)
+		}
+		if len(entries) > limit {
+			t.Fatalf("got %d entries, limit is %d", len(entries), limit)
+		}
+		previous := 0
+		for _, e := range entries {
+			if e.ID <= previous || e.ID > n+1 {
+				t.Fatalf("invalid ID %d (previous %d, count %d)", e.ID, previous, n)
+			}
+			previous = e.ID
+			if e.Values[0] == "" {
+				t.Fatalf("entry %d has an empty first value", e.ID)
+			}
+			if e.Values[0] != form.Get("q"+strconv.Itoa(e.ID)) {
+				t.Fatalf("entry %d has value %q, form has %q", e.ID, e.Values[0], form.Get("q"+strconv.Itoa(e.ID)))
+			}
+		}
+	})
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// GDPRExport contains all personal data stored with a single answer.
+type GDPRExport struct {
+	Poll           string
+	AnswerID       string
+	Name           string
+	Comment        string
+	Mail           string `json:",omitempty"`
+	Answers        []GDPRExportAnswer
+	Created        *time.Time `json:",omitempty"`
+	Modified       *time.Time `json:",omitempty"`
+	ConsentTime    *time.Time `json:",omitempty"`
+	ConsentVersion string     `json:",omitempty"`
+}
+
+// GDPRExportAnswer contains the answer to a single question.
+type GDPRExportAnswer struct {
+	Question string
+	Answer   string
+}
+
+// consentVersion returns the version of the consent form of the poll.
+// The version changes whenever the consent text, the linked document or the privacy policy of the instance changes.
+func (p Poll) consentVersion() string {
+	text, url := p.Consent()
+	h := sha256.New()
+	h.Write([]byte(text))
+	h.Write([]byte{0})
+	h.Write([]byte(url))
+	h.Write([]byte{0})
+	h.Write(dsgvo)
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// ownsAnswer returns whether the request carries the edit cookie of the answer.
+func ownsAnswer(r *http.Request, key, answerID string) (bool, error) {
+	change, err := safe.GetChange(key, answerID)
+	if err != nil {
+		return false, err
+	}
+	if change == "" {
+		return false, nil
+	}
+	c, err := r.Cookie(answerID)
+	if err != nil {
+		return false, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(change), []byte(c.Value)) == 0 {
+		if config.LogFailedLogin {
+			log.Printf("Failed authentication from %s", GetRealIP(r))
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// gdprExport returns the personal data of the given answers. All answers are exported if answerIDs is nil.
+func (p Poll) gdprExport(key string, answerIDs []string) ([]GDPRExport, error) {
+	results, names, comments, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		return nil, err
+	}
+	created, modified, err := safe.GetAnswerTimes(key)
+	if err != nil {
+		return nil, err
+	}
+	mails, err := safe.GetAnswerMails(key)
+	if err != nil {
+		return nil, err
+	}
+
+	export := make([]GDPRExport, 0)
+	for i := range ids {
+		if answerIDs != nil && !slices.Contains(answerIDs, ids[i]) {
+			continue
+		}
+		e := GDPRExport{
+			Poll:     key,
+			AnswerID: ids[i],
+			Name:     names[i],
+			Comment:  comments[i],
+			Mail:     mails[ids[i]],
+			Answers:  make([]GDPRExportAnswer, 0, len(results[i])),
+		}
+		for q := range results[i] {
+			if q >= len(p.Questions) || !p.validAnswer(results[i][q]) {
+				continue
+			}
+			e.Answers = append(e.Answers, GDPRExportAnswer{Question: p.Questions[q], Answer: p.answerText(results[i][q])})
+		}
+		if c := created[ids[i]]; !c.IsZero() {
+			e.Created = &c
+		}
+		if m := modified[ids[i]]; !m.IsZero() {
+			e.Modified = &m
+		}
+		t, v, err := safe.GetConsent(key, ids[i])
+		if err != nil {
+			return nil, err
+		}
+		if !t.IsZero() {
+			e.ConsentTime = &t
+			e.ConsentVersion = v
+		}
+		export = append(export, e)
+	}
+	return export, nil
+}
+
+// handleGDPRExport writes the personal data of the given answers as a JSON download.
+// All answers are exported if answerIDs is nil.
+func (p Poll) handleGDPRExport(rw http.ResponseWriter, key string, answerIDs []string) {
+	export, err := p.gdprExport(key, answerIDs)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	b, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.json\"", strings.NewReplacer("\"", "", "/", "-", "\\", "-").Replace(key)))
+	rw.Write(b)
+}
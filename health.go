@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// healthCheckInterval is the interval in which the health of the data safe is checked.
+const healthCheckInterval = 30 * time.Second
+
+var dataSafeUnhealthy atomic.Bool
+
+// checkDataSafeHealth runs the health check of the data safe and logs changes of the state.
+func checkDataSafeHealth() {
+	err := safe.HealthCheck()
+	wasUnhealthy := dataSafeUnhealthy.Swap(err != nil)
+	switch {
+	case err != nil && !wasUnhealthy:
+		log.Printf("health: data safe is unhealthy: %s", err.Error())
+	case err == nil && wasUnhealthy:
+		log.Println("health: data safe recovered")
+	}
+}
+
+// healthWorker periodically checks the health of the data safe. It never returns.
+func healthWorker() {
+	t := time.NewTicker(healthCheckInterval)
+	defer t.Stop()
+	for {
+		<-t.C
+		checkDataSafeHealth()
+	}
+}
+
+// healthHandle reports the health of the data safe for monitoring.
+func healthHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if dataSafeUnhealthy.Load() {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		rw.Write([]byte("degraded"))
+		return
+	}
+	rw.Write([]byte("ok"))
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+// hooks contains all hooks configured through 'Hooks', sorted by name.
+var hooks []namedHook
+
+type namedHook struct {
+	name string
+	hook registry.Hook
+}
+
+// initialiseHooks loads all hooks configured through 'Hooks'.
+func initialiseHooks() error {
+	names := make([]string, 0, len(config.Hooks))
+	for name := range config.Hooks {
+		names = append(names, name)
+	}
+	// Hooks are always called in the same order
+	sort.Strings(names)
+
+	for _, name := range names {
+		h, ok := registry.GetHook(name)
+		if !ok {
+			return fmt.Errorf("unknown hook %s", name)
+		}
+		var b []byte
+		if config.Hooks[name] != "" {
+			var err error
+			b, err = os.ReadFile(config.Hooks[name])
+			if err != nil {
+				return fmt.Errorf("hook %s: %w", name, err)
+			}
+		}
+		err := h.LoadConfig(b)
+		if err != nil {
+			return fmt.Errorf("hook %s: %w", name, err)
+		}
+		hooks = append(hooks, namedHook{name: name, hook: h})
+	}
+	return nil
+}
+
+// hookPollCreated notifies all hooks about a newly created poll. It also adds the creation to the activity log.
+func hookPollCreated(key, creator string) {
+	recordPollEvent(key, pollEventCreated, "", creator)
+	for _, h := range hooks {
+		err := h.hook.OnPollCreated(key, creator)
+		if err != nil {
+			log.Printf("hook %s: OnPollCreated (%s): %s", h.name, key, err.Error())
+		}
+	}
+}
+
+// hookAnswerSaved notifies all hooks about a saved answer. changed is true if an existing answer was overwritten.
+// It also schedules the recomputation of the aggregate of the poll, removes cached pages of the poll, notifies open poll pages and adds the answer to the activity log.
+func hookAnswerSaved(key, answerID string, changed bool) {
+	markAggregateDirty(key)
+	invalidatePageCache(key)
+	if changed {
+		publishLiveUpdate(key, liveAnswerChanged)
+		recordAnswerEvent(key, pollEventEdited, answerID)
+	} else {
+		publishLiveUpdate(key, liveAnswerNew)
+		recordAnswerEvent(key, pollEventAnswered, answerID)
+	}
+	for _, h := range hooks {
+		err := h.hook.OnAnswerSaved(key, answerID, changed)
+		if err != nil {
+			log.Printf("hook %s: OnAnswerSaved (%s, %s): %s", h.name, key, answerID, err.Error())
+		}
+	}
+}
+
+// hookPollDeleted notifies all hooks about a deleted poll. It also removes cached pages of the poll.
+func hookPollDeleted(key string) {
+	invalidatePageCache(key)
+	for _, h := range hooks {
+		err := h.hook.OnPollDeleted(key)
+		if err != nil {
+			log.Printf("hook %s: OnPollDeleted (%s): %s", h.name, key, err.Error())
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+// execMaxOutput is the maximum number of bytes of the output of the program which are kept and logged.
+const execMaxOutput = 1000
+
+// execWaitDelay is the time waited for the output of a killed program to be closed.
+const execWaitDelay = time.Second
+
+// Exec is a Hook which runs an external program for every event. The event is passed as JSON on stdin (see ExecEvent).
+// This allows extensions without recompiling PollGo!. WASM modules can be used by running them through a WASI runtime (e.g. 'wasmtime').
+// The program is started without environment variables in 'Dir' and is killed together with all processes it started after 'TimeoutSeconds'.
+// If 'UID' or 'GID' are set, the program runs as that user and group (Unix only, PollGo! needs the privileges to switch).
+// Programs run in the background, at most 'MaxParallel' at once. If that many programs are running, the event is dropped and logged.
+// The output of failed programs is logged (up to 1000 bytes).
+//
+// Exec is not a sandbox: apart from the user and group, the program has the same access to the system as PollGo!.
+// Operators have to confine it themselves if needed, e.g. by running it through 'systemd-run', 'bwrap' or 'prlimit'.
+// Example configuration:
+//
+//	{
+//	    "Command": ["/usr/local/bin/pollgo-extension", "--verbose"],
+//	    "Dir": "/var/lib/pollgo-extension",
+//	    "TimeoutSeconds": 10,
+//	    "MaxParallel": 4,
+//	    "UID": 65534,
+//	    "GID": 65534,
+//	    "Events": ["PollCreated", "AnswerSaved", "PollDeleted"]
+//	}
+//
+// If 'Events' is empty, the program is run for all events.
+type Exec struct {
+	command   []string
+	dir       string
+	timeout   time.Duration
+	uid       int
+	gid       int
+	events    map[string]bool
+	semaphore chan struct{}
+}
+
+// ExecEvent is passed to the program of the Exec hook.
+type ExecEvent struct {
+	Event    string // PollCreated, AnswerSaved or PollDeleted
+	Time     time.Time
+	Poll     string
+	Creator  string `json:",omitempty"` // only PollCreated
+	AnswerID string `json:",omitempty"` // only AnswerSaved
+	Changed  bool   `json:",omitempty"` // only AnswerSaved, whether an existing answer was changed
+}
+
+type execConfig struct {
+	Command        []string
+	Dir            string
+	TimeoutSeconds int
+	MaxParallel    int
+	UID            int
+	GID            int
+	Events         []string
+}
+
+func init() {
+	err := registry.RegisterHook(&Exec{}, "Exec")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// LoadConfig loads the configuration of the Exec hook.
+func (e *Exec) LoadConfig(b []byte) error {
+	if b == nil {
+		return errors.New("Exec: configuration is required")
+	}
+	var c execConfig
+	err := json.Unmarshal(b, &c)
+	if err != nil {
+		return fmt.Errorf("Exec: %w", err)
+	}
+	if len(c.Command) == 0 || c.Command[0] == "" {
+		return errors.New("Exec: Command must not be empty")
+	}
+	if _, err := exec.LookPath(c.Command[0]); err != nil {
+		return fmt.Errorf("Exec: %w", err)
+	}
+	if c.Dir == "" {
+		c.Dir = os.TempDir()
+	}
+	if c.TimeoutSeconds <= 0 {
+		c.TimeoutSeconds = 10
+	}
+	if c.MaxParallel <= 0 {
+		c.MaxParallel = 4
+	}
+	if c.UID < 0 || c.GID < 0 {
+		return errors.New("Exec: UID and GID must not be negative")
+	}
+	if (c.UID != 0 || c.GID != 0) && !execCredentialsSupported {
+		return errors.New("Exec: UID and GID are not supported on this platform")
+	}
+
+	e.command = c.Command
+	e.dir = c.Dir
+	e.timeout = time.Duration(c.TimeoutSeconds) * time.Second
+	e.uid = c.UID
+	e.gid = c.GID
+	e.semaphore = make(chan struct{}, c.MaxParallel)
+	if len(c.Events) != 0 {
+		e.events = make(map[string]bool, len(c.Events))
+		for _, ev := range c.Events {
+			switch ev {
+			case "PollCreated", "AnswerSaved", "PollDeleted":
+				e.events[ev] = true
+			default:
+				return fmt.Errorf("Exec: unknown event %s", ev)
+			}
+		}
+	}
+	return nil
+}
+
+// OnPollCreated runs the program for a created poll.
+func (e *Exec) OnPollCreated(pollID, creator string) error {
+	return e.run(ExecEvent{Event: "PollCreated", Time: time.Now(), Poll: pollID, Creator: creator})
+}
+
+// OnAnswerSaved runs the program for a saved answer.
+func (e *Exec) OnAnswerSaved(pollID, answerID string, changed bool) error {
+	return e.run(ExecEvent{Event: "AnswerSaved", Time: time.Now(), Poll: pollID, AnswerID: answerID, Changed: changed})
+}
+
+// OnPollDeleted runs the program for a deleted poll.
+func (e *Exec) OnPollDeleted(pollID string) error {
+	return e.run(ExecEvent{Event: "PollDeleted", Time: time.Now(), Poll: pollID})
+}
+
+// cappedBuffer keeps the first max bytes written to it and discards the rest.
+type cappedBuffer struct {
+	b   []byte
+	max int
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	if n := c.max - len(c.b); n > 0 {
+		c.b = append(c.b, p[:min(n, len(p))]...)
+	}
+	return len(p), nil
+}
+
+// run starts the program for the event in the background.
+// If 'MaxParallel' programs are already running, the event is dropped.
+func (e *Exec) run(ev ExecEvent) error {
+	if e.events != nil && !e.events[ev.Event] {
+		return nil
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case e.semaphore <- struct{}{}:
+	default:
+		log.Printf("Exec: %s (%s) dropped, too many programs running", ev.Event, ev.Poll)
+		return nil
+	}
+
+	go func() {
+		defer func() { <-e.semaphore }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+		defer cancel()
+
+		out := &cappedBuffer{max: execMaxOutput}
+		cmd := exec.CommandContext(ctx, e.command[0], e.command[1:]...)
+		configureCommand(cmd, e.uid, e.gid)
+		cmd.WaitDelay = execWaitDelay
+		cmd.Dir = e.dir
+		cmd.Env = []string{}
+		cmd.Stdin = bytes.NewReader(b)
+		cmd.Stdout = out
+		cmd.Stderr = out
+		err := cmd.Run()
+		if err != nil {
+			log.Printf("Exec: %s (%s) failed: %s: %s", ev.Event, ev.Poll, err.Error(), out.b)
+		}
+	}()
+	return nil
+}
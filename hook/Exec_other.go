@@ -0,0 +1,28 @@
+//go:build !unix
+
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"os/exec"
+)
+
+// execCredentialsSupported is true if the program can run as another user.
+const execCredentialsSupported = false
+
+// configureCommand does nothing on this platform. On timeout, only the started program is killed.
+func configureCommand(cmd *exec.Cmd, uid, gid int) {}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Top-Ranger/pollgo/helper"
+)
+
+// invitationMaxPerPoll is the maximum number of invitations of a single poll.
+const invitationMaxPerPoll = 1000
+
+// invitationMaxNameLength is the maximum length of the name of an invitee in runes.
+const invitationMaxNameLength = 150
+
+// pollInvitation represents a personal answer link of an invitee.
+type pollInvitation struct {
+	Token    string
+	Name     string
+	AnswerID string // empty if the invitee has not answered or the answer was deleted
+	linked   string // answer ID as stored in the data safe
+}
+
+type invitationTemplateStruct struct {
+	Key         string
+	Invitations []pollInvitation
+	URLs        []string
+	Responded   int
+	Translation Translation
+}
+
+var invitationTemplate = template.Must(template.New("invitations").Parse(`
+<h1>{{.Translation.Invitations}} - {{.Key}}</h1>
+{{if .Invitations}}
+<p>{{.Translation.InvitationsResponded}}: {{.Responded}} / {{len .Invitations}}</p>
+<table>
+<thead>
+<tr>
+<th>{{.Translation.Name}}</th>
+<th>{{.Translation.InvitationLink}}</th>
+<th>{{.Translation.InvitationStatus}}</th>
+</tr>
+</thead>
+<tbody>
+{{range $i, $e := .Invitations}}
+<tr>
+<td>{{$e.Name}}</td>
+<td><input type="text" value="{{index $.URLs $i}}" aria-label="{{$.Translation.InvitationLink}} {{$e.Name}}" readonly onclick="this.select()"></td>
+<td class="centre">{{if $e.AnswerID}}{{$.Translation.InvitationAnswered}}{{else}}<em>{{$.Translation.InvitationPending}}</em>{{end}}</td>
+</tr>
+{{end}}
+</tbody>
+</table>
+{{else}}
+<p>{{.Translation.NoInvitations}}</p>
+{{end}}
+<p><a href="/{{.Key}}">{{.Translation.BackToPoll}}</a></p>
+`))
+
+// getInvitations returns all invitations of a poll.
+// Links to answers which do not exist any longer are removed from AnswerID.
+func getInvitations(key string) ([]pollInvitation, error) {
+	tokens, names, answerIDs, err := safe.GetInvitations(key)
+	if err != nil {
+		return nil, err
+	}
+	_, _, _, existing, err := safe.GetPollResult(key)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]pollInvitation, len(tokens))
+	for i := range tokens {
+		invitations[i] = pollInvitation{Token: tokens[i], Name: names[i], linked: answerIDs[i]}
+		if answerIDs[i] != "" && slices.Contains(existing, answerIDs[i]) {
+			invitations[i].AnswerID = answerIDs[i]
+		}
+	}
+	return invitations, nil
+}
+
+// getInvitation returns the invitation of a poll identified by token.
+// The bool indicates whether it existed.
+func getInvitation(key, token string) (pollInvitation, bool, error) {
+	invitations, err := getInvitations(key)
+	if err != nil {
+		return pollInvitation{}, false, err
+	}
+	for i := range invitations {
+		if subtle.ConstantTimeCompare([]byte(invitations[i].Token), []byte(token)) == 1 {
+			return invitations[i], true, nil
+		}
+	}
+	return pollInvitation{}, false, nil
+}
+
+// invitationURL returns the personal answer link of an invitation.
+func invitationURL(key, token string) string {
+	return strings.Join([]string{config.PublicURL, "/", key, "?answer=yes&invite=", url.QueryEscape(token)}, "")
+}
+
+// handleInvitations adds invitations for all names given in 'names' (one per line) and shows all invitations of the poll.
+// The caller must ensure that the request was made by the creator of the poll.
+func handleInvitations(rw http.ResponseWriter, r *http.Request, key string) {
+	tl := GetDefaultTranslation()
+
+	invitations, err := getInvitations(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	names := make([]string, 0)
+	for _, n := range strings.Split(r.Form.Get("names"), "\n") {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if utf8.RuneCountInString(n) > invitationMaxNameLength {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{"400 Bad Request", tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		names = append(names, n)
+	}
+	if len(invitations)+len(names) > invitationMaxPerPoll {
+		rw.WriteHeader(http.StatusBadRequest)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.TooManyInvitations)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	for _, n := range names {
+		token := helper.GetRandomString()
+		err = safe.SaveInvitation(key, token, n)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		invitations = append(invitations, pollInvitation{Token: token, Name: n})
+	}
+
+	td := invitationTemplateStruct{
+		Key:         key,
+		Invitations: invitations,
+		URLs:        make([]string, len(invitations)),
+		Translation: tl,
+	}
+	for i := range invitations {
+		td.URLs[i] = invitationURL(key, invitations[i].Token)
+		if invitations[i].AnswerID != "" {
+			td.Responded++
+		}
+	}
+
+	buf := bytes.Buffer{}
+	err = invitationTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("invitations: %s", err.Error())
+	}
+	t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
@@ -49,4 +49,64 @@ function savePolls(polls) {
     } catch (e) {
         console.log("error saving polls:", e);
     }
-}
\ No newline at end of file
+}
+function connectPresence(elementID, filling, text) {
+    try {
+        let url = new URL(window.location.href);
+        url.protocol = (url.protocol == "https:") ? "wss:" : "ws:";
+        url.search = "?presence=true";
+        if (filling) {
+            url.search += "&filling=true";
+        }
+        let ws = new WebSocket(url.href);
+        ws.onmessage = function(e) {
+            let n = parseInt(e.data);
+            if (filling) {
+                n--;
+            }
+            let target = document.getElementById(elementID);
+            if (n > 0) {
+                target.textContent = text.replace("%d", n);
+                target.removeAttribute("hidden");
+            } else {
+                target.hidden = true;
+            }
+        };
+    } catch (e) {
+        console.log("error connecting presence:", e);
+    }
+}
+
+function connectLiveUpdates(elementID) {
+    try {
+        let url = new URL(window.location.href);
+        url.search = "?live=true";
+        let source = new EventSource(url.href);
+        let pending = false;
+        source.addEventListener("answer", function() {
+            // Several answers in a short time only reload the results once
+            if (pending) {
+                return;
+            }
+            pending = true;
+            setTimeout(function() {
+                fetch(window.location.href, {credentials: "same-origin"}).then(function(response) {
+                    return response.text();
+                }).then(function(text) {
+                    pending = false;
+                    let page = new DOMParser().parseFromString(text, "text/html");
+                    let results = page.getElementById(elementID);
+                    let target = document.getElementById(elementID);
+                    if (results && target) {
+                        target.replaceWith(results);
+                    }
+                }).catch(function(e) {
+                    pending = false;
+                    console.log("error reloading results:", e);
+                });
+            }, 1000);
+        });
+    } catch (e) {
+        console.log("error connecting live updates:", e);
+    }
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// liveMaxConnections is the maximum number of live update connections per poll.
+const liveMaxConnections = 500
+
+// liveKeepAlive is the interval in which idle connections receive a comment, so proxies do not close them.
+const liveKeepAlive = 30 * time.Second
+
+// liveWriteTimeout is the time a single event may take to be written.
+const liveWriteTimeout = 5 * time.Second
+
+// Types of live update events.
+const (
+	liveAnswerNew     = "new"
+	liveAnswerChanged = "changed"
+	liveAnswerDeleted = "deleted"
+)
+
+var liveMutex sync.Mutex
+var liveSubscribers = make(map[string]map[chan string]bool)
+
+// liveSubscribe registers a new subscriber for the live updates of the poll stored under key.
+// It returns false if the poll has too many subscribers.
+func liveSubscribe(key string) (chan string, bool) {
+	liveMutex.Lock()
+	defer liveMutex.Unlock()
+	subscribers, ok := liveSubscribers[key]
+	if !ok {
+		subscribers = make(map[chan string]bool)
+		liveSubscribers[key] = subscribers
+	}
+	if len(subscribers) >= liveMaxConnections {
+		return nil, false
+	}
+	// Events are dropped for slow subscribers, pages reload all results on the next event anyway
+	c := make(chan string, 4)
+	subscribers[c] = true
+	return c, true
+}
+
+// liveUnsubscribe removes the subscriber c of the poll stored under key.
+func liveUnsubscribe(key string, c chan string) {
+	liveMutex.Lock()
+	defer liveMutex.Unlock()
+	subscribers, ok := liveSubscribers[key]
+	if !ok {
+		return
+	}
+	delete(subscribers, c)
+	if len(subscribers) == 0 {
+		delete(liveSubscribers, key)
+	}
+}
+
+// publishLiveUpdate notifies all subscribers of the poll stored under key about a changed answer.
+// It never blocks.
+func publishLiveUpdate(key, eventType string) {
+	if !config.EnableLiveUpdates {
+		return
+	}
+	liveMutex.Lock()
+	defer liveMutex.Unlock()
+	for c := range liveSubscribers[key] {
+		select {
+		case c <- eventType:
+		default:
+		}
+	}
+}
+
+// stopLiveUpdates closes all live update connections. It is called when the server shuts down, as the server would wait for the connections otherwise.
+func stopLiveUpdates() {
+	liveMutex.Lock()
+	defer liveMutex.Unlock()
+	for key, subscribers := range liveSubscribers {
+		for c := range subscribers {
+			close(c)
+		}
+		delete(liveSubscribers, key)
+	}
+}
+
+// HandleLiveUpdates streams server-sent events to the client whenever an answer of the poll stored under key is saved or deleted.
+// Each event only contains the type of the change, clients reload the results afterwards.
+func HandleLiveUpdates(rw http.ResponseWriter, r *http.Request, key string) {
+	c, ok := liveSubscribe(key)
+	if !ok {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	defer liveUnsubscribe(key, c)
+
+	rc := http.NewResponseController(rw)
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("X-Accel-Buffering", "no")
+	rw.WriteHeader(http.StatusOK)
+
+	write := func(message string) bool {
+		// Errors are ignored if the connection does not support deadlines
+		rc.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+		if _, err := fmt.Fprint(rw, message); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !write(": connected\n\n") {
+		return
+	}
+
+	ticker := time.NewTicker(liveKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case eventType, ok := <-c:
+			if !ok {
+				// Server is shutting down
+				return
+			}
+			if !write(fmt.Sprintf("event: answer\ndata: {\"Type\":%q}\n\n", eventType)) {
+				return
+			}
+		case <-ticker.C:
+			if !write(": keep-alive\n\n") {
+				return
+			}
+		}
+	}
+}
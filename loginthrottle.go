@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+// ErrLoginLocked is returned by authenticate if too many failed logins occurred for the user or the IP.
+var ErrLoginLocked = errors.New("login temporarily locked because of too many failed attempts")
+
+// loginMaxLockout is the maximum duration of a single lockout.
+const loginMaxLockout = 24 * time.Hour
+
+type loginThrottleState struct {
+	failures    int
+	lockouts    int // consecutive lockouts, used to increase the duration of the next lockout
+	lockedUntil time.Time
+	last        time.Time
+}
+
+var loginThrottle = make(map[string]*loginThrottleState)
+var loginThrottleMutex sync.Mutex
+
+// loginThrottleKeys returns the keys of the throttle state for the user and the IP of the request.
+func loginThrottleKeys(r *http.Request, user string) []string {
+	return []string{strings.Join([]string{"user", strings.ToLower(user)}, ":"), strings.Join([]string{"ip", GetRealIP(r)}, ":")}
+}
+
+// authenticate checks the user / password combination through the authenticater.
+func authenticate(r *http.Request, user, pw string) (bool, error) {
+	return authenticateWith(authenticater, r, user, pw)
+}
+
+// authenticateRequest checks the credentials of the request and returns the authenticated user.
+// With 'OIDCIssuer', the login session of the request is used, otherwise the user / password given in the form. The form must already be parsed.
+func authenticateRequest(r *http.Request) (string, bool, error) {
+	if user := sessionUser(r); user != "" {
+		return user, true, nil
+	}
+	if oidcEnabled() {
+		return "", false, nil
+	}
+	user, pw := r.Form.Get("user"), r.Form.Get("pw")
+	if len(user) == 0 || len(pw) == 0 {
+		return "", false, nil
+	}
+	correct, err := authenticate(r, user, pw)
+	return user, correct, err
+}
+
+// authenticateWith checks the user / password combination through the given authenticater.
+// If LoginMaxFailures is set, users and IPs are locked for LoginLockoutMinutes after that many failed attempts.
+// Each consecutive lockout doubles the duration. While locked, ErrLoginLocked is returned without asking the authenticater.
+// Failed attempts are counted for security events (see countLoginFailure) even if the lockout is disabled.
+func authenticateWith(a registry.Authenticater, r *http.Request, user, pw string) (bool, error) {
+	if config.LoginMaxFailures <= 0 {
+		correct, err := a.Authenticate(user, pw)
+		if err == nil && !correct {
+			countLoginFailure(r, user)
+		}
+		return correct, err
+	}
+
+	keys := loginThrottleKeys(r, user)
+	now := time.Now()
+
+	loginThrottleMutex.Lock()
+	for _, k := range keys {
+		if s, ok := loginThrottle[k]; ok && now.Before(s.lockedUntil) {
+			loginThrottleMutex.Unlock()
+			return false, ErrLoginLocked
+		}
+	}
+	loginThrottleMutex.Unlock()
+
+	correct, err := a.Authenticate(user, pw)
+	if err != nil {
+		return correct, err
+	}
+
+	loginThrottleMutex.Lock()
+	defer loginThrottleMutex.Unlock()
+
+	if correct {
+		for _, k := range keys {
+			delete(loginThrottle, k)
+		}
+		return true, nil
+	}
+
+	countLoginFailure(r, user)
+
+	lockout := time.Duration(config.LoginLockoutMinutes) * time.Minute
+	for _, k := range keys {
+		s, ok := loginThrottle[k]
+		if !ok {
+			s = new(loginThrottleState)
+			loginThrottle[k] = s
+		}
+		if now.Sub(s.last) > loginMaxLockout {
+			// Old failures are forgiven
+			s.failures = 0
+			s.lockouts = 0
+		}
+		s.failures++
+		s.last = now
+		if s.failures >= config.LoginMaxFailures {
+			d := lockout << min(s.lockouts, 10)
+			if d > loginMaxLockout || d <= 0 {
+				d = loginMaxLockout
+			}
+			s.lockedUntil = now.Add(d)
+			s.failures = 0
+			s.lockouts++
+			log.Printf("login throttle: locking %s for %s", k, d)
+			reportSecurityEvent(SecurityEvent{Type: SecurityEventLoginLocked, Time: now, User: user, IP: GetRealIP(r), Details: fmt.Sprintf("%s locked for %s", k, d)})
+		}
+	}
+
+	// Remove old entries so the map does not grow indefinitely
+	for k, s := range loginThrottle {
+		if now.Sub(s.last) > loginMaxLockout && now.After(s.lockedUntil) {
+			delete(loginThrottle, k)
+		}
+	}
+
+	return false, nil
+}
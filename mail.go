@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// ErrMailNotConfigured is returned by sendMail if no SMTP server is configured.
+var ErrMailNotConfigured = errors.New("no SMTP server configured")
+
+// validMailAddress checks whether s is a single plain email address (without display name).
+func validMailAddress(s string) bool {
+	a, err := mail.ParseAddress(s)
+	return err == nil && a.Address == s && a.Name == ""
+}
+
+// sendMail sends a plain text email through the configured SMTP server.
+func sendMail(to, subject, body string) error {
+	var msg bytes.Buffer
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(mailLineEndings(body))
+	return sendMessage(to, subject, msg.Bytes())
+}
+
+// sendCalendarMail sends an email with a calendar invitation (RFC 5545 / iMIP) through the configured SMTP server.
+// The invitation is added both as alternative content and as an attachment so that most clients can show it.
+func sendCalendarMail(to, subject, body string, ics []byte) error {
+	boundary := fmt.Sprintf("pollgo-%d", time.Now().UnixNano())
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=\"%s\"\r\n", boundary)
+	msg.WriteString("\r\n")
+	fmt.Fprintf(&msg, "--%s\r\n", boundary)
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(mailLineEndings(body))
+	msg.WriteString("\r\n")
+	fmt.Fprintf(&msg, "--%s\r\n", boundary)
+	msg.WriteString("Content-Type: text/calendar; charset=utf-8; method=REQUEST\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+	msg.Write(ics)
+	fmt.Fprintf(&msg, "--%s\r\n", boundary)
+	msg.WriteString("Content-Type: application/ics; name=\"invite.ics\"\r\n")
+	msg.WriteString("Content-Disposition: attachment; filename=\"invite.ics\"\r\n")
+	msg.WriteString("Content-Transfer-Encoding: base64\r\n")
+	msg.WriteString("\r\n")
+	encoded := base64.StdEncoding.EncodeToString(ics)
+	for len(encoded) > 76 {
+		msg.WriteString(encoded[:76])
+		msg.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	msg.WriteString(encoded)
+	msg.WriteString("\r\n")
+	fmt.Fprintf(&msg, "--%s--\r\n", boundary)
+	return sendMessage(to, subject, msg.Bytes())
+}
+
+// mailLineEndings converts all line endings of s to CRLF.
+func mailLineEndings(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
+
+// sendMessage adds the common headers to content (which must start with the content headers) and sends it through the configured SMTP server.
+func sendMessage(to, subject string, content []byte) error {
+	if config.SMTPServer == "" {
+		return ErrMailNotConfigured
+	}
+	if !validMailAddress(to) {
+		return fmt.Errorf("invalid email address %s", to)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", config.SMTPFrom)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.Write(content)
+
+	var a smtp.Auth
+	if config.SMTPUser != "" {
+		host, _, err := net.SplitHostPort(config.SMTPServer)
+		if err != nil {
+			return err
+		}
+		a = smtp.PlainAuth("", config.SMTPUser, config.SMTPPassword, host)
+	}
+	return smtp.SendMail(config.SMTPServer, a, config.SMTPFrom, []string{to}, msg.Bytes())
+}
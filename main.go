@@ -22,34 +22,122 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/signal"
 	"runtime/debug"
 	"strings"
-	"syscall"
 
-	_ "github.com/Top-Ranger/pollgo/authenticater"
+	auth "github.com/Top-Ranger/pollgo/authenticater"
 	_ "github.com/Top-Ranger/pollgo/datasafe"
+	_ "github.com/Top-Ranger/pollgo/formatter"
+	_ "github.com/Top-Ranger/pollgo/hook"
 	"github.com/Top-Ranger/pollgo/registry"
+	"github.com/go-playground/colors"
 )
 
 // ConfigStruct contains all configuration options for PollGo!
 type ConfigStruct struct {
-	Language                     string
-	MaxNumberQuestions           int
-	Address                      string
-	PathImpressum                string
-	PathDSGVO                    string
-	AuthenticationEnabled        bool
-	Authenticater                string
-	AuthenticaterConfig          string
-	LogFailedLogin               bool
-	OnlyCreatorCanDelete         bool
-	DataSafe                     string
-	DataSafeConfig               string
-	RunGCOnStart                 bool
-	ServerPath                   string
-	EditCookieDays               int
-	InsecureAllowCookiesOverHTTP bool
+	Language                       string
+	MaxNumberQuestions             int
+	Address                        string
+	PathImpressum                  string
+	PathDSGVO                      string
+	AuthenticationEnabled          bool
+	Authenticater                  string
+	AuthenticaterConfig            string
+	OIDCIssuer                     string
+	OIDCClientID                   string
+	OIDCClientSecret               string
+	OIDCUsernameClaim              string
+	SessionHours                   int
+	ParticipantAuthenticater       string
+	ParticipantAuthenticaterConfig string
+	Hooks                          map[string]string // name of the hook -> path of its configuration (may be empty)
+	Formatter                      string
+	FormatterConfig                string
+	LogFailedLogin                 bool
+	DebugErrors                    bool
+	OnlyCreatorCanDelete           bool
+	OnlyAuthenticatedCanCreate     bool
+	DataSafe                       string
+	DataSafeConfig                 string
+	RunGCOnStart                   bool
+	ServerPath                     string
+	EditCookieDays                 int
+	InsecureAllowCookiesOverHTTP   bool
+	InstanceName                   string
+	LogoURL                        string
+	FaviconURL                     string
+	AccentColour                   string
+	AccessiblePalette              string
+	EnablePresence                 bool
+	EnableAccessStatistics         bool
+	EnableLiveUpdates              bool
+	EnableH2C                      bool
+	TLSCertFile                    string
+	TLSKeyFile                     string
+	TLSAutocertDomains             []string
+	TLSAutocertCacheDir            string
+	TLSAutocertEmail               string
+	TLSRedirectAddress             string
+	HashedAssetNames               bool
+	PathRobotsTxt                  string
+	SitemapBaseURL                 string
+	SitemapPolls                   []string
+	DisableImplicitCreation        bool
+	APITokens                      []APIToken
+	EnableMetrics                  bool
+	EnableDiscussion               bool
+	AnswerPageSize                 int
+	RenderBudget                   int
+	PrecomputeAggregates           bool
+	PageCacheSeconds               int
+	UploadPath                     string
+	MaxUploadSize                  int64
+	DateInputFormat                string
+	DateDisplayFormat              string
+	DateTimeDisplayFormat          string
+	ScorePrecision                 int
+	ReminderWebhook                string
+	ReminderHours                  int
+	SuggestionWeightYes            float64
+	SuggestionWeightIfNeeded       float64
+	SuggestionWeightNo             float64
+	PseudonymiseAfterDays          int
+	ExpiredPolls                   string
+	ArchivePath                    string
+	ConsentText                    string
+	ConsentURL                     string
+	LoginMaxFailures               int
+	LoginLockoutMinutes            int
+	RateLimitCreatePerMinute       int
+	RateLimitCreateBurst           int
+	RateLimitVotePerMinute         int
+	RateLimitVoteBurst             int
+	RateLimitFailedLoginPerMinute  int
+	RateLimitFailedLoginBurst      int
+	SecurityWebhook                string
+	SecurityLargePollAnswers       int
+	SecurityMassDeletionAnswers    int
+	SecurityLoginFailures          int
+	PublicURL                      string
+	SMTPServer                     string
+	SMTPUser                       string
+	SMTPPassword                   string
+	SMTPFrom                       string
+	EnableRegistration             bool
+	EnablePasswordReset            bool
+	TokenSecret                    string
+	MaxPollsPerCreator             int
+	MaxPolls                       int
+	MaxStorageBytes                int64
+	DemoMode                       bool
+	DemoPollLifetimeHours          int
+	SeedPath                       string
+	CustomStaticPath               string
+	PathExtraHead                  string
+	PathExtraFooter                string
+	PathContentFilter              string
+	ContentFilterMask              bool
+	Moderators                     []string
 }
 
 var config ConfigStruct
@@ -63,7 +151,8 @@ func loadConfig(path string) (ConfigStruct, error) {
 		return ConfigStruct{}, errors.New(fmt.Sprintln("Can not read config.json:", err))
 	}
 
-	c := ConfigStruct{}
+	// ScorePrecision may explicitly be set to 0, so a missing value is marked as negative
+	c := ConfigStruct{ScorePrecision: -1}
 	err = json.Unmarshal(b, &c)
 	if err != nil {
 		return ConfigStruct{}, errors.New(fmt.Sprintln("Error while parsing config.json:", err))
@@ -75,9 +164,154 @@ func loadConfig(path string) (ConfigStruct, error) {
 	}
 	c.ServerPath = strings.TrimSuffix(c.ServerPath, "/")
 
+	if c.ConsentURL == "" {
+		c.ConsentURL = strings.Join([]string{c.ServerPath, "/dsgvo.html"}, "")
+	}
+
+	if c.InstanceName == "" {
+		c.InstanceName = "PollGo!"
+	}
+
+	if c.RenderBudget == 0 {
+		c.RenderBudget = 50000
+	}
+
+	if c.Formatter == "" {
+		c.Formatter = "Markdown"
+	}
+
+	if c.AccentColour == "" {
+		c.AccentColour = "#249C51"
+	}
+	if _, err := colors.ParseHEX(c.AccentColour); err != nil {
+		return ConfigStruct{}, fmt.Errorf("AccentColour '%s' is not a valid hex colour: %w", c.AccentColour, err)
+	}
+	if _, ok := accessiblePalettes[c.AccessiblePalette]; c.AccessiblePalette != "" && !ok {
+		return ConfigStruct{}, fmt.Errorf("unknown value '%s' for AccessiblePalette (allowed: '', 'colourblind', 'highcontrast')", c.AccessiblePalette)
+	}
+
+	c.SitemapBaseURL = strings.TrimSuffix(c.SitemapBaseURL, "/")
+	c.PublicURL = strings.TrimSuffix(c.PublicURL, "/")
+
+	if c.DemoMode {
+		// Demo instances must not send anything to the outside
+		c.SMTPServer = ""
+		c.ReminderWebhook = ""
+		c.SecurityWebhook = ""
+		c.EnableRegistration = false
+		c.EnablePasswordReset = false
+		if c.DemoPollLifetimeHours <= 0 {
+			c.DemoPollLifetimeHours = 24
+		}
+		log.Printf("load config: demo mode - polls are deleted after %d hours, emails and webhooks are disabled", c.DemoPollLifetimeHours)
+	}
+
+	if c.EnableRegistration && (!c.AuthenticationEnabled || c.Authenticater != "Htpasswd" || c.SMTPServer == "" || c.PublicURL == "") {
+		return ConfigStruct{}, errors.New("EnableRegistration requires AuthenticationEnabled, the Htpasswd authenticater, SMTPServer and PublicURL")
+	}
+	if c.EnablePasswordReset && (!c.AuthenticationEnabled || c.Authenticater != "Htpasswd" || c.SMTPServer == "" || c.PublicURL == "") {
+		return ConfigStruct{}, errors.New("EnablePasswordReset requires AuthenticationEnabled, the Htpasswd authenticater, SMTPServer and PublicURL")
+	}
+	if c.OIDCIssuer != "" {
+		if !c.AuthenticationEnabled || c.OIDCClientID == "" || c.PublicURL == "" {
+			return ConfigStruct{}, errors.New("OIDCIssuer requires AuthenticationEnabled, OIDCClientID and PublicURL")
+		}
+		if c.EnableRegistration || c.EnablePasswordReset {
+			return ConfigStruct{}, errors.New("OIDCIssuer can not be used together with EnableRegistration or EnablePasswordReset")
+		}
+		if c.OIDCUsernameClaim == "" {
+			c.OIDCUsernameClaim = "preferred_username"
+		}
+	}
+	if c.SessionHours <= 0 {
+		c.SessionHours = 12
+	}
+	if len(c.Moderators) != 0 && !c.AuthenticationEnabled {
+		return ConfigStruct{}, errors.New("Moderators requires AuthenticationEnabled")
+	}
+	if len(c.SitemapPolls) != 0 && c.SitemapBaseURL == "" {
+		return ConfigStruct{}, errors.New("SitemapBaseURL must be set if SitemapPolls is used")
+	}
+
+	if c.UploadPath != "" {
+		if c.MaxUploadSize <= 0 {
+			c.MaxUploadSize = 2000000 // 2 MB
+		}
+		err = os.MkdirAll(c.UploadPath, os.ModePerm)
+		if err != nil {
+			return ConfigStruct{}, fmt.Errorf("can not create UploadPath: %w", err)
+		}
+	}
+
+	if c.DateInputFormat == "" {
+		c.DateInputFormat = "2006-01-02"
+	}
+	if c.DateDisplayFormat == "" {
+		c.DateDisplayFormat = "02.01.2006"
+	}
+	if c.DateTimeDisplayFormat == "" {
+		c.DateTimeDisplayFormat = "02.01.2006 15:04"
+	}
+	if c.ScorePrecision < 0 {
+		c.ScorePrecision = 2
+	}
+	if c.ScorePrecision > 6 {
+		return ConfigStruct{}, errors.New("ScorePrecision must be between 0 and 6")
+	}
+
+	if c.ReminderWebhook != "" && c.ReminderHours <= 0 {
+		c.ReminderHours = 24
+	}
+
+	if c.SuggestionWeightYes == 0 && c.SuggestionWeightIfNeeded == 0 && c.SuggestionWeightNo == 0 {
+		c.SuggestionWeightYes = 1.0
+		c.SuggestionWeightIfNeeded = 0.5
+		c.SuggestionWeightNo = 1.0
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return ConfigStruct{}, errors.New("TLSCertFile and TLSKeyFile must be set together")
+	}
+	if len(c.TLSAutocertDomains) != 0 {
+		if c.TLSCertFile != "" {
+			return ConfigStruct{}, errors.New("TLSAutocertDomains can not be used together with TLSCertFile")
+		}
+		if c.TLSAutocertCacheDir == "" {
+			return ConfigStruct{}, errors.New("TLSAutocertDomains requires TLSAutocertCacheDir")
+		}
+		err = os.MkdirAll(c.TLSAutocertCacheDir, 0700)
+		if err != nil {
+			return ConfigStruct{}, fmt.Errorf("can not create TLSAutocertCacheDir: %w", err)
+		}
+	}
+	if c.TLSRedirectAddress != "" && c.TLSCertFile == "" && len(c.TLSAutocertDomains) == 0 {
+		return ConfigStruct{}, errors.New("TLSRedirectAddress requires TLSCertFile or TLSAutocertDomains")
+	}
+
+	switch c.ExpiredPolls {
+	case expiredPollsKeep, expiredPollsDelete:
+	case expiredPollsArchive:
+		if c.ArchivePath == "" {
+			return ConfigStruct{}, errors.New("ExpiredPolls 'archive' requires ArchivePath")
+		}
+		err = os.MkdirAll(c.ArchivePath, os.ModePerm)
+		if err != nil {
+			return ConfigStruct{}, fmt.Errorf("can not create ArchivePath: %w", err)
+		}
+	default:
+		return ConfigStruct{}, fmt.Errorf("unknown value '%s' for ExpiredPolls (allowed: '', 'archive', 'delete')", c.ExpiredPolls)
+	}
+
+	if c.LoginMaxFailures > 0 && c.LoginLockoutMinutes <= 0 {
+		c.LoginLockoutMinutes = 15
+	}
+
 	if !c.AuthenticationEnabled && c.OnlyCreatorCanDelete {
 		log.Println("load config: Configuration nonsensical - OnlyCreatorCanDelete has no effect when AuthenticationEnabled is false")
 	}
+	if c.OnlyAuthenticatedCanCreate && c.ParticipantAuthenticater != "" {
+		log.Println("load config: Configuration nonsensical - ParticipantAuthenticater has no effect when OnlyAuthenticatedCanCreate is true")
+	}
 
 	return c, nil
 }
@@ -108,11 +342,22 @@ func printInfo() {
 }
 
 func main() {
-	printInfo()
+	if len(os.Args) > 1 && os.Args[1] == "user" {
+		os.Exit(userCommand(os.Args[2:], os.Stdin, os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "service" {
+		os.Exit(serviceCommand(os.Args[2:]))
+	}
 
 	configPath := flag.String("config", "./config.json", "Path to json config for PollGo!")
 	flag.Parse()
 
+	if isService() {
+		*configPath = prepareService(*configPath)
+	}
+
+	printInfo()
+
 	c, err := loadConfig(*configPath)
 	if err != nil {
 		panic(err)
@@ -141,10 +386,35 @@ func main() {
 			log.Panicln(err)
 		}
 
-		safe = datasafe
+		safe = instrumentedDataSafe{safe: datasafe}
 	}
 
-	if config.AuthenticationEnabled {
+	{
+		f, ok := registry.GetFormatter(config.Formatter)
+		if !ok {
+			log.Panicf("main: Unknown formatter %s", config.Formatter)
+		}
+
+		var b []byte
+		if config.FormatterConfig != "" {
+			b, err = os.ReadFile(config.FormatterConfig)
+			if err != nil {
+				log.Panicln(err)
+			}
+		}
+
+		err = f.LoadConfig(b)
+		if err != nil {
+			log.Panicln(err)
+		}
+
+		formatter = f
+	}
+
+	if config.AuthenticationEnabled && config.OIDCIssuer != "" && config.Authenticater == "" {
+		// Creators can only log in through OpenID Connect
+		authenticater = noPasswordAuthenticater{}
+	} else if config.AuthenticationEnabled {
 		a, ok := registry.GetAuthenticater(config.Authenticater)
 		if !ok {
 			log.Panicf("main: Unknown authenticater %s", config.Authenticater)
@@ -160,26 +430,94 @@ func main() {
 			log.Panicln(err)
 		}
 
+		if h, ok := a.(*auth.Htpasswd); ok {
+			// Users changed with 'pollgo user' or by other tools are picked up without restart
+			err = h.WatchFile(config.AuthenticaterConfig)
+			if err != nil {
+				log.Panicln(err)
+			}
+		}
+
 		authenticater = a
 
 	}
 
+	err = initialiseParticipantAuthenticater()
+	if err != nil {
+		log.Panicln("main:", err)
+	}
+
+	err = initialiseHooks()
+	if err != nil {
+		log.Panicln("main:", err)
+	}
+
+	initialiseRateLimiters()
+
 	if config.RunGCOnStart {
 		log.Println("main: starting gc")
+		// Expired polls are deleted first, so they are removed by the gc
+		processExpiredPolls()
 		safe.RunGC()
+		if config.UploadPath != "" {
+			err = gcUploads()
+			if err != nil {
+				log.Printf("main: upload gc failed: %s", err.Error())
+			}
+		}
 		log.Println("main: gc finished")
 	}
 
-	RunServer()
+	if config.SeedPath != "" {
+		log.Println("main: loading seed polls")
+		loadSeedPolls()
+	}
+
+	checkDataSafeHealth()
+	go healthWorker()
+
+	if config.ReminderWebhook != "" {
+		log.Println("main: starting reminder worker")
+		go reminderWorker()
+	}
 
-	s := make(chan os.Signal, 1)
-	signal.Notify(s, os.Interrupt, syscall.SIGTERM)
+	log.Println("main: starting follow-up worker")
+	go followUpWorker()
+
+	if config.DemoMode {
+		log.Println("main: starting demo worker")
+		go demoWorker()
+	}
+
+	if config.PseudonymiseAfterDays > 0 {
+		log.Println("main: starting retention worker")
+		go retentionWorker()
+	}
+
+	if config.PrecomputeAggregates {
+		go aggregateWorker()
+	}
+
+	if config.ExpiredPolls != expiredPollsKeep {
+		log.Println("main: starting expiry worker")
+		go expiryWorker()
+	}
+
+	if config.EnableAccessStatistics {
+		log.Println("main: starting access statistics worker")
+		go accessWorker()
+	}
+
+	RunServer()
+	sdNotify("READY=1")
 
 	log.Println("main: waiting")
+	waitForShutdown()
 
-	for range s {
-		StopServer()
-		safe.FlushAndClose()
-		return
+	sdNotify("STOPPING=1")
+	StopServer()
+	if config.EnableAccessStatistics {
+		flushAccessStatistics()
 	}
+	safe.FlushAndClose()
 }
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type metric struct {
+	help   string
+	values map[string]float64 // label string -> value
+}
+
+var metricsMutex sync.Mutex
+var metrics = make(map[string]*metric)
+
+// metricLabel returns a label string in the Prometheus text format.
+func metricLabel(name, value string) string {
+	return fmt.Sprintf("%s=%s", name, strconv.Quote(value))
+}
+
+// MetricsAdd adds v to the counter identified by name and labels.
+// labels must be created through metricLabel and joined by ','.
+func MetricsAdd(name, help, labels string, v float64) {
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+	m, ok := metrics[name]
+	if !ok {
+		m = &metric{help: help, values: make(map[string]float64)}
+		metrics[name] = m
+	}
+	m.values[labels] += v
+}
+
+func metricsHandle(rw http.ResponseWriter, r *http.Request) {
+	metricsMutex.Lock()
+	names := make([]string, 0, len(metrics))
+	for k := range metrics {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		m := metrics[name]
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, m.help)
+		fmt.Fprintf(&b, "# TYPE %s counter\n", name)
+		labels := make([]string, 0, len(m.values))
+		for l := range m.values {
+			labels = append(labels, l)
+		}
+		sort.Strings(labels)
+		for _, l := range labels {
+			if l == "" {
+				fmt.Fprintf(&b, "%s %s\n", name, strconv.FormatFloat(m.values[l], 'g', -1, 64))
+			} else {
+				fmt.Fprintf(&b, "%s{%s} %s\n", name, l, strconv.FormatFloat(m.values[l], 'g', -1, 64))
+			}
+		}
+	}
+	metricsMutex.Unlock()
+
+	// Gauges are read from the data safe on every request
+	polls, err := safe.ListPolls()
+	if err == nil {
+		answers := 0
+		for _, key := range polls {
+			n, _, err := safe.GetPollStats(key)
+			if err != nil {
+				continue
+			}
+			answers += n
+		}
+		fmt.Fprintf(&b, "# HELP pollgo_polls Number of polls which are not deleted.\n# TYPE pollgo_polls gauge\npollgo_polls %d\n", len(polls))
+		fmt.Fprintf(&b, "# HELP pollgo_answers Number of answers of all polls which are not deleted.\n# TYPE pollgo_answers gauge\npollgo_answers %d\n", answers)
+	}
+
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	rw.Write([]byte(b.String()))
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// In multiple choice polls, the result of a question is a bitmask of the selected answer options (bit i set if option i is selected).
+// All other polls store the index of the selected answer option.
+// This keeps the storage format of the data safes unchanged.
+
+// maxMultipleChoiceOptions is the maximum number of answer options of multiple choice polls, limited by the size of the bitmask.
+const maxMultipleChoiceOptions = 31
+
+// multipleChoiceNone is sent for each question of multiple choice polls, so questions without selected options can be told apart from questions not on the page.
+const multipleChoiceNone = -1
+
+// validAnswer returns whether v is a valid result of a question of the poll.
+func (p Poll) validAnswer(v int) bool {
+	if p.MultipleChoice {
+		return v >= 0 && v < 1<<len(p.AnswerOption)
+	}
+	return v >= 0 && v < len(p.AnswerOption)
+}
+
+// selectedOptions returns the indices of the answer options selected by the result v. Invalid results select no option.
+func (p Poll) selectedOptions(v int) []int {
+	if !p.validAnswer(v) {
+		return nil
+	}
+	if !p.MultipleChoice {
+		return []int{v}
+	}
+	selected := make([]int, 0)
+	for o := range p.AnswerOption {
+		if v&(1<<o) != 0 {
+			selected = append(selected, o)
+		}
+	}
+	return selected
+}
+
+// selectionMatrix returns for each question of the results which answer options are selected.
+func (p Poll) selectionMatrix(results []int) [][]bool {
+	m := make([][]bool, len(results))
+	for q := range results {
+		m[q] = make([]bool, len(p.AnswerOption))
+		for _, o := range p.selectedOptions(results[q]) {
+			m[q][o] = true
+		}
+	}
+	return m
+}
+
+// answerText returns the text of the answer options selected by the result v.
+func (p Poll) answerText(v int) string {
+	selected := p.selectedOptions(v)
+	texts := make([]string, len(selected))
+	for i, o := range selected {
+		texts[i] = p.AnswerOption[o][0]
+	}
+	return strings.Join(texts, ", ")
+}
+
+// answerPoints returns the sum of the values of the answer options selected by the result v.
+// values must contain the value of every answer option.
+func (p Poll) answerPoints(v int, values []float64) float64 {
+	points := 0.0
+	for _, o := range p.selectedOptions(v) {
+		points += values[o]
+	}
+	return points
+}
+
+// parseFormAnswer returns the result of a question from the values of its form field.
+// The bool is false if the values are invalid.
+func (p Poll) parseFormAnswer(values []string) (int, bool) {
+	if !p.MultipleChoice {
+		if len(values) == 0 {
+			return 0, false
+		}
+		ai, err := strconv.Atoi(values[0])
+		if err != nil || !p.validAnswer(ai) {
+			return 0, false
+		}
+		return ai, true
+	}
+
+	v := 0
+	for i := range values {
+		o, err := strconv.Atoi(values[i])
+		if err != nil || o < multipleChoiceNone || o >= len(p.AnswerOption) {
+			return 0, false
+		}
+		if o != multipleChoiceNone {
+			v |= 1 << o
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type myPollsEntry struct {
+	Key     string
+	Answers int
+}
+
+type myPollsTemplateStruct struct {
+	User        string
+	Polls       []myPollsEntry
+	Translation Translation
+}
+
+var myPollsLoginTemplate = template.Must(template.New("mypollslogin").Parse(`
+<h1>{{.Translation.MyPolls}}</h1>
+<form method="POST">
+  <input type="hidden" name="csrf" value="{{.CSRF}}">
+  <table style="border: none;">
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="user">{{.Translation.Username}}: </label></td>
+      <td style="border: none;"><input type="text" id="user" name="user" maxlength="500" required></td>
+    </tr>
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="pw">{{.Translation.Password}}: </label></td>
+      <td style="border: none;"><input type="password" id="pw" name="pw" maxlength="500" required></td>
+    </tr>
+  </table>
+  <p><input type="submit" value="{{.Translation.Login}}"></p>
+</form>
+`))
+
+var myPollsTemplate = template.Must(template.New("mypolls").Parse(`
+<h1>{{.Translation.MyPolls}} ({{.User}})</h1>
+{{if .Polls}}
+<table>
+<thead>
+<tr>
+<th>{{.Translation.Poll}}</th>
+<th>{{.Translation.Answers}}</th>
+<th></th>
+</tr>
+</thead>
+<tbody>
+{{range .Polls}}
+<tr>
+<td><a href="/{{.Key}}">{{.Key}}</a></td>
+<td class="centre">{{.Answers}}</td>
+<td><a href="/{{.Key}}?answer=yes">{{$.Translation.Participate}}</a></td>
+</tr>
+{{end}}
+</tbody>
+</table>
+{{else}}
+<p>{{.Translation.NoPollsFound}}</p>
+{{end}}
+`))
+
+func myPollsHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	tl := GetDefaultTranslation()
+
+	if !config.AuthenticationEnabled {
+		rw.WriteHeader(http.StatusNotImplemented)
+		t := textTemplateStruct{"501 Not Implemented", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	method := r.Method
+	if method == http.MethodGet {
+		// The login form is not needed with a login session
+		if sessionUser(r) != "" {
+			method = http.MethodPost
+		} else if oidcEnabled() {
+			http.Redirect(rw, r, oidcLoginURL(r.URL.Path), http.StatusSeeOther)
+			return
+		}
+	}
+
+	switch method {
+	case http.MethodGet:
+		buf := bytes.Buffer{}
+		err := myPollsLoginTemplate.Execute(&buf, loginTemplateStruct{CSRF: csrfToken(rw, r), Translation: tl})
+		if err != nil {
+			log.Printf("mypolls: %s", err.Error())
+		}
+		t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	case http.MethodPost:
+		err := r.ParseForm()
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		// Requests with a login session are sent as GET
+		if r.Method == http.MethodPost && !checkCSRF(rw, r) {
+			return
+		}
+
+		user, correct, err := authenticateRequest(r)
+		if errors.Is(err, ErrLoginLocked) {
+			rw.Header().Set("Retry-After", strconv.Itoa(config.LoginLockoutMinutes*60))
+			rw.WriteHeader(http.StatusTooManyRequests)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.LoginLocked)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if !correct {
+			if config.LogFailedLogin {
+				log.Printf("Failed authentication from %s", GetRealIP(r))
+			}
+			rw.WriteHeader(http.StatusForbidden)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.AuthentificationFailure)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		keys, err := safe.GetPollsByCreator(user)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		td := myPollsTemplateStruct{
+			User:        user,
+			Polls:       make([]myPollsEntry, 0, len(keys)),
+			Translation: tl,
+		}
+		for i := range keys {
+			results, _, _, _, err := safe.GetPollResult(keys[i])
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+			td.Polls = append(td.Polls, myPollsEntry{Key: keys[i], Answers: len(results)})
+		}
+
+		buf := bytes.Buffer{}
+		err = myPollsTemplate.Execute(&buf, td)
+		if err != nil {
+			log.Printf("mypolls: %s", err.Error())
+		}
+		t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	default:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		t := textTemplateStruct{"405 Method Not Allowed", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	}
+}
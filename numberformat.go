@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// numberFormatMaxLanguages is the maximum number of languages of an Accept-Language header which are considered.
+const numberFormatMaxLanguages = 20
+
+// numberFormat holds the separators used to display numbers in a locale.
+type numberFormat struct {
+	Decimal string
+	Group   string
+}
+
+// numberFormats contains the known locales, either as language or as language-region (lower case).
+var numberFormats = map[string]numberFormat{
+	"en":    {".", ","},
+	"de":    {",", "."},
+	"de-ch": {".", "\u2019"},
+	"de-li": {".", "\u2019"},
+	"fr":    {",", "\u202f"},
+	"fr-ch": {",", "\u202f"},
+	"es":    {",", "."},
+	"es-mx": {".", ","},
+	"es-us": {".", ","},
+	"it":    {",", "."},
+	"it-ch": {".", "\u2019"},
+	"nl":    {",", "."},
+	"pt":    {",", "\u00a0"},
+	"pt-br": {",", "."},
+	"pl":    {",", "\u00a0"},
+	"cs":    {",", "\u00a0"},
+	"sk":    {",", "\u00a0"},
+	"ru":    {",", "\u00a0"},
+	"uk":    {",", "\u00a0"},
+	"sv":    {",", "\u00a0"},
+	"nb":    {",", "\u00a0"},
+	"no":    {",", "\u00a0"},
+	"da":    {",", "."},
+	"fi":    {",", "\u00a0"},
+	"tr":    {",", "."},
+	"ja":    {".", ","},
+	"zh":    {".", ","},
+	"ko":    {".", ","},
+}
+
+// lookupNumberFormat returns the number format of the language tag. Regions without their own format use the format of the language.
+func lookupNumberFormat(tag string) (numberFormat, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if f, ok := numberFormats[tag]; ok {
+		return f, true
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	f, ok := numberFormats[language]
+	return f, ok
+}
+
+// requestNumberFormat returns the number format preferred by the visitor through the Accept-Language header.
+// If no known locale is requested, the format of the configured language is used.
+func requestNumberFormat(r *http.Request) numberFormat {
+	type language struct {
+		tag string
+		q   float64
+	}
+	languages := make([]language, 0)
+	for i, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		if i >= numberFormatMaxLanguages {
+			break
+		}
+		tag, params, _ := strings.Cut(part, ";")
+		l := language{tag: strings.TrimSpace(tag), q: 1}
+		if l.tag == "" || l.tag == "*" {
+			continue
+		}
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(v, 64)
+			if err != nil || q <= 0 {
+				continue
+			}
+			l.q = q
+		}
+		languages = append(languages, l)
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].q > languages[j].q })
+
+	for _, l := range languages {
+		if f, ok := lookupNumberFormat(l.tag); ok {
+			return f
+		}
+	}
+	if f, ok := lookupNumberFormat(GetDefaultTranslation().Language); ok {
+		return f
+	}
+	return numberFormats["en"]
+}
+
+// Format returns v with the given number of decimals, using the separators of the locale.
+func (f numberFormat) Format(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		s = s[1:]
+		// Rounded values like -0.001 are shown as 0
+		if strings.Trim(s, "0.") != "" {
+			sign = "-"
+		}
+	}
+	integer, fraction, _ := strings.Cut(s, ".")
+	if len(integer) > 3 && (integer[0] >= '0' && integer[0] <= '9') {
+		var b strings.Builder
+		for i := range integer {
+			if i != 0 && (len(integer)-i)%3 == 0 {
+				b.WriteString(f.Group)
+			}
+			b.WriteByte(integer[i])
+		}
+		integer = b.String()
+	}
+	if fraction == "" {
+		return sign + integer
+	}
+	return sign + integer + f.Decimal + fraction
+}
+
+// Score returns a score (e.g. points, mean or median) with the precision set in 'ScorePrecision'.
+func (f numberFormat) Score(v float64) string {
+	return f.Format(v, config.ScorePrecision)
+}
+
+// key returns a short identifier of the format, e.g. for cache keys.
+func (f numberFormat) key() string {
+	return f.Decimal + f.Group
+}
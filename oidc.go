@@ -0,0 +1,415 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/pollgo/helper"
+)
+
+// With 'OIDCIssuer' set, creators log in through an OpenID Connect provider (e.g. Keycloak or Authentik) instead of sending user / password with every form.
+// PollGo! uses the authorization code flow with PKCE. The ID token is received directly from the token endpoint of the provider over TLS,
+// so its issuer is validated through TLS instead of its signature (OpenID Connect Core 1.0, section 3.1.3.7).
+// After the login, the user is stored in a login session (see session.go).
+// Started logins are only stored in a signed cookie, so starting logins does not use memory on the server.
+
+// oidcStateCookieName is the name of the cookie holding a started login. It binds the login to the browser, so logins can not be completed in other browsers.
+const oidcStateCookieName = "pollgo_oidc_state"
+
+// oidcLoginValidity is the time a started login can be completed.
+const oidcLoginValidity = 10 * time.Minute
+
+// oidcDiscoveryValidity is the time the configuration of the provider is cached.
+const oidcDiscoveryValidity = time.Hour
+
+// oidcMaxResponseSize is the maximum size of responses of the provider.
+const oidcMaxResponseSize = 1 << 20
+
+var oidcClient = &http.Client{Timeout: 10 * time.Second}
+
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+type oidcLogin struct {
+	state    string
+	nonce    string
+	verifier string
+	returnTo string
+	created  time.Time
+}
+
+var (
+	oidcMutex        sync.Mutex
+	oidcProviderData oidcProvider
+	oidcProviderTime time.Time
+)
+
+// oidcEnabled returns whether creators log in through OpenID Connect.
+func oidcEnabled() bool {
+	return config.OIDCIssuer != ""
+}
+
+// oidcRedirectURI returns the URI the provider redirects to after the login.
+func oidcRedirectURI() string {
+	return fmt.Sprintf("%s%s/login/oidc/callback", config.PublicURL, config.ServerPath)
+}
+
+// getOIDCProvider returns the configuration of the provider, which is read through OpenID Connect Discovery.
+func getOIDCProvider() (oidcProvider, error) {
+	oidcMutex.Lock()
+	if !oidcProviderTime.IsZero() && time.Since(oidcProviderTime) < oidcDiscoveryValidity {
+		p := oidcProviderData
+		oidcMutex.Unlock()
+		return p, nil
+	}
+	oidcMutex.Unlock()
+
+	resp, err := oidcClient.Get(strings.Join([]string{strings.TrimSuffix(config.OIDCIssuer, "/"), "/.well-known/openid-configuration"}, ""))
+	if err != nil {
+		return oidcProvider{}, fmt.Errorf("oidc: can not read provider configuration: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return oidcProvider{}, fmt.Errorf("oidc: can not read provider configuration: status %d", resp.StatusCode)
+	}
+	var p oidcProvider
+	err = json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseSize)).Decode(&p)
+	if err != nil {
+		return oidcProvider{}, fmt.Errorf("oidc: can not parse provider configuration: %w", err)
+	}
+	if strings.TrimSuffix(p.Issuer, "/") != strings.TrimSuffix(config.OIDCIssuer, "/") {
+		return oidcProvider{}, fmt.Errorf("oidc: provider reports issuer '%s' instead of '%s'", p.Issuer, config.OIDCIssuer)
+	}
+	if p.AuthorizationEndpoint == "" || p.TokenEndpoint == "" {
+		return oidcProvider{}, errors.New("oidc: provider configuration misses endpoints")
+	}
+
+	oidcMutex.Lock()
+	defer oidcMutex.Unlock()
+	oidcProviderData = p
+	oidcProviderTime = time.Now()
+	return p, nil
+}
+
+// oidcStateSignature signs the fields of a started login stored in the state cookie.
+// The key is derived from the token secret, so started logins can not be confused with other signed tokens.
+func oidcStateSignature(fields []string) []byte {
+	key := hmac.New(sha256.New, getTokenSecret())
+	key.Write([]byte("oidc"))
+	mac := hmac.New(sha256.New, key.Sum(nil))
+	mac.Write([]byte(strings.Join(fields, "\x00")))
+	return mac.Sum(nil)
+}
+
+// setOIDCStateCookie stores the started login in the state cookie.
+func setOIDCStateCookie(rw http.ResponseWriter, login oidcLogin) {
+	fields := []string{login.state, login.nonce, login.verifier, login.returnTo, strconv.FormatInt(login.created.Unix(), 10)}
+	value := make([]string, 0, len(fields)+1)
+	for _, f := range fields {
+		value = append(value, base64.RawURLEncoding.EncodeToString([]byte(f)))
+	}
+	value = append(value, base64.RawURLEncoding.EncodeToString(oidcStateSignature(fields)))
+
+	cookie := http.Cookie{}
+	cookie.Name = oidcStateCookieName
+	cookie.Value = strings.Join(value, ".")
+	cookie.MaxAge = int(oidcLoginValidity / time.Second)
+	cookie.Path = strings.Join([]string{config.ServerPath, "/login/oidc"}, "")
+	cookie.SameSite = http.SameSiteLaxMode
+	cookie.HttpOnly = true
+	cookie.Secure = !config.InsecureAllowCookiesOverHTTP
+	http.SetCookie(rw, &cookie)
+}
+
+// deleteOIDCStateCookie removes the state cookie.
+func deleteOIDCStateCookie(rw http.ResponseWriter) {
+	cookie := http.Cookie{}
+	cookie.Name = oidcStateCookieName
+	cookie.MaxAge = -1
+	cookie.Path = strings.Join([]string{config.ServerPath, "/login/oidc"}, "")
+	cookie.SameSite = http.SameSiteLaxMode
+	cookie.HttpOnly = true
+	cookie.Secure = !config.InsecureAllowCookiesOverHTTP
+	http.SetCookie(rw, &cookie)
+}
+
+// oidcStateLogin returns the started login stored in the state cookie of the request. The bool is false if there is no valid state cookie.
+func oidcStateLogin(r *http.Request) (oidcLogin, bool) {
+	c, err := r.Cookie(oidcStateCookieName)
+	if err != nil {
+		return oidcLogin{}, false
+	}
+	value := strings.Split(c.Value, ".")
+	if len(value) != 6 {
+		return oidcLogin{}, false
+	}
+	fields := make([]string, 5)
+	for i := range fields {
+		b, err := base64.RawURLEncoding.DecodeString(value[i])
+		if err != nil {
+			return oidcLogin{}, false
+		}
+		fields[i] = string(b)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(value[5])
+	if err != nil || !hmac.Equal(sig, oidcStateSignature(fields)) {
+		return oidcLogin{}, false
+	}
+	created, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil {
+		return oidcLogin{}, false
+	}
+	return oidcLogin{state: fields[0], nonce: fields[1], verifier: fields[2], returnTo: fields[3], created: time.Unix(created, 0)}, true
+}
+
+// oidcLoginURL returns the URL starting a login which returns to the local path afterwards.
+func oidcLoginURL(returnTo string) string {
+	return fmt.Sprintf("%s/login/oidc?return=%s", config.ServerPath, url.QueryEscape(returnTo))
+}
+
+// oidcLoginHandle redirects the visitor to the provider.
+func oidcLoginHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	tl := GetDefaultTranslation()
+
+	p, err := getOIDCProvider()
+	if err != nil {
+		rw.WriteHeader(http.StatusBadGateway)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	login := oidcLogin{
+		state:    helper.GetRandomString(),
+		nonce:    helper.GetRandomString(),
+		verifier: strings.Join([]string{helper.GetRandomString(), helper.GetRandomString()}, ""),
+		returnTo: loginReturnPath(r.URL.Query().Get("return")),
+		created:  time.Now(),
+	}
+	setOIDCStateCookie(rw, login)
+
+	challenge := sha256.Sum256([]byte(login.verifier))
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", config.OIDCClientID)
+	v.Set("redirect_uri", oidcRedirectURI())
+	v.Set("scope", "openid profile email")
+	v.Set("state", login.state)
+	v.Set("nonce", login.nonce)
+	v.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	v.Set("code_challenge_method", "S256")
+
+	target := p.AuthorizationEndpoint
+	if strings.Contains(target, "?") {
+		target = strings.Join([]string{target, "&", v.Encode()}, "")
+	} else {
+		target = strings.Join([]string{target, "?", v.Encode()}, "")
+	}
+	http.Redirect(rw, r, target, http.StatusSeeOther)
+}
+
+// oidcCallbackHandle completes the login after the provider redirected the visitor back.
+func oidcCallbackHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	tl := GetDefaultTranslation()
+
+	failed := func(err error) {
+		if config.LogFailedLogin {
+			log.Printf("Failed authentication from %s: %s", GetRealIP(r), err.Error())
+		}
+		rw.WriteHeader(http.StatusForbidden)
+		text := fmt.Sprintf(`<p>%s</p><p><a href="%s">%s</a></p>`, template.HTMLEscapeString(tl.SSOLoginFailed), template.HTMLEscapeString(oidcLoginURL(strings.Join([]string{config.ServerPath, "/"}, ""))), template.HTMLEscapeString(tl.SSOLogin))
+		t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	}
+
+	q := r.URL.Query()
+	login, ok := oidcStateLogin(r)
+	// A login can only be completed once
+	deleteOIDCStateCookie(rw)
+	if !ok || time.Since(login.created) > oidcLoginValidity {
+		failed(errors.New("oidc: unknown or expired login"))
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(login.state)) != 1 {
+		failed(errors.New("oidc: login was started in another browser"))
+		return
+	}
+	if e := q.Get("error"); e != "" {
+		failed(fmt.Errorf("oidc: provider returned error '%s'", e))
+		return
+	}
+	code := q.Get("code")
+	if code == "" {
+		failed(errors.New("oidc: no code returned"))
+		return
+	}
+
+	p, err := getOIDCProvider()
+	if err != nil {
+		rw.WriteHeader(http.StatusBadGateway)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	idToken, err := oidcExchangeCode(p, code, login.verifier)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadGateway)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	user, err := oidcUserFromIDToken(p, idToken, login.nonce)
+	if err != nil {
+		failed(err)
+		return
+	}
+
+	setSessionCookie(rw, user)
+	http.Redirect(rw, r, login.returnTo, http.StatusSeeOther)
+}
+
+// oidcExchangeCode redeems the code at the token endpoint and returns the ID token.
+func oidcExchangeCode(p oidcProvider, code, verifier string) (string, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", oidcRedirectURI())
+	v.Set("code_verifier", verifier)
+	if config.OIDCClientSecret == "" {
+		// Public client
+		v.Set("client_id", config.OIDCClientID)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.TokenEndpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if config.OIDCClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(config.OIDCClientID), url.QueryEscape(config.OIDCClientSecret))
+	}
+
+	resp, err := oidcClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oidc: can not redeem code: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oidc: can not redeem code: status %d", resp.StatusCode)
+	}
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseSize)).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("oidc: can not parse token response: %w", err)
+	}
+	if token.IDToken == "" {
+		return "", errors.New("oidc: token response contains no ID token")
+	}
+	return token.IDToken, nil
+}
+
+// oidcUserFromIDToken validates the claims of the ID token and returns the user given in 'OIDCUsernameClaim'.
+func oidcUserFromIDToken(p oidcProvider, idToken, nonce string) (string, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return "", errors.New("oidc: malformed ID token")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", fmt.Errorf("oidc: malformed ID token: %w", err)
+	}
+	var claims map[string]interface{}
+	err = json.Unmarshal(b, &claims)
+	if err != nil {
+		return "", fmt.Errorf("oidc: malformed ID token: %w", err)
+	}
+
+	if iss, _ := claims["iss"].(string); iss != p.Issuer {
+		return "", fmt.Errorf("oidc: ID token issued by '%s'", iss)
+	}
+	audiences := make([]string, 0)
+	switch aud := claims["aud"].(type) {
+	case string:
+		audiences = append(audiences, aud)
+	case []interface{}:
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				audiences = append(audiences, s)
+			}
+		}
+	}
+	found := false
+	for _, a := range audiences {
+		found = found || a == config.OIDCClientID
+	}
+	if !found {
+		return "", errors.New("oidc: ID token not issued for this client")
+	}
+	if azp, ok := claims["azp"].(string); ok && azp != config.OIDCClientID {
+		return "", errors.New("oidc: ID token authorised for another client")
+	}
+	exp, _ := claims["exp"].(float64)
+	if time.Now().Unix() >= int64(exp) {
+		return "", errors.New("oidc: ID token expired")
+	}
+	if n, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(n), []byte(nonce)) != 1 {
+		return "", errors.New("oidc: ID token with wrong nonce")
+	}
+
+	user, _ := claims[config.OIDCUsernameClaim].(string)
+	if user == "" {
+		return "", fmt.Errorf("oidc: ID token does not contain claim '%s'", config.OIDCUsernameClaim)
+	}
+	return user, nil
+}
+
+// noPasswordAuthenticater rejects all user / password combinations.
+// It is used if creators can only log in through OpenID Connect, e.g. for HTTP basic authentication of the API.
+type noPasswordAuthenticater struct{}
+
+func (noPasswordAuthenticater) LoadConfig(b []byte) error {
+	return nil
+}
+
+func (noPasswordAuthenticater) Authenticate(user, password string) (bool, error) {
+	return false, nil
+}
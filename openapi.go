@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// openAPIObject is a generic JSON object of the OpenAPI document.
+type openAPIObject map[string]interface{}
+
+var openAPIDocument []byte
+
+// openAPISchema generates the schema of a type through reflection.
+// This way, the documentation always matches the data returned by the handlers.
+func openAPISchema(t reflect.Type) openAPIObject {
+	if t == reflect.TypeOf(time.Time{}) {
+		return openAPIObject{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := openAPISchema(t.Elem())
+		s["nullable"] = true
+		return s
+	case reflect.Struct:
+		properties := openAPIObject{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("json") == "-" {
+				continue
+			}
+			properties[f.Name] = openAPISchema(f.Type)
+		}
+		return openAPIObject{"type": "object", "properties": properties}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return openAPIObject{"type": "string", "format": "byte"}
+		}
+		return openAPIObject{"type": "array", "items": openAPISchema(t.Elem())}
+	case reflect.Map:
+		return openAPIObject{"type": "object", "additionalProperties": openAPISchema(t.Elem())}
+	case reflect.String:
+		return openAPIObject{"type": "string"}
+	case reflect.Bool:
+		return openAPIObject{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return openAPIObject{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return openAPIObject{"type": "number", "format": "double"}
+	default:
+		return openAPIObject{}
+	}
+}
+
+func openAPIRef(name string) openAPIObject {
+	return openAPIObject{"$ref": "#/components/schemas/" + name}
+}
+
+func openAPIJSONResponse(description, schema string) openAPIObject {
+	return openAPIObject{
+		"description": description,
+		"content": openAPIObject{
+			"application/json": openAPIObject{"schema": openAPIRef(schema)},
+		},
+	}
+}
+
+func openAPIDescription(description string) openAPIObject {
+	return openAPIObject{"description": description}
+}
+
+// buildOpenAPIDocument creates the OpenAPI document of all machine readable endpoints.
+// New endpoints must be added here.
+func buildOpenAPIDocument() ([]byte, error) {
+	keyParameter := openAPIObject{
+		"name":        "key",
+		"in":          "path",
+		"required":    true,
+		"description": "Key of the poll. Must not contain '/'.",
+		"schema":      openAPIObject{"type": "string"},
+	}
+
+	answerIDParameter := openAPIObject{
+		"name":     "id",
+		"in":       "path",
+		"required": true,
+		"schema":   openAPIObject{"type": "string"},
+	}
+
+	// All errors of the poll API share the same body
+	apiError := func(description string) openAPIObject {
+		return openAPIJSONResponse(description, "APIError")
+	}
+	apiBody := func(schema string) openAPIObject {
+		return openAPIObject{
+			"required": true,
+			"content": openAPIObject{
+				"application/json": openAPIObject{"schema": openAPIRef(schema)},
+			},
+		}
+	}
+
+	server := config.ServerPath
+	if server == "" {
+		server = "/"
+	}
+
+	doc := openAPIObject{
+		"openapi": "3.0.3",
+		"info": openAPIObject{
+			"title":   config.InstanceName,
+			"version": "1",
+		},
+		"servers": []openAPIObject{{"url": server}},
+		"components": openAPIObject{
+			"schemas": openAPIObject{
+				"Poll":           openAPISchema(reflect.TypeOf(Poll{})),
+				"PollStatistics": openAPISchema(reflect.TypeOf(PollStatistics{})),
+				"APIPoll":        openAPISchema(reflect.TypeOf(APIPoll{})),
+				"APINewPoll":     openAPISchema(reflect.TypeOf(APINewPoll{})),
+				"APIAnswer":      openAPISchema(reflect.TypeOf(APIAnswer{})),
+				"APIAnswers":     openAPISchema(reflect.TypeOf([]APIAnswer{})),
+				"APIVote":        openAPISchema(reflect.TypeOf(APIVote{})),
+				"APIVoteResult":  openAPISchema(reflect.TypeOf(APIVoteResult{})),
+				"APIError":       openAPISchema(reflect.TypeOf(APIError{})),
+			},
+			"securitySchemes": openAPIObject{
+				"token": openAPIObject{"type": "http", "scheme": "bearer"},
+				"basic": openAPIObject{"type": "http", "scheme": "basic"},
+			},
+		},
+		"security": []openAPIObject{{}, {"token": []string{}}},
+		"paths": openAPIObject{
+			"/preview": openAPIObject{
+				"post": openAPIObject{
+					"summary": "Renders Markdown the same way as poll descriptions",
+					"requestBody": openAPIObject{
+						"required": true,
+						"content": openAPIObject{
+							"application/x-www-form-urlencoded": openAPIObject{
+								"schema": openAPIObject{
+									"type":       "object",
+									"properties": openAPIObject{"text": openAPIObject{"type": "string", "maxLength": previewMaxLength}},
+									"required":   []string{"text"},
+								},
+							},
+						},
+					},
+					"responses": openAPIObject{
+						"200": openAPIObject{
+							"description": "Sanitised HTML",
+							"content":     openAPIObject{"text/html": openAPIObject{"schema": openAPIObject{"type": "string"}}},
+						},
+						"413": openAPIDescription("Text is too long"),
+					},
+				},
+			},
+			"/{key}": openAPIObject{
+				"parameters": []openAPIObject{keyParameter},
+				"get": openAPIObject{
+					"summary": "Returns aggregated statistics of a poll",
+					"parameters": []openAPIObject{{
+						"name":     "stats",
+						"in":       "query",
+						"required": true,
+						"schema":   openAPIObject{"type": "string", "enum": []string{"true"}},
+					}},
+					"responses": openAPIObject{
+						"200": openAPIJSONResponse("Statistics of the poll", "PollStatistics"),
+						"401": openAPIDescription("Unknown API token"),
+						"404": openAPIDescription("Poll does not exist"),
+						"410": openAPIDescription("Poll is deleted"),
+						"429": openAPIDescription("Quota of the API token exceeded"),
+					},
+				},
+				"post": openAPIObject{
+					"summary": "Exports the configuration of a poll",
+					"requestBody": openAPIObject{
+						"required": true,
+						"content": openAPIObject{
+							"application/x-www-form-urlencoded": openAPIObject{
+								"schema": openAPIObject{
+									"type":       "object",
+									"properties": openAPIObject{"exportConfig": openAPIObject{"type": "string", "enum": []string{"true"}}},
+									"required":   []string{"exportConfig"},
+								},
+							},
+						},
+					},
+					"responses": openAPIObject{
+						"200": openAPIJSONResponse("Configuration of the poll", "Poll"),
+						"401": openAPIDescription("Unknown API token"),
+						"429": openAPIDescription("Quota of the API token exceeded"),
+					},
+				},
+				"put": openAPIObject{
+					"summary": "Checks a user / password combination",
+					"requestBody": openAPIObject{
+						"required": true,
+						"content": openAPIObject{
+							"multipart/form-data": openAPIObject{
+								"schema": openAPIObject{
+									"type": "object",
+									"properties": openAPIObject{
+										"user": openAPIObject{"type": "string"},
+										"pw":   openAPIObject{"type": "string"},
+									},
+									"required": []string{"user", "pw"},
+								},
+							},
+						},
+					},
+					"responses": openAPIObject{
+						"202": openAPIDescription("Valid combination"),
+						"403": openAPIDescription("Invalid combination"),
+						"501": openAPIDescription("Authentication is not enabled"),
+					},
+				},
+			},
+			"/api/v1/polls/{key}": openAPIObject{
+				"parameters": []openAPIObject{keyParameter},
+				"get": openAPIObject{
+					"summary": "Returns a poll",
+					"responses": openAPIObject{
+						"200": openAPIJSONResponse("The poll", "APIPoll"),
+						"403": apiError("Poll is hidden by a moderator"),
+						"404": apiError("Poll does not exist"),
+						"406": openAPIDescription("Client does not accept JSON"),
+						"410": apiError("Poll is deleted"),
+					},
+				},
+				"put": openAPIObject{
+					"summary":     "Creates a poll",
+					"security":    []openAPIObject{{}, {"basic": []string{}}},
+					"requestBody": apiBody("APINewPoll"),
+					"responses": openAPIObject{
+						"201": openAPIJSONResponse("The created poll", "APIPoll"),
+						"400": apiError("Invalid poll configuration"),
+						"401": apiError("Authentication required"),
+						"403": apiError("Poll quota of the user exceeded"),
+						"409": apiError("Poll already exists"),
+						"415": apiError("Body is not JSON"),
+						"507": apiError("Instance is full"),
+					},
+				},
+				"delete": openAPIObject{
+					"summary":  "Deletes a poll",
+					"security": []openAPIObject{{}, {"basic": []string{}}},
+					"responses": openAPIObject{
+						"204": openAPIDescription("Poll deleted"),
+						"401": apiError("Authentication required"),
+						"403": apiError("User is not the creator of the poll"),
+						"404": apiError("Poll does not exist"),
+						"410": apiError("Poll is deleted"),
+					},
+				},
+			},
+			"/api/v1/polls/{key}/answers": openAPIObject{
+				"parameters": []openAPIObject{keyParameter},
+				"get": openAPIObject{
+					"summary": "Lists all answers of a poll",
+					"responses": openAPIObject{
+						"200": openAPIJSONResponse("Answers of the poll", "APIAnswers"),
+						"403": apiError("Answers of the poll are hidden"),
+						"404": apiError("Poll does not exist"),
+						"410": apiError("Poll is deleted"),
+					},
+				},
+				"post": openAPIObject{
+					"summary":     "Submits an answer",
+					"security":    []openAPIObject{{}, {"basic": []string{}}},
+					"requestBody": apiBody("APIVote"),
+					"responses": openAPIObject{
+						"201": openAPIJSONResponse("Answer saved", "APIVoteResult"),
+						"400": apiError("Invalid answer"),
+						"403": apiError("Poll is closed"),
+						"404": apiError("Poll does not exist"),
+						"409": apiError("Name already taken, question full or poll changed since 'Revision'"),
+						"410": apiError("Poll is deleted"),
+						"415": apiError("Body is not JSON"),
+						"507": apiError("Instance is full"),
+					},
+				},
+			},
+			"/api/v1/polls/{key}/answers/{id}": openAPIObject{
+				"parameters": []openAPIObject{keyParameter, answerIDParameter},
+				"get": openAPIObject{
+					"summary": "Returns a single answer",
+					"responses": openAPIObject{
+						"200": openAPIJSONResponse("The answer", "APIAnswer"),
+						"404": apiError("Poll or answer does not exist"),
+						"410": apiError("Poll is deleted"),
+					},
+				},
+				"put": openAPIObject{
+					"summary":     "Changes an answer",
+					"security":    []openAPIObject{{}, {"basic": []string{}}},
+					"requestBody": apiBody("APIVote"),
+					"responses": openAPIObject{
+						"200": openAPIJSONResponse("Answer saved", "APIVoteResult"),
+						"400": apiError("Invalid answer"),
+						"403": apiError("Poll is closed or 'EditToken' is invalid"),
+						"404": apiError("Poll or answer does not exist"),
+						"409": apiError("Name already taken, question full or poll changed since 'Revision'"),
+						"410": apiError("Poll is deleted"),
+						"415": apiError("Body is not JSON"),
+					},
+				},
+			},
+		},
+	}
+
+	return json.Marshal(doc)
+}
+
+func openAPIHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(openAPIDocument)
+}
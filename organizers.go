@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// The creator of a poll can name co-organizers ('organizers=true'), who get the same rights to manage the poll (close, edit, delete, exports, ...).
+// Only the creator can change the co-organizers. Co-organizers are stored apart from the configuration of the poll, as the configuration is public.
+// Co-organizers are only used if managing polls requires authentication.
+
+// maxOrganizers is the maximum number of co-organizers of a poll.
+const maxOrganizers = 20
+
+// organizerMaxNameLength is the maximum length of the user name of a co-organizer.
+const organizerMaxNameLength = 500
+
+type organizersTemplateStruct struct {
+	Key         string
+	Organizers  string // one user name per line
+	HasPassword bool
+	User        string
+	CSRF        string
+	Translation Translation
+}
+
+var organizersTemplate = template.Must(template.New("organizers").Parse(`
+<h1>{{.Translation.CoOrganizers}}: <a href="/{{.Key}}">{{.Key}}</a></h1>
+<p>{{.Translation.CoOrganizersDescription}}</p>
+<form method="POST">
+<input type="hidden" name="csrf" value="{{.CSRF}}">
+<input type="hidden" name="organizers" value="true">
+<input type="hidden" name="organizeraction" value="save">
+<p><label for="organizer_names">{{.Translation.CoOrganizerNames}}</label></p>
+<p><textarea id="organizer_names" name="names" rows="5" style="width: 100%;">{{.Organizers}}</textarea></p>
+{{if .HasPassword}}
+<table style="border: none;">
+  <tr style="border: none; background-color: inherit;">
+    <td style="border: none;"><label>{{.Translation.Username}}: <input type="text" name="user" maxlength="500" value="{{.User}}" required></label></td>
+  </tr>
+  <tr style="border: none; background-color: inherit;">
+    <td style="border: none;"><label>{{.Translation.Password}}: <input type="password" name="pw" maxlength="500" required></label></td>
+  </tr>
+</table>
+{{end}}
+<p><input type="submit" value="{{.Translation.CoOrganizersSave}}"></p>
+</form>
+<p><a href="/{{.Key}}">{{.Translation.BackToPoll}}</a></p>
+`))
+
+// isPollManager returns whether the user may manage the poll stored under key, i.e. is its creator or a co-organizer.
+// Polls without creator (e.g. old polls or polls created without authentication) can be managed by every user.
+func isPollManager(key, user string) (bool, error) {
+	creator, err := safe.GetPollCreator(key)
+	if err != nil {
+		return false, err
+	}
+	if creator == "" || user == creator {
+		return true, nil
+	}
+	organizers, err := safe.GetPollOrganizers(key)
+	if err != nil {
+		return false, err
+	}
+	return user != "" && slices.Contains(organizers, user), nil
+}
+
+// parseOrganizers returns the co-organizers given in v (one user name per line). Duplicates and the creator are removed.
+// The bool is false if there are too many co-organizers or a name is too long.
+func parseOrganizers(v, creator string) ([]string, bool) {
+	organizers := make([]string, 0)
+	for _, n := range strings.Split(v, "\n") {
+		n = strings.TrimSpace(n)
+		if n == "" || n == creator || slices.Contains(organizers, n) {
+			continue
+		}
+		if len(n) > organizerMaxNameLength || len(organizers) == maxOrganizers {
+			return nil, false
+		}
+		organizers = append(organizers, n)
+	}
+	return organizers, true
+}
+
+// handleOrganizers shows the co-organizers of a poll and changes them ('organizeraction=save').
+// Access is only allowed for the creator of the poll, co-organizers can not change the co-organizers.
+func handleOrganizers(rw http.ResponseWriter, r *http.Request, key string) {
+	tl := GetDefaultTranslation()
+
+	if !checkCreator(rw, r, key, true) {
+		return
+	}
+	creator, err := safe.GetPollCreator(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	if !config.AuthenticationEnabled || creator == "" || requestUser(r) != creator {
+		rw.WriteHeader(http.StatusForbidden)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(fmt.Sprintf("403 Forbidden (%s)", tl.UserNotCreator))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	if r.Form.Get("organizeraction") == "save" {
+		organizers, ok := parseOrganizers(r.Form.Get("names"), creator)
+		if !ok {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(fmt.Sprintf(tl.CoOrganizersInvalid, maxOrganizers))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		err = safe.SavePollOrganizers(key, organizers)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+	}
+
+	organizers, err := safe.GetPollOrganizers(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	td := organizersTemplateStruct{
+		Key:         key,
+		Organizers:  strings.Join(organizers, "\n"),
+		HasPassword: managementRequiresPassword(r),
+		User:        r.Form.Get("user"),
+		CSRF:        csrfToken(rw, r),
+		Translation: tl,
+	}
+	buf := bytes.Buffer{}
+	err = organizersTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("organizers: %s", err.Error())
+	}
+	t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Top-Ranger/pollgo/helper"
+)
+
+// pageCacheMaxEntries is the maximum number of cached pages. If it is reached, the cache is cleared.
+const pageCacheMaxEntries = 1000
+
+// pageCacheCSRFPlaceholder is rendered instead of the CSRF token into cached pages and replaced on delivery.
+// The token and the placeholder only contain characters which are not escaped in HTML.
+var pageCacheCSRFPlaceholder = []byte(strings.Join([]string{"CSRFPLACEHOLDER", helper.GetRandomString()}, ""))
+
+type pageCacheEntry struct {
+	page         []byte
+	created      time.Time
+	lastActivity time.Time
+	closed       bool
+}
+
+var (
+	pageCache      = make(map[string]pageCacheEntry)
+	pageCacheMutex sync.Mutex
+)
+
+// pageCacheKey returns the key of the rendered poll page in the page cache.
+// The bool is false if the request must not be served from the cache, e.g. because the page contains personal elements.
+// Pages are cached separately for each number format the visitor requested.
+func pageCacheKey(r *http.Request, key string, numbers numberFormat) (string, bool) {
+	if config.PageCacheSeconds <= 0 || r.Method != http.MethodGet {
+		return "", false
+	}
+	for k := range r.Form {
+		if k != "view" && k != "resultpage" && k != "sort" && k != "order" {
+			return "", false
+		}
+	}
+	// Cookies mark answers which can be edited or were endorsed by the visitor
+	for _, c := range r.Cookies() {
+		if c.Name != csrfCookieName && c.Name != shuffleCookieName {
+			return "", false
+		}
+	}
+	return strings.Join([]string{key, r.Form.Get("view"), r.Form.Get("resultpage"), r.Form.Get("sort"), r.Form.Get("order"), numbers.key()}, "\x00"), true
+}
+
+// getCachedPage returns the cached page with the CSRF token inserted.
+// The page is only used if the poll did not change since rendering.
+func getCachedPage(cacheKey string, lastActivity time.Time, closed bool, csrf string) ([]byte, bool) {
+	pageCacheMutex.Lock()
+	e, ok := pageCache[cacheKey]
+	pageCacheMutex.Unlock()
+	if !ok || time.Since(e.created) > time.Duration(config.PageCacheSeconds)*time.Second || !e.lastActivity.Equal(lastActivity) || e.closed != closed {
+		return nil, false
+	}
+	return bytes.ReplaceAll(e.page, pageCacheCSRFPlaceholder, []byte(csrf)), true
+}
+
+// putCachedPage stores a page rendered with pageCacheCSRFPlaceholder as CSRF token.
+func putCachedPage(cacheKey string, page []byte, lastActivity time.Time, closed bool) {
+	pageCacheMutex.Lock()
+	defer pageCacheMutex.Unlock()
+	if len(pageCache) >= pageCacheMaxEntries {
+		pageCache = make(map[string]pageCacheEntry)
+	}
+	pageCache[cacheKey] = pageCacheEntry{page: page, created: time.Now(), lastActivity: lastActivity, closed: closed}
+}
+
+// invalidatePageCache removes all cached pages of the poll stored under key.
+func invalidatePageCache(key string) {
+	if config.PageCacheSeconds <= 0 {
+		return
+	}
+	prefix := strings.Join([]string{key, ""}, "\x00")
+	pageCacheMutex.Lock()
+	defer pageCacheMutex.Unlock()
+	for k := range pageCache {
+		if strings.HasPrefix(k, prefix) {
+			delete(pageCache, k)
+		}
+	}
+}
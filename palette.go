@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sort"
+	"strconv"
+)
+
+// accessiblePalettes contains the palettes which can be selected through 'AccessiblePalette'.
+// Colours are ordered from the best to the worst answer value.
+var accessiblePalettes = map[string][]string{
+	// Okabe-Ito palette, distinguishable with all common forms of colour blindness
+	"colourblind": {"#0072B2", "#56B4E9", "#F0E442", "#E69F00", "#D55E00"},
+	// Maximal differences in lightness
+	"highcontrast": {"#000000", "#004488", "#FFFFFF", "#DDAA33", "#882255"},
+}
+
+// answerColours returns the colours used to display the answer options in the results.
+// If 'AccessiblePalette' is set, the configured colours are replaced by the palette based on the value of each answer option.
+// Answer options with the same value get the same colour.
+func (p Poll) answerColours() []string {
+	colours := make([]string, len(p.AnswerOption))
+	palette := accessiblePalettes[config.AccessiblePalette]
+	if len(palette) == 0 {
+		for i := range p.AnswerOption {
+			colours[i] = p.AnswerOption[i][2]
+		}
+		return colours
+	}
+
+	values := make([]float64, len(p.AnswerOption))
+	distinct := make([]float64, 0, len(p.AnswerOption))
+	seen := make(map[float64]bool, len(p.AnswerOption))
+	for i := range p.AnswerOption {
+		f, err := strconv.ParseFloat(p.AnswerOption[i][1], 64)
+		if err != nil {
+			f = 0.0
+		}
+		values[i] = f
+		if !seen[f] {
+			seen[f] = true
+			distinct = append(distinct, f)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(distinct)))
+
+	for i := range values {
+		rank := sort.Search(len(distinct), func(j int) bool { return distinct[j] <= values[i] })
+		if len(distinct) == 1 {
+			colours[i] = palette[0]
+			continue
+		}
+		// Spread the values over the whole palette so that best and worst answer always use the outermost colours
+		colours[i] = palette[rank*(len(palette)-1)/(len(distinct)-1)]
+	}
+	return colours
+}
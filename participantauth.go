@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	auth "github.com/Top-Ranger/pollgo/authenticater"
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+// participantAuthenticater is used to authenticate participants before answering. It is nil if answering requires no authentication.
+var participantAuthenticater registry.Authenticater
+
+// initialiseParticipantAuthenticater loads the authenticater configured through 'ParticipantAuthenticater'.
+// With 'OnlyAuthenticatedCanCreate', answering is open to everyone, so no authenticater is loaded.
+func initialiseParticipantAuthenticater() error {
+	if config.ParticipantAuthenticater == "" || config.OnlyAuthenticatedCanCreate {
+		return nil
+	}
+	a, ok := registry.GetAuthenticater(config.ParticipantAuthenticater)
+	if !ok {
+		return fmt.Errorf("unknown participant authenticater %s", config.ParticipantAuthenticater)
+	}
+	// The authenticater may be the same as the one for creators, so it must not be loaded twice
+	if config.AuthenticationEnabled && config.ParticipantAuthenticater == config.Authenticater && config.ParticipantAuthenticaterConfig == config.AuthenticaterConfig {
+		participantAuthenticater = a
+		return nil
+	}
+	if config.AuthenticationEnabled && config.ParticipantAuthenticater == config.Authenticater {
+		return errors.New("ParticipantAuthenticater must differ from Authenticater if a different configuration is used")
+	}
+	b, err := os.ReadFile(config.ParticipantAuthenticaterConfig)
+	if err != nil {
+		return err
+	}
+	err = a.LoadConfig(b)
+	if err != nil {
+		return err
+	}
+	if h, ok := a.(*auth.Htpasswd); ok {
+		err = h.WatchFile(config.ParticipantAuthenticaterConfig)
+		if err != nil {
+			return err
+		}
+	}
+	participantAuthenticater = a
+	return nil
+}
+
+// checkParticipant verifies the user / password combination of the request through the participant authenticater if one is configured.
+// It returns false if the request must not be processed further. In that case, the response has already been written.
+func checkParticipant(rw http.ResponseWriter, r *http.Request) bool {
+	if participantAuthenticater == nil {
+		return true
+	}
+	user, pw := r.Form.Get("participantuser"), r.Form.Get("participantpw")
+	if len(user) == 0 || len(pw) == 0 {
+		rw.WriteHeader(http.StatusForbidden)
+		t := textTemplateStruct{"403 Forbidden", GetDefaultTranslation(), config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return false
+	}
+	correct, err := authenticateWith(participantAuthenticater, r, user, pw)
+	if errors.Is(err, ErrLoginLocked) {
+		rw.Header().Set("Retry-After", strconv.Itoa(config.LoginLockoutMinutes*60))
+		rw.WriteHeader(http.StatusTooManyRequests)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(GetDefaultTranslation().LoginLocked)), GetDefaultTranslation(), config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return false
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return false
+	}
+	if !correct {
+		if config.LogFailedLogin {
+			log.Printf("Failed participant authentication from %s", GetRealIP(r))
+		}
+		rw.WriteHeader(http.StatusForbidden)
+		t := textTemplateStruct{"403 Forbidden", GetDefaultTranslation(), config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,287 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	auth "github.com/Top-Ranger/pollgo/authenticater"
+)
+
+// passwordResetValidity is the time a password reset link can be used.
+const passwordResetValidity = time.Hour
+
+// passwordResetMailInterval is the minimal time between two reset mails to the same user.
+const passwordResetMailInterval = 5 * time.Minute
+
+var tokenSecret []byte
+var tokenSecretOnce sync.Once
+
+var passwordResetMails = make(map[string]time.Time)
+var passwordResetMailsMutex sync.Mutex
+
+var passwordResetRequestTemplate = template.Must(template.New("passwordresetrequest").Parse(`
+<h1>{{.ForgotPassword}}</h1>
+<form method="POST">
+  <p><label for="user">{{.EmailAddress}}: </label><input type="email" id="user" name="user" maxlength="254" required></p>
+  <p><input type="submit" value="{{.ResetPassword}}"></p>
+</form>
+`))
+
+var passwordResetTemplate = template.Must(template.New("passwordreset").Parse(`
+<h1>{{.Translation.ResetPassword}} ({{.User}})</h1>
+<form method="POST">
+  <input type="hidden" name="token" value="{{.Token}}">
+  <table style="border: none;">
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="pw">{{.Translation.Password}}: </label></td>
+      <td style="border: none;"><input type="password" id="pw" name="pw" maxlength="500" autocomplete="new-password" required></td>
+    </tr>
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="pw2">{{.Translation.RepeatPassword}}: </label></td>
+      <td style="border: none;"><input type="password" id="pw2" name="pw2" maxlength="500" autocomplete="new-password" required></td>
+    </tr>
+  </table>
+  <p><input type="submit" value="{{.Translation.ResetPassword}}"></p>
+</form>
+`))
+
+type passwordResetTemplateStruct struct {
+	User        string
+	Token       string
+	Translation Translation
+}
+
+// getTokenSecret returns the key used to sign reset tokens and login sessions.
+// If 'TokenSecret' is not set, a random key is used and links and sessions become invalid on restart.
+func getTokenSecret() []byte {
+	tokenSecretOnce.Do(func() {
+		if config.TokenSecret != "" {
+			tokenSecret = []byte(config.TokenSecret)
+			return
+		}
+		tokenSecret = make([]byte, 32)
+		_, err := rand.Read(tokenSecret)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return tokenSecret
+}
+
+// passwordResetSignature signs the user, the expiry and the current password hash.
+// Including the hash makes the token invalid after the password was changed.
+func passwordResetSignature(user string, expires int64, hash string) []byte {
+	mac := hmac.New(sha256.New, getTokenSecret())
+	fmt.Fprintf(mac, "%s\x00%d\x00%s", user, expires, hash)
+	return mac.Sum(nil)
+}
+
+// createPasswordResetToken returns a signed token which allows to reset the password of the user.
+func createPasswordResetToken(user, hash string) string {
+	expires := time.Now().Add(passwordResetValidity).Unix()
+	return strings.Join([]string{base64.RawURLEncoding.EncodeToString([]byte(user)), strconv.FormatInt(expires, 10), base64.RawURLEncoding.EncodeToString(passwordResetSignature(user, expires, hash))}, ".")
+}
+
+// verifyPasswordResetToken returns the user of a valid token or false if the token is invalid or expired.
+func verifyPasswordResetToken(token string) (string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", false
+	}
+	u, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", false
+	}
+	user := string(u)
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return "", false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", false
+	}
+	hash, ok, err := localUserHash(user)
+	if err != nil || !ok {
+		return "", false
+	}
+	if !hmac.Equal(sig, passwordResetSignature(user, expires, hash)) {
+		return "", false
+	}
+	return user, true
+}
+
+// localUserHash returns the current password hash of the user from the user file of the Htpasswd authenticater.
+func localUserHash(user string) (string, bool, error) {
+	b, err := os.ReadFile(config.AuthenticaterConfig)
+	if err != nil {
+		return "", false, err
+	}
+	users, err := auth.ParseHtpasswd(b)
+	if err != nil {
+		return "", false, err
+	}
+	hash, ok := users[user]
+	return hash, ok, nil
+}
+
+// setLocalUserPassword replaces the password hash of an existing user and reloads the authenticater.
+func setLocalUserPassword(user, hash string) error {
+	htpasswdMutex.Lock()
+	defer htpasswdMutex.Unlock()
+
+	err := updateHtpasswd(config.AuthenticaterConfig, user, hash, false)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(config.AuthenticaterConfig)
+	if err != nil {
+		return err
+	}
+	return authenticater.LoadConfig(b)
+}
+
+func passwordResetHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	rw.Header().Set("Referrer-Policy", "no-referrer")
+	tl := GetDefaultTranslation()
+
+	err := r.ParseForm()
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	token := r.Form.Get("token")
+
+	switch {
+	case r.Method == http.MethodGet && token == "":
+		buf := bytes.Buffer{}
+		err := passwordResetRequestTemplate.Execute(&buf, tl)
+		if err != nil {
+			log.Printf("password reset: %s", err.Error())
+		}
+		t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	case r.Method == http.MethodGet:
+		user, ok := verifyPasswordResetToken(token)
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.InvalidToken)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		buf := bytes.Buffer{}
+		err := passwordResetTemplate.Execute(&buf, passwordResetTemplateStruct{User: user, Token: token, Translation: tl})
+		if err != nil {
+			log.Printf("password reset: %s", err.Error())
+		}
+		t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	case r.Method == http.MethodPost && token == "":
+		user := strings.TrimSpace(r.Form.Get("user"))
+		if !validMailAddress(user) {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.InvalidEmailAddress)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		hash, ok, err := localUserHash(user)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		now := time.Now()
+		send := false
+		passwordResetMailsMutex.Lock()
+		for k, v := range passwordResetMails {
+			if now.Sub(v) > passwordResetMailInterval {
+				delete(passwordResetMails, k)
+			}
+		}
+		if _, recent := passwordResetMails[user]; ok && !recent {
+			passwordResetMails[user] = now
+			send = true
+		}
+		passwordResetMailsMutex.Unlock()
+
+		// Unknown users get the same answer so that existing users are not revealed
+		if send {
+			link := fmt.Sprintf("%s%s/resetpassword.html?token=%s", config.PublicURL, config.ServerPath, createPasswordResetToken(user, hash))
+			err = sendMail(user, fmt.Sprintf("%s: %s", config.InstanceName, tl.ResetPassword), fmt.Sprintf(tl.PasswordResetMailText, config.InstanceName, link))
+			if err != nil {
+				log.Printf("password reset: can not send mail: %s", err.Error())
+			}
+		}
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PasswordResetMailSent)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	case r.Method == http.MethodPost:
+		user, ok := verifyPasswordResetToken(token)
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.InvalidToken)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		pw := r.Form.Get("pw")
+		if pw == "" || len(pw) > 500 || pw != r.Form.Get("pw2") {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PasswordsDoNotMatch)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		hash, err := auth.HashPassword(pw)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		err = setLocalUserPassword(user, hash)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		log.Printf("password reset: changed password of %s", user)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PasswordChanged)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	default:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		t := textTemplateStruct{"405 Method Not Allowed", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	}
+}
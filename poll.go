@@ -17,19 +17,26 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
 	"math"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Top-Ranger/pollgo/helper"
+	"github.com/Top-Ranger/pollgo/registry"
 	"github.com/go-playground/colors"
 )
 
@@ -38,11 +45,58 @@ import (
 // It is adviced to create an own instance for each concurrent use.
 // Results will be shared throuh the DataSafe.
 type Poll struct {
-	AnswerOption [][]string // [text, value, colour]
-	Questions    []string
-	Description  string
-	Deleted      bool
-	initialised  bool
+	Version          int        // Version of the configuration schema, see PollVersion
+	Type             string     // Type of the poll on creation (normal, date, opinion), empty if unknown
+	AnswerOption     [][]string // [text, value, colour]
+	Questions        []string
+	Description      string
+	Deleted          bool
+	DisableComments  bool
+	UniqueNames      bool
+	Anonymous        bool // names are not stored, voters get a receipt for their answer instead
+	ShuffleOrder     bool
+	ShowPercentages  bool
+	ResultVisibility string // "" if all answers are shown, otherwise resultsHiddenUntilClosed or resultsTotalsOnly
+	MultipleChoice   bool   // each question accepts several answer options, see multiplechoice.go
+	AnswerLayout     string // "" to choose the layout of the answer form by the device of the visitor, otherwise answerLayoutGrid or answerLayoutCompact (see answerlayout.go)
+	Capacity         []int  // maximum number of participants picking each question, 0 for no limit, nil if no question is limited (see capacity.go)
+	Sections         []PollSection
+	Deadline         time.Time     // zero if the poll has no deadline
+	Expiry           time.Time     // zero if the poll does not expire, the poll is read-only afterwards
+	ConsentText      string        // overrides the consent text of the instance if set
+	ConsentURL       string        // overrides the linked consent document of the instance if set
+	Hidden           bool          // set by moderators for reported polls
+	Locked           bool          // set by moderators, no answers are accepted while set
+	CreatorClosed    bool          // set by the creator, no answers are accepted while set
+	Dates            []PollDate    // date of each question for date polls, nil if not known
+	FinalDate        int           // index of the question chosen as final date plus one, 0 if none was chosen
+	InvitationsSent  []int         // values of FinalDate for which calendar invitations were sent (see calendar.go)
+	FollowUp         *PollFollowUp // nil if no follow-up poll should be created
+	initialised      bool
+}
+
+// PollDate represents the date of a single question of a date poll.
+type PollDate struct {
+	Time   time.Time // wall clock time, the location has no meaning
+	NoTime bool      // whether the question only shows the date
+}
+
+// PollFollowUp describes the follow-up poll which is created once the deadline of a date poll is reached.
+type PollFollowUp struct {
+	Days    int    // interval by which the dates and the deadline are shifted
+	Created string // key of the follow-up poll, empty if it was not created yet
+}
+
+// PollSection represents a titled group of consecutive questions.
+type PollSection struct {
+	Title string
+	Start int // index of the first question of the section
+}
+
+// pollSectionHeader represents a section in the header of the results table.
+type pollSectionHeader struct {
+	Title string
+	Span  int
 }
 
 type pollTemplateStruct struct {
@@ -54,34 +108,273 @@ type pollTemplateStruct struct {
 	Comments        []string
 	IDs             []string
 	CanEdit         []bool
+	Endorsements    []int
+	Endorsed        []bool
 	Points          []float64
 	BestValue       float64
+	Means           []float64 // only set for opinion polls
+	Medians         []float64 // only set for opinion polls
+	Numbers         numberFormat
+	AnswerOptions   []string
+	Percentages     [][]float64 // [answer option][question], only set if ShowPercentages is set or the results are summarised
+	Suggestions     []dateSuggestion
+	Full            []bool   // whether a question reached its capacity, nil if no question is limited
+	Capacity        []string // occupied and total capacity of each question, empty for questions without capacity, nil if no question is limited or the results are hidden
+	ShowComments    bool
+	SectionStarts   []string
+	SectionHeaders  []pollSectionHeader
+	ConsentText     string
+	ConsentURL      string
+	Transposed      bool
+	Sort            string // sort order of the answers, empty for the order of the data safe (see resultsort.go)
+	SortDesc        bool
+	AnswersHidden   bool // whether the answers of other participants are not shown
+	ResultsHidden   bool // whether aggregated results are not shown either
+	Summarised      bool // whether the answers exceed the render budget and are shown in pages
+	ResultPage      int  // current page of answers starting at 1, 0 if not summarised
+	ResultPages     int
+	PreviousResult  int // previous page of answers, 0 if none
+	NextResult      int // next page of answers, 0 if none
+	TotalAnswers    int
+	Deadline        string
+	DeadlineUnix    int64
+	Expiry          string
+	Expired         bool
+	Closed          bool
+	Locked          bool
+	CreatorClosed   bool
+	Anonymous       bool
+	FollowUpDays    int    // 0 if no follow-up poll is created
+	FollowUp        string // key of the created follow-up poll
+	FinalDate       string
+	FinalDateIndex  int  // index of the question chosen as final date, -1 if none was chosen
+	CanChooseFinal  bool // whether the poll knows the dates of its questions
+	CalendarInvites bool
 	Description     template.HTML
 	HasPassword     bool
+	CoOrganizers    bool   // whether the creator can name co-organizers
+	LoginURL        string // URL of the login page, empty if sessions are not enabled
+	SessionUser     string // user of the login session
+	Presence        bool
+	LiveUpdates     bool
+	Indexable       bool
+	StructuredData  template.JS // schema.org JSON-LD of the poll and its aggregated results
+	Discussion      bool
+	DiscussionNames []string
+	DiscussionTexts []string
+	DiscussionTimes []string
+	DiscussionIDs   []string
+	CSRF            string
 	Translation     Translation
 	ServerPath      string
 }
 
 type answerTemplateStruct struct {
-	Key          string
-	EditID       string
-	AnswerOption [][]string // [text, value, colour]
-	Questions    []string
-	Description  template.HTML
-	Name         string
-	Comment      string
-	Answers      []int
-	Translation  Translation
-	ServerPath   string
+	Key            string
+	EditID         string
+	AnswerOption   [][]string // [text, value, colour]
+	Questions      []string
+	Order          []int    // display order of Questions
+	Carry          [][2]int // [question, answer] given on other pages
+	Page           int      // current page starting at 1, 0 if not paginated
+	PreviousPage   int      // value of the 'page' parameter of the previous page
+	Pages          int
+	LastPage       bool
+	SectionStarts  []string
+	Description    template.HTML
+	Name           string
+	Comment        string
+	ShowComments   bool
+	ConsentText    string
+	ConsentURL     string
+	Revision       string
+	Invite         string // token of the invitation, empty if answering without invitation
+	InviteName     string
+	Receipt        string // receipt of the answer in anonymous polls, empty if answering without receipt
+	Anonymous      bool
+	AskMail        bool
+	Participant    bool   // whether participants must log in
+	Mail           string // only carried between pages, saved addresses are never shown
+	Answers        []int
+	Prefill        string   // suggested selections given through the 'prefill' parameter, carried between pages (see prefill.go)
+	Selected       [][]bool // [question][answer option], derived from Answers
+	MultipleChoice bool
+	Compact        bool   // whether the compact layout is used instead of the grid
+	Layout         string // layout requested through the 'layout' parameter, carried between pages
+	SwitchLayout   string // URL of the form in the other layout
+	WhiteFont      []bool // whether the answer options need a white font in the compact layout
+	Full           []bool // whether a question reached its capacity
+	Positive       []bool // whether an answer option picks a question, i.e. can not be chosen for full questions
+	Presence       bool
+	CSRF           string
+	Translation    Translation
+	ServerPath     string
+}
+
+// paginate restricts the displayed questions to the page requested in form.
+// Answers given on other pages are carried along as hidden fields, so no state needs to be kept on the server.
+func (td *answerTemplateStruct) paginate(p Poll, form url.Values, pageSize int) {
+	td.Pages = (len(td.Order) + pageSize - 1) / pageSize
+	page, err := strconv.Atoi(form.Get("page"))
+	if err != nil || page < 0 {
+		page = 0
+	}
+	if page >= td.Pages {
+		page = td.Pages - 1
+	}
+	td.Page = page + 1
+	td.PreviousPage = page - 1
+	td.LastPage = td.Page == td.Pages
+
+	// Answers from other pages take precedence over saved answers
+	for i := range td.Answers {
+		values, ok := form[strconv.Itoa(i)]
+		if !ok {
+			continue
+		}
+		a, ok := p.parseFormAnswer(values)
+		if ok && a >= 0 {
+			td.Answers[i] = a
+		}
+	}
+	if form.Has("name") {
+		td.Name = form.Get("name")
+	}
+	if form.Has("comment") {
+		td.Comment = form.Get("comment")
+	}
+	if form.Has("mail") {
+		td.Mail = form.Get("mail")
+	}
+
+	td.Order = td.Order[page*pageSize : min((page+1)*pageSize, len(td.Order))]
+	shown := make(map[int]bool, len(td.Order))
+	for _, q := range td.Order {
+		shown[q] = true
+	}
+	td.Carry = make([][2]int, 0, len(td.Answers))
+	for i := range td.Answers {
+		if shown[i] || td.Answers[i] < 0 {
+			continue
+		}
+		if !p.MultipleChoice {
+			td.Carry = append(td.Carry, [2]int{i, td.Answers[i]})
+			continue
+		}
+		// Multiple choice answers are carried as one field per selected answer option, as they are sent by the form
+		td.Carry = append(td.Carry, [2]int{i, multipleChoiceNone})
+		for _, o := range p.selectedOptions(td.Answers[i]) {
+			td.Carry = append(td.Carry, [2]int{i, o})
+		}
+	}
 }
 
 type newTemplateStruct struct {
 	Key         string
 	HasPassword bool
+	LoginURL    string // URL of the login page, empty if sessions are not enabled
+	SessionUser string // user of the login session
+	Uploads     bool
+	CSRF        string
 	Translation Translation
 	ServerPath  string
 }
 
+// renderBudgetExceeded returns whether the answers of a poll with the given size exceed 'RenderBudget' (number of answer cells shown at once).
+func renderBudgetExceeded(answers, questions int) bool {
+	return config.RenderBudget > 0 && answers*questions > config.RenderBudget
+}
+
+// summarise restricts the displayed answers to the requested page so that at most 'RenderBudget' answer cells are shown.
+// Aggregated results (points, percentages, ...) must be calculated before, as they cover all answers.
+func (td *pollTemplateStruct) summarise(resultPage string, questions int) {
+	pageSize := 1
+	if questions > 0 && config.RenderBudget/questions > 1 {
+		pageSize = config.RenderBudget / questions
+	}
+	td.TotalAnswers = len(td.Answers)
+	td.ResultPages = (len(td.Answers) + pageSize - 1) / pageSize
+	page, err := strconv.Atoi(resultPage)
+	if err != nil || page < 1 {
+		page = 1
+	}
+	if page > td.ResultPages {
+		page = td.ResultPages
+	}
+	td.ResultPage = page
+	td.PreviousResult = page - 1
+	if page < td.ResultPages {
+		td.NextResult = page + 1
+	}
+
+	start := (page - 1) * pageSize
+	end := min(start+pageSize, len(td.Answers))
+	td.Answers = td.Answers[start:end]
+	td.AnswerWhiteFont = td.AnswerWhiteFont[start:end]
+	td.Names = td.Names[start:end]
+	td.Comments = td.Comments[start:end]
+	td.IDs = td.IDs[start:end]
+	td.CanEdit = td.CanEdit[start:end]
+	td.Endorsements = td.Endorsements[start:end]
+	td.Endorsed = td.Endorsed[start:end]
+}
+
+// hideAnswers removes all answers from the template which the visitor can not edit, so they are never sent to the browser.
+// If resultsHidden is set, aggregated results are removed as well.
+func (td *pollTemplateStruct) hideAnswers(resultsHidden bool) {
+	td.AnswersHidden = true
+	td.ResultsHidden = resultsHidden
+	var keep []int
+	for i := range td.CanEdit {
+		if td.CanEdit[i] {
+			keep = append(keep, i)
+		}
+	}
+	answers := make([][][]string, len(keep))
+	whiteFont := make([][]bool, len(keep))
+	names := make([]string, len(keep))
+	comments := make([]string, len(keep))
+	ids := make([]string, len(keep))
+	canEdit := make([]bool, len(keep))
+	endorsements := make([]int, len(keep))
+	endorsed := make([]bool, len(keep))
+	for i, k := range keep {
+		answers[i] = td.Answers[k]
+		whiteFont[i] = td.AnswerWhiteFont[k]
+		names[i] = td.Names[k]
+		comments[i] = td.Comments[k]
+		ids[i] = td.IDs[k]
+		canEdit[i] = true
+		endorsements[i] = td.Endorsements[k]
+		endorsed[i] = td.Endorsed[k]
+	}
+	td.Answers = answers
+	td.AnswerWhiteFont = whiteFont
+	td.Names = names
+	td.Comments = comments
+	td.IDs = ids
+	td.CanEdit = canEdit
+	td.Endorsements = endorsements
+	td.Endorsed = endorsed
+
+	if resultsHidden {
+		td.Points = nil
+		td.BestValue = 0
+		td.AnswerOptions = nil
+		td.Percentages = nil
+		td.Suggestions = nil
+		td.Means = nil
+		td.Medians = nil
+		td.Capacity = nil
+	}
+}
+
+// endorseCookiePrefix is the prefix of the cookies marking an answer as endorsed by the visitor.
+const endorseCookiePrefix = "endorsed-"
+
+// shuffleCookieName is the name of the cookie holding the seed for the display order of questions.
+const shuffleCookieName = "shuffle"
+
 var pollTemplate *template.Template
 var answerTemplate *template.Template
 var newTemplate *template.Template
@@ -101,17 +394,17 @@ try {
 
 func init() {
 	var err error
-	pollTemplate, err = template.ParseFS(templateFiles, "template/poll.html")
+	pollTemplate, err = template.New("poll.html").Funcs(templateFunctions).ParseFS(templateFiles, "template/poll.html", "template/transposed.html")
 	if err != nil {
 		panic(err)
 	}
 
-	answerTemplate, err = template.ParseFS(templateFiles, "template/answer.html")
+	answerTemplate, err = template.New("answer.html").Funcs(templateFunctions).ParseFS(templateFiles, "template/answer.html")
 	if err != nil {
 		panic(err)
 	}
 
-	newTemplate, err = template.ParseFS(templateFiles, "template/new.html")
+	newTemplate, err = template.New("new.html").Funcs(templateFunctions).ParseFS(templateFiles, "template/new.html")
 	if err != nil {
 		panic(err)
 	}
@@ -143,17 +436,108 @@ func VerifyPollConfig(p Poll) bool {
 		return false
 	}
 
+	// Date and opinion polls rely on a single answer option per question
+	if p.MultipleChoice && (len(p.AnswerOption) > maxMultipleChoiceOptions || (p.Type != "" && p.Type != "normal")) {
+		return false
+	}
+
+	for i := range p.Sections {
+		if p.Sections[i].Title == "" || p.Sections[i].Start < 0 || p.Sections[i].Start >= len(p.Questions) {
+			return false
+		}
+		if i > 0 && p.Sections[i].Start <= p.Sections[i-1].Start {
+			return false
+		}
+	}
+
+	if len(p.ConsentText) > 500 || len(p.ConsentURL) > 500 {
+		return false
+	}
+	if p.ConsentURL != "" && !strings.HasPrefix(p.ConsentURL, "https://") && !strings.HasPrefix(p.ConsentURL, "http://") && !strings.HasPrefix(p.ConsentURL, "/") {
+		return false
+	}
+
+	if p.Dates != nil && len(p.Dates) != len(p.Questions) {
+		return false
+	}
+
+	if p.Capacity != nil {
+		if len(p.Capacity) != len(p.Questions) {
+			return false
+		}
+		for _, c := range p.Capacity {
+			if c < 0 || c > maxCapacity {
+				return false
+			}
+		}
+	}
+
+	if p.FinalDate < 0 || p.FinalDate > len(p.Dates) {
+		return false
+	}
+	for _, d := range p.InvitationsSent {
+		if d < 1 || d > len(p.Dates) {
+			return false
+		}
+	}
+
+	if !p.Expiry.IsZero() && !p.Deadline.IsZero() && p.Expiry.Before(p.Deadline) {
+		return false
+	}
+
+	if p.FollowUp != nil {
+		if p.Deadline.IsZero() || p.Dates == nil || p.FollowUp.Days < 1 || p.FollowUp.Days > followUpMaxDays {
+			return false
+		}
+	}
+
 	return true
 }
 
+// sectionStarts returns for each question the title of the section starting at that question or an empty string.
+func (p Poll) sectionStarts() []string {
+	starts := make([]string, len(p.Questions))
+	for i := range p.Sections {
+		if p.Sections[i].Start >= 0 && p.Sections[i].Start < len(starts) {
+			starts[p.Sections[i].Start] = p.Sections[i].Title
+		}
+	}
+	return starts
+}
+
+// sectionHeaders returns the sections covering all questions in order.
+// Questions before the first section are covered by a section without title.
+func (p Poll) sectionHeaders() []pollSectionHeader {
+	if len(p.Sections) == 0 {
+		return nil
+	}
+	headers := make([]pollSectionHeader, 0, len(p.Sections)+1)
+	if p.Sections[0].Start > 0 {
+		headers = append(headers, pollSectionHeader{Span: p.Sections[0].Start})
+	}
+	for i := range p.Sections {
+		end := len(p.Questions)
+		if i+1 < len(p.Sections) {
+			end = p.Sections[i+1].Start
+		}
+		headers = append(headers, pollSectionHeader{Title: p.Sections[i].Title, Span: end - p.Sections[i].Start})
+	}
+	return headers
+}
+
 // LoadPoll loads  and initialises the poll from the current provided configuration.
+// Configurations of older versions are migrated to the current version.
 // PLEASE NOTE: The loaded poll is not verified. If you use an untrusted source, you need to verify the poll else the behaviour is undefined.
 func LoadPoll(config []byte) (Poll, error) {
 	if len(config) == 0 {
 		return Poll{initialised: false}, nil
 	}
+	config, err := migratePollConfig(config)
+	if err != nil {
+		return Poll{initialised: false}, err
+	}
 	var p Poll
-	err := json.Unmarshal(config, &p)
+	err = json.Unmarshal(config, &p)
 	if err != nil {
 		return Poll{initialised: false}, err
 	}
@@ -161,120 +545,577 @@ func LoadPoll(config []byte) (Poll, error) {
 	return p, nil
 }
 
+// Consent returns the consent text and the URL of the linked document for this poll.
+// The values of the instance are used if the poll does not override them.
+func (p Poll) Consent() (string, string) {
+	text, url := p.ConsentText, p.ConsentURL
+	if text == "" {
+		text = config.ConsentText
+	}
+	if text == "" {
+		text = GetDefaultTranslation().AcceptPrivacyPolicy
+	}
+	if url == "" {
+		url = config.ConsentURL
+	}
+	return text, url
+}
+
+// Closed returns whether the poll accepts no more answers, either because the deadline or expiry date has passed or because it is locked or closed by the creator.
+func (p Poll) Closed() bool {
+	return p.Locked || p.CreatorClosed || p.Expired() || (!p.Deadline.IsZero() && time.Now().After(p.Deadline))
+}
+
+// Values of ResultVisibility.
+const (
+	resultsHiddenUntilClosed = "closed" // answers and aggregated results are hidden until the poll is closed
+	resultsTotalsOnly        = "totals" // only aggregated results are shown
+)
+
+// parseResultVisibility returns the ResultVisibility for the value of a form. Unknown values show all answers.
+func parseResultVisibility(v string) string {
+	switch v {
+	case resultsHiddenUntilClosed, resultsTotalsOnly:
+		return v
+	default:
+		return ""
+	}
+}
+
+// answersHidden returns whether the answers of participants must not be shown to others.
+func (p Poll) answersHidden() bool {
+	return p.ResultVisibility == resultsTotalsOnly || p.resultsHidden()
+}
+
+// resultsHidden returns whether aggregated results must not be shown either.
+func (p Poll) resultsHidden() bool {
+	return p.ResultVisibility == resultsHiddenUntilClosed && !p.Closed()
+}
+
+// closedMessage returns the message shown when answering a closed poll.
+func (p Poll) closedMessage(tl Translation) string {
+	if p.Locked {
+		return tl.PollLocked
+	}
+	if p.CreatorClosed {
+		return tl.PollClosedByCreator
+	}
+	if p.Expired() {
+		return tl.PollExpired
+	}
+	return tl.PollClosed
+}
+
+// Revision returns a token identifying the structure (questions and answer options) of the poll.
+// It changes whenever a question or answer option is added, removed or reordered.
+func (p Poll) Revision() string {
+	h := sha256.New()
+	for i := range p.Questions {
+		h.Write([]byte(p.Questions[i]))
+		h.Write([]byte{0})
+	}
+	h.Write([]byte{1})
+	for i := range p.AnswerOption {
+		for j := range p.AnswerOption[i] {
+			h.Write([]byte(p.AnswerOption[i][j]))
+			h.Write([]byte{0})
+		}
+		h.Write([]byte{1})
+	}
+	if p.MultipleChoice {
+		// Results are stored differently, so answers can not be carried over
+		h.Write([]byte("multiplechoice"))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// saveNewPoll saves the configuration of a newly created poll together with its creator (empty if unknown) and schedules reminders, follow-up polls and the expiry.
+func (p Poll) saveNewPoll(key, creator string) error {
+	b, err := p.ExportPoll()
+	if err != nil {
+		return err
+	}
+	err = safe.SavePollConfig(key, b)
+	if err != nil {
+		return err
+	}
+	if creator != "" {
+		err = safe.SavePollCreator(key, creator)
+		if err != nil {
+			return err
+		}
+	}
+	if config.ReminderWebhook != "" {
+		reminder := time.Time{}
+		if !p.Deadline.IsZero() {
+			reminder = p.Deadline.Add(-time.Duration(config.ReminderHours) * time.Hour)
+		}
+		err = safe.SetReminder(key, reminder)
+		if err != nil {
+			return err
+		}
+	}
+	if p.FollowUp != nil {
+		err = safe.SetFollowUp(key, p.Deadline)
+		if err != nil {
+			return err
+		}
+	}
+	if !p.Expiry.IsZero() {
+		err = safe.SetExpiry(key, p.Expiry)
+		if err != nil {
+			return err
+		}
+	}
+	hookPollCreated(key, creator)
+	return nil
+}
+
+// pollQuotaExceeded returns whether the user already created the maximum number of polls ('MaxPollsPerCreator').
+func pollQuotaExceeded(user string) (bool, error) {
+	if config.MaxPollsPerCreator <= 0 {
+		return false, nil
+	}
+	// Deleted polls have no creator, so only active polls are counted
+	polls, err := safe.GetPollsByCreator(user)
+	if err != nil {
+		return false, err
+	}
+	return len(polls) >= config.MaxPollsPerCreator, nil
+}
+
+// deletePoll marks the poll as deleted in the data safe. The data is removed on the next garbage collection.
+func (p Poll) deletePoll(key string) error {
+	p.Deleted = true
+	b, err := p.ExportPoll()
+	if err != nil {
+		return err
+	}
+	err = safe.SavePollConfig(key, b)
+	if err != nil {
+		return err
+	}
+	err = safe.MarkPollDeleted(key)
+	if err != nil {
+		return err
+	}
+	err = safe.SavePollCreator(key, "") // We don't need the creator any longer
+	if err != nil {
+		return err
+	}
+	err = safe.SavePollOrganizers(key, nil)
+	if err != nil {
+		return err
+	}
+	hookPollDeleted(key)
+	return nil
+}
+
 // ExportPoll returns the configuration of the poll at the time of calling.
-// The configuration is human readable.
+// The configuration is human readable and always uses the current version.
 func (p Poll) ExportPoll() ([]byte, error) {
+	p.Version = PollVersion
 	b, err := json.Marshal(&p)
 	return b, err
 }
 
+// questionOrder returns the order in which the questions are displayed to the visitor.
+// If the poll shuffles its order, the order is derived from a seed stored in a session cookie so it stays the same for the visitor.
+// The indices of the questions are not changed.
+func (p Poll) questionOrder(rw http.ResponseWriter, r *http.Request, key string) []int {
+	if !p.ShuffleOrder {
+		order := make([]int, len(p.Questions))
+		for i := range order {
+			order[i] = i
+		}
+		return order
+	}
+
+	var seed int64
+	c, err := r.Cookie(shuffleCookieName)
+	if err == nil {
+		seed, err = strconv.ParseInt(c.Value, 10, 64)
+	}
+	if err != nil {
+		seed = rand.Int63()
+		cookie := http.Cookie{}
+		cookie.Name = shuffleCookieName
+		cookie.Value = strconv.FormatInt(seed, 10)
+		cookie.Path = fmt.Sprintf("/%s", key)
+		cookie.SameSite = http.SameSiteLaxMode
+		cookie.HttpOnly = true
+		cookie.Secure = !config.InsecureAllowCookiesOverHTTP
+		http.SetCookie(rw, &cookie)
+	}
+	return rand.New(rand.NewSource(seed)).Perm(len(p.Questions))
+}
+
+// normaliseName returns the name in the form used for comparing participant names.
+func normaliseName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// nameTaken returns whether an answer other than exceptID with the same name (case insensitive) exists in the poll stored under key.
+// Empty names are never taken.
+func nameTaken(key, name, exceptID string) (bool, error) {
+	name = normaliseName(name)
+	if name == "" {
+		return false, nil
+	}
+	_, names, _, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		return false, err
+	}
+	for i := range names {
+		if i < len(ids) && ids[i] == exceptID {
+			continue
+		}
+		if normaliseName(names[i]) == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// managementRequiresPassword returns whether forms managing polls must contain user / password.
+// This is not the case if the request has a login session or creators log in through OpenID Connect.
+func managementRequiresPassword(r *http.Request) bool {
+	return config.AuthenticationEnabled && !oidcEnabled() && sessionUser(r) == ""
+}
+
+// checkCreator verifies the user / password combination of the request if authentication is enabled.
+// If mustBeCreator is true, the user must additionally be the creator or a co-organizer of the poll.
+// It returns false if the request must not be processed further. In that case, the response has already been written.
+func checkCreator(rw http.ResponseWriter, r *http.Request, key string, mustBeCreator bool) bool {
+	// Test password first
+	if config.AuthenticationEnabled {
+		_, correct, err := authenticateRequest(r)
+		if errors.Is(err, ErrLoginLocked) {
+			rw.Header().Set("Retry-After", strconv.Itoa(config.LoginLockoutMinutes*60))
+			rw.WriteHeader(http.StatusTooManyRequests)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(GetDefaultTranslation().LoginLocked)), GetDefaultTranslation(), config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return false
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return false
+		}
+		if !correct {
+			if config.LogFailedLogin {
+				log.Printf("Failed authentication from %s", GetRealIP(r))
+			}
+			rw.WriteHeader(http.StatusForbidden)
+			t := textTemplateStruct{"403 Forbidden", GetDefaultTranslation(), config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return false
+		}
+	}
+
+	// Test if user is creator - this can be skipped if no authentification is enabled
+	if config.AuthenticationEnabled && mustBeCreator {
+		user := requestUser(r) // is already authenticated
+		manager, err := isPollManager(key, user)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return false
+		}
+		if !manager {
+			tr := GetDefaultTranslation()
+			rw.WriteHeader(http.StatusForbidden)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(fmt.Sprintf("403 Forbidden (%s)", tr.UserNotCreator))), tr, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return false
+		}
+	}
+	return true
+}
+
 // HandleRequest handles a web request to this poll. The key needs to be provided.
 func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string) {
 	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 
+	if p.initialised && p.Hidden && !p.Deleted {
+		rw.WriteHeader(http.StatusForbidden)
+		tl := GetDefaultTranslation()
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollHidden)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPost:
 		if p.initialised {
 			// This is an existing poll
-			err := r.ParseForm()
+			var err error
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+				err = r.ParseMultipartForm(10000000) // 10 MB
+			} else {
+				err = r.ParseForm()
+			}
 			if err != nil {
 				rw.WriteHeader(http.StatusInternalServerError)
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
 
+			if !checkCSRF(rw, r) {
+				return
+			}
+			// Most requests change the poll, so cached pages are not valid afterwards
+			defer invalidatePageCache(key)
+
 			if r.Form.Get("delete") == "true" {
 				// Delete this poll and return
 
-				// Test password first
-				if config.AuthenticationEnabled {
-					user, pw := r.Form.Get("user"), r.Form.Get("pw")
-					if len(user) == 0 || len(pw) == 0 {
-						rw.WriteHeader(http.StatusForbidden)
-						t := textTemplateStruct{"403 Forbidden", GetDefaultTranslation(), config.ServerPath}
-						textTemplate.Execute(rw, t)
-						return
-					}
-					correct, err := authenticater.Authenticate(user, pw)
-					if err != nil {
-						rw.WriteHeader(http.StatusInternalServerError)
-						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
-						textTemplate.Execute(rw, t)
-						return
-					}
-					if !correct {
-						if config.LogFailedLogin {
-							log.Printf("Failed authentication from %s", GetRealIP(r))
-						}
-						rw.WriteHeader(http.StatusForbidden)
-						t := textTemplateStruct{"403 Forbidden", GetDefaultTranslation(), config.ServerPath}
-						textTemplate.Execute(rw, t)
-						return
-					}
+				if !checkCreator(rw, r, key, config.OnlyCreatorCanDelete) {
+					return
 				}
 
-				// Test if user is creator - this can be skipped if no authentification is enabled
-				if config.AuthenticationEnabled && config.OnlyCreatorCanDelete {
-					user := r.Form.Get("user") // is already authenticated
-					creator, err := safe.GetPollCreator(key)
-					if err != nil {
-						rw.WriteHeader(http.StatusInternalServerError)
-						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
-						textTemplate.Execute(rw, t)
-						return
-					}
-					if creator != "" && user != creator { // Also allow if creator is not set (e.g. old poll or poll created without authentification)
-						tr := GetDefaultTranslation()
-						rw.WriteHeader(http.StatusForbidden)
-						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(fmt.Sprintf("403 Forbidden (%s)", tr.UserNotCreator))), tr, config.ServerPath}
-						textTemplate.Execute(rw, t)
-						return
-					}
+				reportLargePollDeleted(r, key)
+
+				err := p.deletePoll(key)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
 				}
+				http.Redirect(rw, r, fmt.Sprintf("/%s", key), http.StatusSeeOther)
+				return
+			}
 
-				p.Deleted = true
+			if r.Form.Get("exportConfig") == "true" {
 				b, err := p.ExportPoll()
 				if err != nil {
 					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
-				err = safe.SavePollConfig(key, b)
+				rw.Write(b)
+				return
+			}
+
+			if r.Form.Get("gdprExport") == "true" {
+				if !checkCreator(rw, r, key, true) {
+					return
+				}
+				p.handleGDPRExport(rw, key, nil)
+				return
+			}
+
+			if r.Form.Get("fullExport") == "true" {
+				if !checkCreator(rw, r, key, true) {
+					return
+				}
+				p.handleFullExport(rw, key)
+				return
+			}
+
+			if r.Form.Get("invitations") == "true" {
+				if !checkCreator(rw, r, key, true) {
+					return
+				}
+				handleInvitations(rw, r, key)
+				return
+			}
+
+			if c := r.Form.Get("closePoll"); c != "" {
+				if !checkCreator(rw, r, key, true) {
+					return
+				}
+				if p.Deleted {
+					tl := GetDefaultTranslation()
+					rw.WriteHeader(http.StatusGone)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollIsDeleted)), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				p.CreatorClosed = c == "true"
+				b, err := p.ExportPoll()
+				if err == nil {
+					err = safe.SavePollConfig(key, b)
+				}
 				if err != nil {
 					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				if p.CreatorClosed {
+					recordPollEvent(key, pollEventClosed, "", requestUser(r))
+				} else {
+					recordPollEvent(key, pollEventReopened, "", requestUser(r))
+				}
+				http.Redirect(rw, r, fmt.Sprintf("/%s", key), http.StatusSeeOther)
+				return
+			}
+
+			if r.Form.Get("events") == "true" {
+				if !checkCreator(rw, r, key, true) {
+					return
+				}
+				handlePollEvents(rw, key)
+				return
+			}
+
+			if r.Form.Get("finaldate") == "true" {
+				if !checkCreator(rw, r, key, true) {
+					return
+				}
+				p.handleFinalDate(rw, r, key)
+				return
+			}
+
+			if r.Form.Get("dashboard") == "true" {
+				p.handleDashboard(rw, r, key)
+				return
+			}
+
+			if r.Form.Get("duplicates") == "true" {
+				p.handleDuplicates(rw, r, key)
+				return
+			}
+
+			if r.Form.Get("edit") == "true" {
+				p.handleEdit(rw, r, key)
+				return
+			}
+
+			if r.Form.Get("organizers") == "true" {
+				handleOrganizers(rw, r, key)
+				return
+			}
+
+			if reportsEnabled() && r.Form.Get("report") == "true" {
+				handleReport(rw, r, key)
+				return
+			}
+
+			if config.EnableDiscussion && r.Form.Get("discussion") != "" {
+				if p.Deleted {
+					tl := GetDefaultTranslation()
+					rw.WriteHeader(http.StatusGone)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollIsDeleted)), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				p.handleDiscussion(rw, r, key)
+				return
+			}
+
+			if answerID := r.Form.Get("endorse"); answerID != "" {
+				tl := GetDefaultTranslation()
+				if p.Closed() {
+					rw.WriteHeader(http.StatusForbidden)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(p.closedMessage(tl))), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				if p.answersHidden() {
+					// Answers can't be seen, so they can't be endorsed either
+					rw.WriteHeader(http.StatusForbidden)
+					t := textTemplateStruct{"403 Forbidden", tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+
+				cookieName := endorseCookiePrefix + answerID
+				if c, err := r.Cookie(cookieName); err == nil && c.Value != "" {
+					// Already endorsed
+					http.Redirect(rw, r, fmt.Sprintf("/%s", key), http.StatusSeeOther)
+					return
+				}
+
+				_, _, _, ids, err := safe.GetPollResult(key)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
-				err = safe.MarkPollDeleted(key)
+				if !slices.Contains(ids, answerID) {
+					rw.WriteHeader(http.StatusNotFound)
+					t := textTemplateStruct{"404 Not Found", tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+
+				err = safe.EndorseAnswer(key, answerID)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+
+				cookie := http.Cookie{}
+				cookie.Name = cookieName
+				cookie.Value = "1"
+				cookie.MaxAge = 365 * 24 * 60 * 60
+				cookie.Path = fmt.Sprintf("/%s", key)
+				cookie.SameSite = http.SameSiteLaxMode
+				cookie.HttpOnly = true
+				cookie.Secure = !config.InsecureAllowCookiesOverHTTP
+				http.SetCookie(rw, &cookie)
+
+				http.Redirect(rw, r, fmt.Sprintf("/%s", key), http.StatusSeeOther)
+				return
+			}
+
+			if r.Form.Get("importCSV") == "true" {
+				if !checkCreator(rw, r, key, true) {
+					return
+				}
+
+				tl := GetDefaultTranslation()
+				if p.Deleted {
+					rw.WriteHeader(http.StatusGone)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollIsDeleted)), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				if p.Closed() {
+					rw.WriteHeader(http.StatusForbidden)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(p.closedMessage(tl))), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				if instanceFull(false) {
+					writeInstanceFull(rw)
+					return
+				}
+
+				f, _, err := r.FormFile("csv")
 				if err != nil {
-					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					rw.WriteHeader(http.StatusBadRequest)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), tl, config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
-				err = safe.SavePollCreator(key, "") // We don't need the creator any longer
-				if err != nil {
-					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+				defer f.Close()
+
+				_, err = p.ImportCSV(key, f)
+				var csvErr csvImportError
+				if errors.As(err, &csvErr) {
+					rw.WriteHeader(http.StatusBadRequest)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(fmt.Sprintf("%s: %s", tl.InvalidCSV, csvErr.Error()))), tl, config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
-				http.Redirect(rw, r, fmt.Sprintf("/%s", key), http.StatusSeeOther)
-				return
-			}
-
-			if r.Form.Get("exportConfig") == "true" {
-				b, err := p.ExportPoll()
 				if err != nil {
 					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
-				rw.Write(b)
+				http.Redirect(rw, r, fmt.Sprintf("/%s", key), http.StatusSeeOther)
 				return
 			}
 
@@ -286,7 +1127,7 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 				change, err := safe.GetChange(key, answerID)
 				if err != nil {
 					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
@@ -320,13 +1161,25 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 					return
 				}
 
+				// The name is needed for the activity log
+				_, name, _, err := safe.GetSinglePollResult(key, answerID)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
 				err = safe.DeleteAnswer(key, answerID)
 				if err != nil {
 					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
+				countAnswerDeletion(r, key)
+				markAggregateDirty(key)
+				publishLiveUpdate(key, liveAnswerDeleted)
+				recordPollEvent(key, pollEventDeleted, answerID, name)
 
 				// Remove cookie
 				cookie := http.Cookie{}
@@ -344,6 +1197,18 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 				return
 			}
 
+			if p.Closed() {
+				rw.WriteHeader(http.StatusForbidden)
+				tl := GetDefaultTranslation()
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(p.closedMessage(tl))), tl, config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+
+			if !checkParticipant(rw, r) {
+				return
+			}
+
 			// Test DSGVO first
 			if r.Form.Get("dsgvo") == "" {
 				rw.WriteHeader(http.StatusForbidden)
@@ -352,17 +1217,25 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 				return
 			}
 
+			if instanceFull(false) {
+				writeInstanceFull(rw)
+				return
+			}
+
+			// Reject answers given for an older structure of the poll, as the answer indices might refer to other questions or options
+			if rev := r.Form.Get("revision"); rev != "" && rev != p.Revision() {
+				tl := GetDefaultTranslation()
+				rw.WriteHeader(http.StatusConflict)
+				text := fmt.Sprintf(`<p>%s</p><p><a href="/%s?answer=yes">%s</a></p>`, template.HTMLEscapeString(tl.PollChanged), template.HTMLEscapeString(key), template.HTMLEscapeString(tl.Participate))
+				t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+
 			results := make([]int, len(p.Questions))
 			for i := range p.Questions {
-				a := r.Form.Get(strconv.Itoa(i))
-				ai, err := strconv.Atoi(a)
-				if err != nil {
-					rw.WriteHeader(http.StatusBadRequest)
-					t := textTemplateStruct{"400 Bad Request", GetDefaultTranslation(), config.ServerPath}
-					textTemplate.Execute(rw, t)
-					return
-				}
-				if ai >= len(p.AnswerOption) {
+				ai, ok := p.parseFormAnswer(r.Form[strconv.Itoa(i)])
+				if !ok {
 					rw.WriteHeader(http.StatusBadRequest)
 					t := textTemplateStruct{"400 Bad Request", GetDefaultTranslation(), config.ServerPath}
 					textTemplate.Execute(rw, t)
@@ -372,20 +1245,175 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			}
 			change := helper.GetRandomString()
 
+			comment := r.Form.Get("comment")
+			if p.DisableComments {
+				comment = ""
+			}
+
+			name := r.Form.Get("name")
 			answerID := r.Form.Get("answerID")
+
+			// Answers given through an invitation always belong to the invitee
+			var invitation pollInvitation
+			if token := r.Form.Get("invite"); token != "" {
+				inv, ok, err := getInvitation(key, token)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				if !ok {
+					rw.WriteHeader(http.StatusForbidden)
+					tl := GetDefaultTranslation()
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.InvitationInvalid)), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				invitation = inv
+				name = inv.Name
+				answerID = inv.AnswerID
+			}
+
+			receipt := r.Form.Get("receipt")
+			if receipt != "" {
+				id, ok, err := resolveReceipt(key, receipt)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				if !ok {
+					rw.WriteHeader(http.StatusForbidden)
+					tl := GetDefaultTranslation()
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.ReceiptInvalid)), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				answerID = id
+			}
+
+			if p.Anonymous {
+				name = ""
+			}
+
+			mail := strings.TrimSpace(r.Form.Get("mail"))
+			if mail != "" && (!p.asksMail() || !validMailAddress(mail)) {
+				rw.WriteHeader(http.StatusBadRequest)
+				tl := GetDefaultTranslation()
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.InvalidEmailAddress)), tl, config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+
+			if !filterContent(&name, &comment) {
+				writeContentFiltered(rw)
+				return
+			}
+
+			if p.UniqueNames {
+				taken, err := nameTaken(key, name, answerID)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				if taken {
+					tl := GetDefaultTranslation()
+					rw.WriteHeader(http.StatusConflict)
+					text := fmt.Sprintf(`<p>%s</p><p><a href="/%s">%s</a></p>`, template.HTMLEscapeString(tl.NameAlreadyTaken), template.HTMLEscapeString(key), template.HTMLEscapeString(tl.BackToPoll))
+					t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+			}
+
+			unlockCapacity := func() {}
+			if p.hasCapacity() {
+				// Hold the lock until the answer is saved, so parallel answers can not exceed the capacity
+				unlockCapacity = lockCapacity(key)
+				defer unlockCapacity()
+				exceeded, err := p.checkCapacity(key, answerID, results)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				if len(exceeded) != 0 {
+					tl := GetDefaultTranslation()
+					rw.WriteHeader(http.StatusConflict)
+					text := fmt.Sprintf(`<p>%s</p><p><a href="/%s">%s</a></p>`, template.HTMLEscapeString(capacityExceededMessage(tl, exceeded)), template.HTMLEscapeString(key), template.HTMLEscapeString(tl.BackToPoll))
+					t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+			}
+
+			// Hold the lock until the answer is saved, so the poll can not be edited in between
+			pollStructureMutex.RLock()
+			defer pollStructureMutex.RUnlock()
+			unchanged, err := structureUnchanged(key, p.Revision())
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+			if !unchanged {
+				tl := GetDefaultTranslation()
+				rw.WriteHeader(http.StatusConflict)
+				text := fmt.Sprintf(`<p>%s</p><p><a href="/%s?answer=yes">%s</a></p>`, template.HTMLEscapeString(tl.PollChanged), template.HTMLEscapeString(key), template.HTMLEscapeString(tl.Participate))
+				t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+
+			changed := answerID != ""
 			if answerID == "" {
-				answerID, err = safe.SavePollResult(key, r.Form.Get("name"), r.Form.Get("comment"), results, change)
+				answerID, err = safe.SavePollResult(key, name, comment, results, change)
+				if errors.Is(err, registry.ErrPollNotAvailable) {
+					rw.WriteHeader(http.StatusGone)
+					tl := GetDefaultTranslation()
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollIsDeleted)), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
 				if err != nil {
 					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
+				if invitation.Token != "" {
+					err = safe.SetInvitationAnswer(key, invitation.Token, invitation.linked, answerID)
+					if errors.Is(err, registry.ErrInvitationChanged) {
+						// The invitation was used in parallel - only keep the first answer
+						err = safe.DeleteAnswer(key, answerID)
+						if err != nil {
+							log.Printf("Poll.HandleRequest (%s): can not delete duplicate invitation answer: %s", key, err.Error())
+						}
+						tl := GetDefaultTranslation()
+						rw.WriteHeader(http.StatusConflict)
+						text := fmt.Sprintf(`<p>%s</p><p><a href="/%s">%s</a></p>`, template.HTMLEscapeString(tl.InvitationAlreadyUsed), template.HTMLEscapeString(key), template.HTMLEscapeString(tl.BackToPoll))
+						t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+					if err != nil {
+						rw.WriteHeader(http.StatusInternalServerError)
+						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+				}
 			} else {
 				change, err = safe.GetChange(key, answerID)
 				if err != nil {
 					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
@@ -395,10 +1423,12 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 					textTemplate.Execute(rw, t)
 					return
 				}
+				// Invitation tokens and receipts authorise changing the answer they belong to
+				authorised := invitation.Token != "" || receipt != ""
+				found := authorised
 				cookies := r.Cookies()
-				found := false
 				for i := range cookies {
-					if cookies[i].Name == answerID {
+					if !authorised && cookies[i].Name == answerID {
 						if subtle.ConstantTimeCompare([]byte(change), []byte(cookies[i].Value)) == 0 {
 							if config.LogFailedLogin {
 								log.Printf("Failed authentication from %s", GetRealIP(r))
@@ -419,15 +1449,45 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 					return
 				}
 
-				err := safe.OverwritePollResult(key, answerID, r.Form.Get("name"), r.Form.Get("comment"), results, change)
+				err := safe.OverwritePollResult(key, answerID, name, comment, results, change)
+				if errors.Is(err, registry.ErrPollNotAvailable) {
+					rw.WriteHeader(http.StatusGone)
+					tl := GetDefaultTranslation()
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollIsDeleted)), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
 				if err != nil {
 					rw.WriteHeader(http.StatusInternalServerError)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+			}
+			// Parallel answers see the saved answer, so the capacity does not need to be locked any more
+			unlockCapacity()
+
+			err = safe.SaveConsent(key, answerID, time.Now(), p.consentVersion())
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+
+			// An empty field keeps a previously given address, as it is never shown again
+			if mail != "" {
+				err = safe.SaveAnswerMail(key, answerID, mail)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
 			}
 
+			hookAnswerSaved(key, answerID, changed)
+
 			// Set cookie for editing
 			cookie := http.Cookie{}
 			cookie.Name = answerID
@@ -439,6 +1499,11 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			cookie.Secure = !config.InsecureAllowCookiesOverHTTP
 			http.SetCookie(rw, &cookie)
 
+			if p.Anonymous {
+				writeReceipt(rw, key, receiptCode(answerID, change))
+				return
+			}
+
 			http.Redirect(rw, r, fmt.Sprintf("/%s", key), http.StatusSeeOther)
 			return
 		}
@@ -453,23 +1518,26 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 		err := r.ParseForm()
 		if err != nil {
 			rw.WriteHeader(http.StatusInternalServerError)
-			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 			textTemplate.Execute(rw, t)
 			return
 		}
+		if !checkCSRF(rw, r) {
+			return
+		}
 		// Test password first
 		if config.AuthenticationEnabled {
-			user, pw := r.Form.Get("user"), r.Form.Get("pw")
-			if len(user) == 0 || len(pw) == 0 {
-				rw.WriteHeader(http.StatusForbidden)
-				t := textTemplateStruct{"403 Forbidden", GetDefaultTranslation(), config.ServerPath}
+			user, correct, err := authenticateRequest(r)
+			if errors.Is(err, ErrLoginLocked) {
+				rw.Header().Set("Retry-After", strconv.Itoa(config.LoginLockoutMinutes*60))
+				rw.WriteHeader(http.StatusTooManyRequests)
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(GetDefaultTranslation().LoginLocked)), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
-			correct, err := authenticater.Authenticate(user, pw)
 			if err != nil {
 				rw.WriteHeader(http.StatusInternalServerError)
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
@@ -482,6 +1550,21 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 				textTemplate.Execute(rw, t)
 				return
 			}
+
+			exceeded, err := pollQuotaExceeded(user)
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+			if exceeded {
+				rw.WriteHeader(http.StatusForbidden)
+				tl := GetDefaultTranslation()
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(fmt.Sprintf(tl.PollQuotaExceeded, config.MaxPollsPerCreator))), tl, config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
 		}
 		// Test DSGVO first
 		if r.Form.Get("dsgvo") == "" {
@@ -491,96 +1574,93 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			return
 		}
 
+		if instanceFull(true) {
+			writeInstanceFull(rw)
+			return
+		}
+
 		p.AnswerOption = make([][]string, 0)
 		p.Questions = make([]string, 0)
-
-		switch r.Form.Get("type") {
-		case "normal":
-			p.Description = r.Form.Get("description")
-			// Questions
-			searchid := 0
-			searchuntil, err := strconv.Atoi(r.Form.Get("normalanswer"))
+		p.DisableComments = r.Form.Get("disablecomments") != ""
+		p.UniqueNames = r.Form.Get("uniquenames") != ""
+		p.Anonymous = r.Form.Get("anonymous") != ""
+		p.ShuffleOrder = r.Form.Get("shuffleorder") != ""
+		p.ShowPercentages = r.Form.Get("showpercentages") != ""
+		p.ResultVisibility = parseResultVisibility(r.Form.Get("resultvisibility"))
+		p.MultipleChoice = r.Form.Get("multiplechoice") != ""
+		p.AnswerLayout = parseAnswerLayout(r.Form.Get("answerlayout"))
+		p.ConsentText = strings.TrimSpace(r.Form.Get("consenttext"))
+		p.ConsentURL = strings.TrimSpace(r.Form.Get("consenturl"))
+		p.Deadline = time.Time{}
+		if d := r.Form.Get("deadline"); d != "" {
+			deadline, err := time.ParseInLocation("2006-01-02T15:04", d, time.Local)
 			if err != nil {
 				rw.WriteHeader(http.StatusBadRequest)
-				tl := GetDefaultTranslation()
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), tl, config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
-			budget := config.MaxNumberQuestions
-			if searchuntil > budget*2 { // Allow for a few blank fields here
+			p.Deadline = deadline
+		}
+		p.Expiry = time.Time{}
+		if e := r.Form.Get("expiry"); e != "" {
+			expiry, err := time.ParseInLocation("2006-01-02T15:04", e, time.Local)
+			if err != nil {
 				rw.WriteHeader(http.StatusBadRequest)
-				tl := GetDefaultTranslation()
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollToLargeError)), tl, config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
-			for {
-				searchid++
-				if searchid > searchuntil+1 {
-					break
-				}
-				name := r.Form.Get(fmt.Sprintf("normalanswer%d", searchid))
-				if name == "" {
-					continue
-				}
-				p.Questions = append(p.Questions, name)
-				budget--
-				if budget < 0 {
+			p.Expiry = expiry
+		}
+
+		var importAnswers []PollExportAnswer
+		switch r.Form.Get("type") {
+		case "normal":
+			p.Type = "normal"
+			p.Description = r.Form.Get("description")
+			// Questions
+			questions, err := parseFormList(r.Form, "normalanswer", config.MaxNumberQuestions, "normalanswer", "normalcapacity")
+			if err != nil {
+				writeFormListError(rw, err)
+				return
+			}
+			capacity := make([]int, 0, len(questions))
+			for _, q := range questions {
+				p.Questions = append(p.Questions, q.Values[0])
+				c, err := parseCapacity(q.Values[1])
+				if err != nil {
 					rw.WriteHeader(http.StatusBadRequest)
-					tl := GetDefaultTranslation()
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollToLargeError)), tl, config.ServerPath}
+					t := textTemplateStruct{"400 Bad Request", GetDefaultTranslation(), config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
+				capacity = append(capacity, c)
 			}
+			p.Capacity = capacity
+			if !p.hasCapacity() {
+				p.Capacity = nil
+			}
+			// Answers
 			// Answers
-			searchid = 0
-			searchuntil, err = strconv.Atoi(r.Form.Get("normalansweroption"))
+			options, err := parseFormList(r.Form, "normalansweroption", config.MaxNumberQuestions, "normalansweroption", "normalanswervalue", "normalanswercolour")
 			if err != nil {
-				rw.WriteHeader(http.StatusBadRequest)
-				tl := GetDefaultTranslation()
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), tl, config.ServerPath}
-				textTemplate.Execute(rw, t)
-				return
-			}
-			budget = config.MaxNumberQuestions
-			if searchuntil > budget*2 { // Allow for a few blank fields here
-				rw.WriteHeader(http.StatusBadRequest)
-				tl := GetDefaultTranslation()
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollToLargeError)), tl, config.ServerPath}
-				textTemplate.Execute(rw, t)
+				writeFormListError(rw, err)
 				return
 			}
-			for {
-				searchid++
-				if searchid > searchuntil+1 {
-					break
-				}
-				answer := r.Form.Get(fmt.Sprintf("normalansweroption%d", searchid))
-				if answer == "" {
-					continue
-				}
-				value := r.Form.Get(fmt.Sprintf("normalanswervalue%d", searchid))
+			for _, o := range options {
+				value := o.Values[1]
 				if value == "" {
 					value = "0.0"
 				} else if _, err := strconv.ParseFloat(value, 64); err != nil {
 					value = "0.0"
 				}
-				colour := r.Form.Get(fmt.Sprintf("normalanswercolour%d", searchid))
+				colour := o.Values[2]
 				if colour == "" {
 					colour = "#ffffff"
 				}
 
-				p.AnswerOption = append(p.AnswerOption, []string{answer, value, colour})
-				budget--
-				if budget < 0 {
-					rw.WriteHeader(http.StatusBadRequest)
-					tl := GetDefaultTranslation()
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollToLargeError)), tl, config.ServerPath}
-					textTemplate.Execute(rw, t)
-					return
-				}
+				p.AnswerOption = append(p.AnswerOption, []string{o.Values[0], value, colour})
 			}
 			if len(p.Questions) == 0 || len(p.AnswerOption) == 0 {
 				rw.WriteHeader(http.StatusBadRequest)
@@ -597,24 +1677,36 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			}
 			p.initialised = true
 		case "date":
+			p.Type = "date"
 			t := GetDefaultTranslation()
-			p.AnswerOption = [][]string{{t.DateYes, "1.0", "#243D00"}, {t.DateOnlyIfNeeded, "0.25", "#9A9A9A"}, {t.DateNo, "-1.0", "#E3C2D4"}, {t.DateCanNotSay, "0.0", "#F7F7F7"}}
-			var dateRead = "2006-01-02"
-			var timeWrite = "02.01.2006 15:04"
-			var timeWriteNoTime = "02.01.2006"
+			ifNeeded := "0.25"
+			if w := r.Form.Get("ifneededweight"); w != "" {
+				f, err := strconv.ParseFloat(w, 64)
+				if err != nil || math.IsNaN(f) || f < 0 || f > 1 {
+					rw.WriteHeader(http.StatusBadRequest)
+					t := textTemplateStruct{"400 Bad Request", GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				ifNeeded = strconv.FormatFloat(f, 'f', -1, 64)
+			}
+			p.AnswerOption = [][]string{{t.DateYes, "1.0", "#243D00"}, {t.DateOnlyIfNeeded, ifNeeded, "#9A9A9A"}, {t.DateNo, "-1.0", "#E3C2D4"}, {t.DateCanNotSay, "0.0", "#F7F7F7"}}
+			var dateRead = config.DateInputFormat
+			var timeWrite = config.DateTimeDisplayFormat
+			var timeWriteNoTime = config.DateDisplayFormat
 
 			p.Description = r.Form.Get("description")
 			start, err := time.Parse(dateRead, r.Form.Get("start"))
 			if err != nil {
 				rw.WriteHeader(http.StatusInternalServerError)
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
 			end, err := time.Parse(dateRead, r.Form.Get("end"))
 			if err != nil {
 				rw.WriteHeader(http.StatusInternalServerError)
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
@@ -643,34 +1735,14 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			}
 			times := make([][]int, 0)
 			test := make(map[string]bool)
-			searchid := 0
-			searchuntil, err := strconv.Atoi(r.Form.Get("timeanswer"))
+			entries, err := parseFormList(r.Form, "timeanswer", config.MaxNumberQuestions, "time", "timeday")
 			if err != nil {
-				rw.WriteHeader(http.StatusBadRequest)
-				tl := GetDefaultTranslation()
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), tl, config.ServerPath}
-				textTemplate.Execute(rw, t)
-				return
-			}
-			budget := config.MaxNumberQuestions
-			if searchuntil > budget*2 { // Allow for a few blank fields here
-				rw.WriteHeader(http.StatusBadRequest)
-				tl := GetDefaultTranslation()
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollToLargeError)), tl, config.ServerPath}
-				textTemplate.Execute(rw, t)
+				writeFormListError(rw, err)
 				return
 			}
-			for {
-				searchid++
-				if searchid > searchuntil+1 {
-					break
-				}
-				name := r.Form.Get(fmt.Sprintf("time%d", searchid))
-				if name == "" {
-					continue
-				}
+			for _, e := range entries {
 				tn := make([]int, 2)
-				split := strings.Split(name, ":")
+				split := strings.Split(e.Values[0], ":")
 				if len(split) != 2 {
 					break
 				}
@@ -703,33 +1775,81 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 					return
 				}
 
+				// Times can be restricted to a single weekday, -1 means all selected weekdays
+				day := -1
+				if d := e.Values[1]; d != "" {
+					weekday, ok := weekdayNames[d]
+					if !ok {
+						rw.WriteHeader(http.StatusBadRequest)
+						t := textTemplateStruct{"400 Bad Request", GetDefaultTranslation(), config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+					day = int(weekday)
+				}
+
 				// Ensure time format is identical
-				timeTest := fmt.Sprintf("%d:%d", tn[0], tn[1])
+				timeTest := fmt.Sprintf("%d:%d:%d", tn[0], tn[1], day)
 				if test[timeTest] {
 					continue
 				}
 				test[timeTest] = true
 
-				times = append(times, tn)
+				times = append(times, append(tn, day))
 			}
 
 			sort.Sort(timesSort(times))
 
+			followUpDays := 0
+			if d := r.Form.Get("followupdays"); d != "" {
+				followUpDays, err = strconv.Atoi(d)
+				if err != nil || followUpDays < 1 || followUpDays > followUpMaxDays || p.Deadline.IsZero() {
+					rw.WriteHeader(http.StatusBadRequest)
+					tl := GetDefaultTranslation()
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(fmt.Sprintf(tl.FollowUpInvalid, followUpMaxDays))), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+			}
+
 			// Generate questions
-			budget = config.MaxNumberQuestions
+			budget := config.MaxNumberQuestions
+			lastWeek := -1
 			for start.Before(end) {
 				process := start
 				start = start.AddDate(0, 0, 1)
 				if !weekdayMap[process.Weekday()] {
 					continue
 				}
+				questions := make([]string, 0, len(times)+1)
 				if r.Form.Get("notime") != "" {
-					p.Questions = append(p.Questions, FormatTimeDisplay(process, timeWriteNoTime))
+					questions = append(questions, FormatTimeDisplay(process, timeWriteNoTime))
+					p.Dates = append(p.Dates, PollDate{Time: process, NoTime: true})
 				}
 
+				last := -1
 				for i := range times {
-					p.Questions = append(p.Questions, FormatTimeDisplay(time.Date(process.Year(), process.Month(), process.Day(), times[i][0], times[i][1], 0, 0, process.Location()), timeWrite))
+					if times[i][2] != -1 && times[i][2] != int(process.Weekday()) {
+						continue
+					}
+					// Same time might be given for all weekdays and a specific weekday
+					if times[i][0]*60+times[i][1] == last {
+						continue
+					}
+					last = times[i][0]*60 + times[i][1]
+					qt := time.Date(process.Year(), process.Month(), process.Day(), times[i][0], times[i][1], 0, 0, process.Location())
+					questions = append(questions, FormatTimeDisplay(qt, timeWrite))
+					p.Dates = append(p.Dates, PollDate{Time: qt})
+				}
+				if len(questions) == 0 {
+					continue
+				}
+
+				if _, week := process.ISOWeek(); r.Form.Get("groupweeks") != "" && week != lastWeek {
+					p.Sections = append(p.Sections, PollSection{Title: fmt.Sprintf("%s %d", t.CalendarWeek, week), Start: len(p.Questions)})
+					lastWeek = week
 				}
+				p.Questions = append(p.Questions, questions...)
 				budget--
 				if budget < 0 {
 					rw.WriteHeader(http.StatusBadRequest)
@@ -739,6 +1859,27 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 					return
 				}
 			}
+			if len(p.Sections) == 1 {
+				// A single week does not need grouping
+				p.Sections = nil
+			}
+			if followUpDays > 0 {
+				p.FollowUp = &PollFollowUp{Days: followUpDays}
+			}
+			// The capacity of date polls applies to every date
+			capacity, err := parseCapacity(r.Form.Get("capacity"))
+			if err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				t := textTemplateStruct{"400 Bad Request", GetDefaultTranslation(), config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+			if capacity > 0 {
+				p.Capacity = make([]int, len(p.Questions))
+				for i := range p.Capacity {
+					p.Capacity[i] = capacity
+				}
+			}
 			if len(p.Questions) == 0 {
 				rw.WriteHeader(http.StatusBadRequest)
 				tl := GetDefaultTranslation()
@@ -754,43 +1895,18 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			}
 			p.initialised = true
 		case "opinion":
+			p.Type = "opinion"
 			tl := GetDefaultTranslation()
 			p.Description = r.Form.Get("description")
 			// Questions
-			searchid := 0
-			searchuntil, err := strconv.Atoi(r.Form.Get("opinionitem"))
+			// Questions
+			items, err := parseFormList(r.Form, "opinionitem", config.MaxNumberQuestions, "opinionitem")
 			if err != nil {
-				rw.WriteHeader(http.StatusBadRequest)
-				tl := GetDefaultTranslation()
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), tl, config.ServerPath}
-				textTemplate.Execute(rw, t)
-				return
-			}
-			budget := config.MaxNumberQuestions
-			if searchuntil > budget*2 { // Allow for a few blank fields here
-				rw.WriteHeader(http.StatusBadRequest)
-				tl := GetDefaultTranslation()
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollToLargeError)), tl, config.ServerPath}
-				textTemplate.Execute(rw, t)
+				writeFormListError(rw, err)
 				return
 			}
-			for {
-				searchid++
-				if searchid > searchuntil+1 {
-					break
-				}
-				name := r.Form.Get(fmt.Sprintf("opinionitem%d", searchid))
-				if name == "" {
-					continue
-				}
-				p.Questions = append(p.Questions, name)
-				budget--
-				if budget < 0 {
-					rw.WriteHeader(http.StatusBadRequest)
-					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollToLargeError)), tl, config.ServerPath}
-					textTemplate.Execute(rw, t)
-					return
-				}
+			for _, item := range items {
+				p.Questions = append(p.Questions, item.Values[0])
 			}
 			if len(p.Questions) == 0 {
 				rw.WriteHeader(http.StatusBadRequest)
@@ -817,6 +1933,11 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 				textTemplate.Execute(rw, t)
 				return
 			}
+			// A full export contains the answers next to the configuration
+			full, isFull := parseFullExport([]byte(c))
+			if isFull {
+				c = string(full.Poll)
+			}
 			new, err := LoadPoll([]byte(c))
 			if err != nil {
 				rw.WriteHeader(http.StatusBadRequest)
@@ -830,9 +1951,41 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 				textTemplate.Execute(rw, t)
 				return
 			}
+			if isFull {
+				err = new.verifyExportAnswers(full.Answers)
+				if err != nil {
+					rw.WriteHeader(http.StatusBadRequest)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				importAnswers = full.Answers
+			}
+			p.Type = new.Type
 			p.AnswerOption = new.AnswerOption
 			p.Questions = new.Questions
 			p.Description = new.Description
+			p.DisableComments = new.DisableComments
+			p.UniqueNames = new.UniqueNames
+			p.Anonymous = new.Anonymous
+			p.ShuffleOrder = new.ShuffleOrder
+			p.ShowPercentages = new.ShowPercentages
+			p.ResultVisibility = parseResultVisibility(new.ResultVisibility)
+			p.MultipleChoice = new.MultipleChoice
+			p.AnswerLayout = parseAnswerLayout(new.AnswerLayout)
+			p.Capacity = new.Capacity
+			p.Sections = new.Sections
+			p.Deadline = new.Deadline
+			p.Expiry = new.Expiry
+			p.ConsentText = new.ConsentText
+			p.ConsentURL = new.ConsentURL
+			p.Dates = new.Dates
+			p.FinalDate = 0
+			p.InvitationsSent = nil
+			p.FollowUp = new.FollowUp
+			if p.FollowUp != nil {
+				p.FollowUp.Created = ""
+			}
 			p.Deleted = false
 			p.initialised = true
 		default:
@@ -841,27 +1994,26 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			textTemplate.Execute(rw, t)
 			return
 		}
-		b, err := p.ExportPoll()
-		if err != nil {
-			rw.WriteHeader(http.StatusInternalServerError)
-			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
-			textTemplate.Execute(rw, t)
+		if !p.filterPollTexts() {
+			writeContentFiltered(rw)
 			return
 		}
-		err = safe.SavePollConfig(key, b)
+		creator := ""
+		if config.AuthenticationEnabled {
+			creator = requestUser(r) // is already authenticated
+		}
+		err = p.saveNewPoll(key, creator)
 		if err != nil {
 			rw.WriteHeader(http.StatusInternalServerError)
-			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 			textTemplate.Execute(rw, t)
 			return
 		}
-		creator := ""
-		if config.AuthenticationEnabled {
-			creator = r.Form.Get("user") // is already authenticated
-			err := safe.SavePollCreator(key, creator)
+		if importAnswers != nil {
+			err = p.importExportAnswers(key, importAnswers)
 			if err != nil {
 				rw.WriteHeader(http.StatusInternalServerError)
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
@@ -886,24 +2038,172 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			err := r.ParseForm()
 			if err != nil {
 				rw.WriteHeader(http.StatusInternalServerError)
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
+			if config.EnablePresence && r.Form.Get("presence") == "true" {
+				HandlePresence(rw, r, key, r.Form.Get("filling") == "true")
+				return
+			}
+
+			if config.EnableLiveUpdates && r.Form.Get("live") == "true" {
+				if p.resultsHidden() {
+					rw.WriteHeader(http.StatusForbidden)
+					return
+				}
+				HandleLiveUpdates(rw, r, key)
+				return
+			}
+
+			if r.Form.Get("stats") == "true" {
+				// Statistics requested
+				if p.resultsHidden() {
+					rw.WriteHeader(http.StatusForbidden)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(GetDefaultTranslation().ResultsHiddenUntilClosed)), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				stats, err := p.GetStatistics(key)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				b, err := json.Marshal(stats)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				rw.Header().Set("Content-Type", "application/json")
+				rw.Write(b)
+				return
+			}
+
+			if compare := r.Form.Get("compare"); compare != "" {
+				// Comparison with another poll requested
+				p.handleCompare(rw, r, key, compare)
+				return
+			}
+
+			if r.Form.Get("ics") == "true" {
+				// Calendar export requested
+				p.handleCalendarExport(rw, key)
+				return
+			}
+
+			if answerID := r.Form.Get("gdpr"); answerID != "" {
+				// Personal data of an answer requested
+				ok, err := ownsAnswer(r, key, answerID)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				if !ok {
+					rw.WriteHeader(http.StatusForbidden)
+					t := textTemplateStruct{"403 Forbidden", GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				p.handleGDPRExport(rw, key, []string{answerID})
+				return
+			}
+
 			a := r.Form.Get("answer")
 			if a != "" {
 				// Answer requested
+				if p.Closed() {
+					rw.WriteHeader(http.StatusForbidden)
+					tl := GetDefaultTranslation()
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(p.closedMessage(tl))), tl, config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				recordAnswerFormOpen(r, key)
 				td := answerTemplateStruct{
-					Key:          sanitiseKey(key),
-					EditID:       r.Form.Get("answerID"),
-					AnswerOption: p.AnswerOption,
-					Questions:    p.Questions,
-					Description:  Format([]byte(p.Description)),
-					Name:         "",
-					Comment:      "",
-					Answers:      nil,
-					Translation:  GetDefaultTranslation(),
-					ServerPath:   config.ServerPath,
+					Key:            sanitiseKey(key),
+					EditID:         r.Form.Get("answerID"),
+					AnswerOption:   p.AnswerOption,
+					Questions:      p.Questions,
+					Order:          p.questionOrder(rw, r, key),
+					Description:    Format([]byte(p.Description)),
+					Name:           "",
+					Comment:        "",
+					ShowComments:   !p.DisableComments,
+					Anonymous:      p.Anonymous,
+					AskMail:        p.asksMail(),
+					Participant:    participantAuthenticater != nil,
+					Revision:       p.Revision(),
+					Answers:        nil,
+					MultipleChoice: p.MultipleChoice,
+					Presence:       config.EnablePresence,
+					Translation:    GetDefaultTranslation(),
+					ServerPath:     config.ServerPath,
+				}
+
+				td.ConsentText, td.ConsentURL = p.Consent()
+				td.Compact = p.answerLayout(rw, r) == answerLayoutCompact
+				td.Layout = parseAnswerLayout(r.Form.Get("layout"))
+				td.SwitchLayout = answerLayoutURL(r, td.Compact)
+				td.WhiteFont = make([]bool, len(p.AnswerOption))
+				for i := range p.AnswerOption {
+					col, err := colors.ParseHEX(p.AnswerOption[i][2])
+					if err == nil {
+						td.WhiteFont[i] = col.IsDark()
+					}
+				}
+				if rev := r.Form.Get("revision"); rev != "" {
+					// Keep the revision of the first page so that answers carried from other pages are checked as well
+					td.Revision = rev
+				}
+
+				if !p.ShuffleOrder {
+					// Sections are only meaningful if the questions are displayed in order
+					td.SectionStarts = p.sectionStarts()
+				}
+
+				if token := r.Form.Get("invite"); token != "" {
+					inv, ok, err := getInvitation(key, token)
+					if err != nil {
+						rw.WriteHeader(http.StatusInternalServerError)
+						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+					if !ok {
+						rw.WriteHeader(http.StatusForbidden)
+						tl := GetDefaultTranslation()
+						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.InvitationInvalid)), tl, config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+					td.Invite = inv.Token
+					td.InviteName = inv.Name
+					td.EditID = inv.AnswerID
+				}
+
+				if receipt := r.Form.Get("receipt"); receipt != "" {
+					id, ok, err := resolveReceipt(key, receipt)
+					if err != nil {
+						rw.WriteHeader(http.StatusInternalServerError)
+						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+					if !ok {
+						rw.WriteHeader(http.StatusForbidden)
+						tl := GetDefaultTranslation()
+						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.ReceiptInvalid)), tl, config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+					td.Receipt = strings.TrimSpace(receipt)
+					td.EditID = id
 				}
 
 				if td.EditID != "" {
@@ -911,7 +2211,7 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 					if err != nil {
 						if err != nil {
 							rw.WriteHeader(http.StatusInternalServerError)
-							t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+							t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 							textTemplate.Execute(rw, t)
 							return
 						}
@@ -921,11 +2221,55 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 					td.Comment = c
 					td.Answers = r
 				}
+				if td.Invite != "" {
+					td.Name = td.InviteName
+				}
+				if p.Anonymous {
+					td.Name = ""
+				}
 
 				for len(td.Answers) < len(p.Questions) {
 					td.Answers = append(td.Answers, -1)
 				}
 
+				if prefill := r.Form.Get("prefill"); prefill != "" && td.EditID == "" {
+					// Suggestions never replace saved answers
+					answers, ok := p.parsePrefill(prefill)
+					if !ok {
+						rw.WriteHeader(http.StatusBadRequest)
+						tl := GetDefaultTranslation()
+						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PrefillInvalid)), tl, config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+					td.Answers = answers
+					td.Prefill = prefill
+				}
+
+				td.LastPage = true
+				if config.AnswerPageSize > 0 && len(td.Order) > config.AnswerPageSize {
+					td.paginate(*p, r.Form, config.AnswerPageSize)
+				}
+				td.Selected = p.selectionMatrix(td.Answers)
+
+				td.Full = make([]bool, len(p.Questions))
+				td.Positive = make([]bool, len(p.AnswerOption))
+				if p.hasCapacity() {
+					r, _, _, ids, err := safe.GetPollResult(key)
+					if err != nil {
+						rw.WriteHeader(http.StatusInternalServerError)
+						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+					// The own answer is not counted, so it can keep its picks
+					td.Full = p.fullQuestions(r, ids, td.EditID)
+					for i, v := range p.optionValues() {
+						td.Positive[i] = v > 0
+					}
+				}
+
+				td.CSRF = csrfToken(rw, r)
 				err = answerTemplate.Execute(rw, td)
 				if err != nil {
 					log.Printf("Poll.HandleRequest.answer: %s", err.Error())
@@ -934,12 +2278,33 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			}
 
 			// Poll requested
+			recordPollView(r, key)
 			cookies := r.Cookies()
+			transposed := r.Form.Get("view") == "transposed"
+			resultPage := r.Form.Get("resultpage")
+			sortBy, sortDesc := parseResultSort(r.Form.Get("sort"), r.Form.Get("order"))
+
+			csrf := csrfToken(rw, r) // r is shadowed by the results below
+			numbers := requestNumberFormat(r)
+			sessionUser := sessionUser(r)
+			hasPassword := managementRequiresPassword(r)
+			rw.Header().Add("Vary", "Accept-Language")
+			cacheKey, cacheable := pageCacheKey(r, key, numbers)
+			var lastActivity time.Time
+			if cacheable {
+				var err error
+				lastActivity, err = safe.GetLastActivity(key)
+				cacheable = err == nil
+				if page, ok := getCachedPage(cacheKey, lastActivity, p.Closed(), csrf); cacheable && ok {
+					rw.Write(page)
+					return
+				}
+			}
 
 			r, n, c, aid, err := safe.GetPollResult(key)
 			if err != nil {
 				rw.WriteHeader(http.StatusInternalServerError)
-				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
@@ -947,24 +2312,21 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			// Verify data
 			if len(r) != len(n) {
 				rw.WriteHeader(http.StatusInternalServerError)
-				log.Printf("Poll.HandleRequest (%s):  len(r) != len(n)", key)
-				t := textTemplateStruct{"len(r) != len(n)", GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(fmt.Errorf("Poll.HandleRequest (%s): len(r) != len(n)", key)))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
 
 			if len(r) != len(c) {
 				rw.WriteHeader(http.StatusInternalServerError)
-				log.Printf("Poll.HandleRequest (%s):  len(r) != len(C)", key)
-				t := textTemplateStruct{"len(r) != len(C)", GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(fmt.Errorf("Poll.HandleRequest (%s): len(r) != len(C)", key)))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
 
 			if len(r) != len(aid) {
 				rw.WriteHeader(http.StatusInternalServerError)
-				log.Printf("Poll.HandleRequest (%s):  len(r) != len(aid)", key)
-				t := textTemplateStruct{"len(r) != len(aid)", GetDefaultTranslation(), config.ServerPath}
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(fmt.Errorf("Poll.HandleRequest (%s): len(r) != len(aid)", key)))), GetDefaultTranslation(), config.ServerPath}
 				textTemplate.Execute(rw, t)
 				return
 			}
@@ -972,13 +2334,13 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			for i := range r {
 				if len(r[i]) != len(p.Questions) {
 					rw.WriteHeader(http.StatusInternalServerError)
-					log.Printf("Poll.HandleRequest (%s):  len(r[%d]) != len(p.Questions)", key, i)
-					t := textTemplateStruct{"len(r[i]) != len(p.Questions)", GetDefaultTranslation(), config.ServerPath}
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(fmt.Errorf("Poll.HandleRequest (%s): len(r[%d]) != len(p.Questions)", key, i)))), GetDefaultTranslation(), config.ServerPath}
 					textTemplate.Execute(rw, t)
 					return
 				}
 			}
 
+			consentText, consentURL := p.Consent()
 			td := pollTemplateStruct{
 				Key:             sanitiseKey(key),
 				Questions:       p.Questions,
@@ -988,38 +2350,118 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 				Comments:        c,
 				IDs:             aid,
 				CanEdit:         make([]bool, len(n)),
+				Endorsements:    make([]int, len(n)),
+				Endorsed:        make([]bool, len(n)),
 				Points:          make([]float64, len(p.Questions)),
 				BestValue:       math.Inf(-1),
+				Numbers:         numbers,
+				ShowComments:    !p.DisableComments,
+				SectionStarts:   p.sectionStarts(),
+				SectionHeaders:  p.sectionHeaders(),
+				ConsentText:     consentText,
+				ConsentURL:      consentURL,
+				Transposed:      transposed,
+				Sort:            sortBy,
+				SortDesc:        sortDesc,
+				Closed:          p.Closed(),
+				Locked:          p.Locked,
+				CreatorClosed:   p.CreatorClosed,
+				Expired:         p.Expired(),
+				Anonymous:       p.Anonymous,
+				FinalDate:       p.finalDate(),
+				FinalDateIndex:  p.FinalDate - 1,
+				CanChooseFinal:  p.Dates != nil,
+				CalendarInvites: p.asksMail(),
 				Description:     Format([]byte(p.Description)),
-				HasPassword:     config.AuthenticationEnabled,
+				HasPassword:     hasPassword,
+				CoOrganizers:    config.AuthenticationEnabled,
+				LoginURL:        pollLoginURL(key),
+				SessionUser:     sessionUser,
+				Presence:        config.EnablePresence,
+				LiveUpdates:     config.EnableLiveUpdates && !p.resultsHidden(),
+				Indexable:       isSitemapPoll(key),
 				Translation:     GetDefaultTranslation(),
 				ServerPath:      config.ServerPath,
 			}
 
+			if !p.Deadline.IsZero() {
+				td.Deadline = FormatTimeDisplay(p.Deadline, config.DateTimeDisplayFormat)
+				td.DeadlineUnix = p.Deadline.Unix()
+			}
+			if !p.Expiry.IsZero() {
+				td.Expiry = FormatTimeDisplay(p.Expiry, config.DateTimeDisplayFormat)
+			}
+			if p.FollowUp != nil {
+				td.FollowUpDays = p.FollowUp.Days
+				td.FollowUp = p.FollowUp.Created
+			}
+
+			endorsements, err := safe.GetEndorsements(key)
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+
 			knownIDs := make(map[string]bool)
-			for i := 0; i < len(cookies) && i < len(r)*2; i++ {
+			endorsedIDs := make(map[string]bool)
+			for i := 0; i < len(cookies) && i < len(r)*4; i++ {
+				if strings.HasPrefix(cookies[i].Name, endorseCookiePrefix) {
+					endorsedIDs[strings.TrimPrefix(cookies[i].Name, endorseCookiePrefix)] = true
+					continue
+				}
 				knownIDs[cookies[i].Name] = true
 			}
 
+			values := make([][]float64, len(p.Questions))
+			colours := p.answerColours()
+			optionValues := make([]float64, len(p.AnswerOption))
+			for o := range p.AnswerOption {
+				f, err := strconv.ParseFloat(p.AnswerOption[o][1], 64)
+				if err != nil {
+					f = 0.0
+					log.Printf("Poll.HandleRequest (%s): strconv.ParseFloat(p.AnswerOption[%d][1], 64) %s", key, o, err.Error())
+				}
+				optionValues[o] = f
+			}
+			// With 'PrecomputeAggregates', the totals and percentages are taken from the aggregate if it is up to date
+			var aggregate PollStatistics
+			useAggregate := false
+			if config.PrecomputeAggregates && !p.resultsHidden() {
+				aggregate, useAggregate = p.loadAggregate(key)
+				if !useAggregate {
+					markAggregateDirty(key)
+				}
+				// Answers might have changed between reading the results and the aggregate
+				useAggregate = useAggregate && aggregate.Participants == len(r) && len(aggregate.Points) == len(p.Questions)
+			}
+
+			score := make([]float64, len(r))
 			for i := range r {
 				answer := make([][]string, len(p.Questions))
 				whitefont := make([]bool, len(p.Questions))
 				for a := range r[i] {
-					if r[i][a] < len(p.AnswerOption) {
-						answer[a] = []string{p.AnswerOption[r[i][a]][0], p.AnswerOption[r[i][a]][2]}
-						f, err := strconv.ParseFloat(p.AnswerOption[r[i][a]][1], 64)
-						if err != nil {
-							f = 0.0
-							log.Printf("Poll.HandleRequest (%s): strconv.ParseFloat(p.AnswerOption[r[%d][%d]][1], 64) %s", key, i, a, err.Error())
+					if p.validAnswer(r[i][a]) {
+						// Cells of multiple choice answers are coloured by the first selected answer option
+						colour := "#ffffff"
+						if selected := p.selectedOptions(r[i][a]); len(selected) != 0 {
+							colour = colours[selected[0]]
+						}
+						answer[a] = []string{p.answerText(r[i][a]), colour}
+						f := p.answerPoints(r[i][a], optionValues)
+						if !useAggregate {
+							td.Points[a] += f
 						}
-						td.Points[a] += f
-						col, err := colors.ParseHEX(p.AnswerOption[r[i][a]][2])
+						score[i] += f
+						values[a] = append(values[a], f)
+						col, err := colors.ParseHEX(colour)
 						if err == nil {
 							whitefont[a] = col.IsDark()
 						}
 					} else {
 						// Something is wrong
-						log.Printf("Poll.HandleRequest (%s):  r[%d][%d] < len(p.AnswerOption)", key, i, a)
+						log.Printf("Poll.HandleRequest (%s):  r[%d][%d] is not a valid answer", key, i, a)
 						answer[a] = []string{"error", "#ffffff"}
 					}
 				}
@@ -1029,12 +2471,146 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 				if knownIDs[aid[i]] {
 					td.CanEdit[i] = true
 				}
+				td.Endorsements[i] = endorsements[aid[i]]
+				td.Endorsed[i] = endorsedIDs[aid[i]]
 			}
 
+			if useAggregate {
+				copy(td.Points, aggregate.Points)
+			}
 			for i := range td.Points {
 				td.BestValue = math.Max(td.BestValue, td.Points[i])
 			}
 
+			// Hidden answers are never shown, so they do not count towards the render budget
+			td.Summarised = !p.answersHidden() && renderBudgetExceeded(len(r), len(p.Questions))
+
+			if (p.ShowPercentages || td.Summarised) && len(r) != 0 {
+				td.AnswerOptions = make([]string, len(p.AnswerOption))
+				td.Percentages = make([][]float64, len(p.AnswerOption))
+				for o := range p.AnswerOption {
+					td.AnswerOptions[o] = p.AnswerOption[o][0]
+					td.Percentages[o] = make([]float64, len(p.Questions))
+				}
+				if useAggregate {
+					for q := range aggregate.Counts {
+						for o := range aggregate.Counts[q] {
+							td.Percentages[o][q] = float64(aggregate.Counts[q][o])
+						}
+					}
+				} else {
+					for i := range r {
+						for a := range r[i] {
+							if a >= len(p.Questions) {
+								continue
+							}
+							// In multiple choice polls, the percentages of a question can add up to more than 100 %
+							for _, o := range p.selectedOptions(r[i][a]) {
+								td.Percentages[o][a]++
+							}
+						}
+					}
+				}
+				for o := range td.Percentages {
+					for q := range td.Percentages[o] {
+						td.Percentages[o][q] = td.Percentages[o][q] * 100 / float64(len(r))
+					}
+				}
+			}
+
+			if p.Type == "date" {
+				td.Suggestions = p.suggestDates(r)
+			}
+
+			if p.hasCapacity() {
+				td.Full = p.fullQuestions(r, aid, "")
+				used := p.capacityUsage(r, aid, "")
+				td.Capacity = make([]string, len(p.Questions))
+				for q := range td.Capacity {
+					if p.Capacity[q] > 0 {
+						td.Capacity[q] = fmt.Sprintf("%d / %d", used[q], p.Capacity[q])
+					}
+				}
+			}
+
+			if sortBy != "" && !p.answersHidden() {
+				var created map[string]time.Time
+				if sortBy == resultSortTime {
+					created, _, err = safe.GetAnswerTimes(key)
+					if err != nil {
+						rw.WriteHeader(http.StatusInternalServerError)
+						t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+						textTemplate.Execute(rw, t)
+						return
+					}
+				}
+				td.sortAnswers(resultOrder(sortBy, sortDesc, n, aid, created, score))
+			}
+
+			if td.Summarised {
+				td.summarise(resultPage, len(p.Questions))
+			}
+
+			if p.Type == "opinion" && len(r) != 0 {
+				td.Means = make([]float64, len(p.Questions))
+				td.Medians = make([]float64, len(p.Questions))
+				for i := range values {
+					td.Means[i], td.Medians[i] = meanMedian(values[i])
+				}
+			}
+
+			if p.answersHidden() {
+				// Aggregated results above cover all answers
+				td.hideAnswers(p.resultsHidden())
+			}
+
+			if !td.ResultsHidden {
+				stats := aggregate
+				var err error
+				if !useAggregate {
+					stats, err = p.GetStatistics(key)
+				}
+				if err == nil {
+					td.StructuredData, err = p.structuredData(key, stats, td.Translation.Language)
+				}
+				if err != nil {
+					// Structured data is optional, the poll can be shown without it
+					log.Printf("Poll.HandleRequest (%s): can not create structured data: %s", key, err.Error())
+				}
+			}
+
+			if config.EnableDiscussion {
+				dn, dt, dtimes, did, err := safe.GetDiscussion(key)
+				if err != nil {
+					rw.WriteHeader(http.StatusInternalServerError)
+					t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+					textTemplate.Execute(rw, t)
+					return
+				}
+				td.Discussion = true
+				td.DiscussionNames = dn
+				td.DiscussionTexts = dt
+				td.DiscussionIDs = did
+				td.DiscussionTimes = make([]string, len(dtimes))
+				for i := range dtimes {
+					td.DiscussionTimes[i] = dtimes[i].Format("2006-01-02 15:04")
+				}
+			}
+
+			if cacheable {
+				td.CSRF = string(pageCacheCSRFPlaceholder)
+				var buf bytes.Buffer
+				err = pollTemplate.Execute(&buf, td)
+				if err != nil {
+					log.Printf("Poll.HandleRequest.poll: %s", err.Error())
+					return
+				}
+				putCachedPage(cacheKey, buf.Bytes(), lastActivity, p.Closed())
+				rw.Write(bytes.ReplaceAll(buf.Bytes(), pageCacheCSRFPlaceholder, []byte(csrf)))
+				return
+			}
+
+			td.CSRF = csrf
 			err = pollTemplate.Execute(rw, td)
 			if err != nil {
 				log.Printf("Poll.HandleRequest.poll: %s", err.Error())
@@ -1042,9 +2618,22 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 			return
 		}
 		// This is a new poll
+		rw.Header().Set("X-Robots-Tag", "noindex")
+		if config.DisableImplicitCreation && r.URL.Query().Get("create") != "true" {
+			tl := GetDefaultTranslation()
+			rw.WriteHeader(http.StatusNotFound)
+			text := fmt.Sprintf(`<p>%s</p><p><a href="?create=true">%s</a></p>`, template.HTMLEscapeString(tl.PollNotFound), template.HTMLEscapeString(tl.CreatePollHere))
+			t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
 		td := newTemplateStruct{
 			Key:         sanitiseKey(key),
-			HasPassword: config.AuthenticationEnabled,
+			HasPassword: config.AuthenticationEnabled && !oidcEnabled() && sessionUser(r) == "",
+			LoginURL:    pollLoginURL(key),
+			SessionUser: sessionUser(r),
+			Uploads:     config.UploadPath != "",
+			CSRF:        csrfToken(rw, r),
 			Translation: GetDefaultTranslation(),
 			ServerPath:  config.ServerPath,
 		}
@@ -1056,6 +2645,39 @@ func (p *Poll) HandleRequest(rw http.ResponseWriter, r *http.Request, key string
 	}
 }
 
+// weekdayNames maps the form names of weekdays to the weekday.
+var weekdayNames = map[string]time.Weekday{
+	"mo": time.Monday,
+	"tu": time.Tuesday,
+	"we": time.Wednesday,
+	"th": time.Thursday,
+	"fr": time.Friday,
+	"sa": time.Saturday,
+	"su": time.Sunday,
+}
+
+// meanMedian returns the mean and median of the values. Both are 0 if no values are given.
+func meanMedian(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for i := range sorted {
+		sum += sorted[i]
+	}
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	return sum / float64(len(sorted)), median
+}
+
 type timesSort [][]int
 
 func (t timesSort) Len() int {
@@ -0,0 +1,503 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// Creators can edit the description, questions and answer options of an existing poll ('edit=true').
+// Questions and answer options keep their identity when renamed or removed, so existing answers are remapped to the new structure.
+// Answers which can not be remapped (an added question or a removed answer option in single choice polls) are deleted, which must be confirmed first.
+// Questions of date polls are derived from the dates, so they can only be removed.
+// The revision of the poll changes with its structure, so answer forms opened before the edit are rejected.
+
+// pollStructureMutex serialises editing polls with saving answers, so no answer is saved for the old structure while answers are remapped.
+// Answers hold it for reading and check with structureUnchanged that the poll was not edited since it was loaded.
+var pollStructureMutex sync.RWMutex
+
+// editNewRows is the number of empty rows for new questions and answer options in the edit form.
+const editNewRows = 3
+
+type editQuestion struct {
+	Row    int // number of the row in the form starting at 1
+	Old    int // index before editing, -1 for new questions
+	Text   string
+	Remove bool
+}
+
+type editOption struct {
+	Row    int // number of the row in the form starting at 1
+	Old    int // index before editing, -1 for new answer options
+	Text   string
+	Value  string
+	Colour string
+	Remove bool
+}
+
+type editTemplateStruct struct {
+	Key            string
+	Description    string
+	Questions      []editQuestion
+	Options        []editOption
+	FixedQuestions bool // whether questions can only be removed
+	Answers        int
+	Invalidated    int // answers which would be deleted, the edit must be confirmed if set
+	HasPassword    bool
+	User           string
+	CSRF           string
+	Translation    Translation
+}
+
+var editTemplate = template.Must(template.New("edit").Parse(`
+<h1>{{.Translation.EditPoll}}: <a href="/{{.Key}}">{{.Key}}</a></h1>
+<p>{{.Translation.EditPollDescription}}</p>
+{{if .Invalidated}}<p><strong>{{printf .Translation.EditPollInvalidated .Invalidated .Answers}}</strong></p>{{end}}
+<form method="POST">
+<input type="hidden" name="csrf" value="{{.CSRF}}">
+<input type="hidden" name="edit" value="true">
+<input type="hidden" name="editaction" value="save">
+<h2><label for="edit_description">{{.Translation.Description}}</label></h2>
+<p><textarea id="edit_description" name="description" rows="8" style="width: 100%;">{{.Description}}</textarea></p>
+<h2>{{.Translation.EditPollQuestions}}</h2>
+<input type="hidden" name="editquestions" value="{{len .Questions}}">
+<table>
+<thead>
+<tr>
+<th>{{.Translation.Question}}</th>
+<th>{{.Translation.EditPollRemove}}</th>
+</tr>
+</thead>
+<tbody>
+{{range .Questions}}
+<tr>
+<td><input type="hidden" name="editquestionold{{.Row}}" value="{{.Old}}"><input type="text" name="editquestion{{.Row}}" value="{{.Text}}" aria-label="{{$.Translation.Question}} {{.Row}}" maxlength="500"{{if $.FixedQuestions}} readonly{{end}}></td>
+<td class="centre">{{if ge .Old 0}}<input type="checkbox" name="editquestionremove{{.Row}}" aria-label="{{$.Translation.EditPollRemove}} {{.Text}}"{{if .Remove}} checked{{end}}>{{end}}</td>
+</tr>
+{{end}}
+</tbody>
+</table>
+<h2>{{.Translation.EditPollAnswerOptions}}</h2>
+<input type="hidden" name="editoptions" value="{{len .Options}}">
+<table>
+<thead>
+<tr>
+<th>{{.Translation.AnswerOption}}</th>
+<th>{{.Translation.Value}}</th>
+<th>{{.Translation.Colour}}</th>
+<th>{{.Translation.EditPollRemove}}</th>
+</tr>
+</thead>
+<tbody>
+{{range .Options}}
+<tr>
+<td><input type="hidden" name="editoptionold{{.Row}}" value="{{.Old}}"><input type="text" name="editoption{{.Row}}" value="{{.Text}}" aria-label="{{$.Translation.AnswerOption}} {{.Row}}" maxlength="500"></td>
+<td><input type="number" name="editoptionvalue{{.Row}}" value="{{.Value}}" step="any" aria-label="{{$.Translation.Value}} {{.Row}}"></td>
+<td><input type="color" name="editoptioncolour{{.Row}}" value="{{if .Colour}}{{.Colour}}{{else}}#ffffff{{end}}" aria-label="{{$.Translation.Colour}} {{.Row}}"></td>
+<td class="centre">{{if ge .Old 0}}<input type="checkbox" name="editoptionremove{{.Row}}" aria-label="{{$.Translation.EditPollRemove}} {{.Text}}"{{if .Remove}} checked{{end}}>{{end}}</td>
+</tr>
+{{end}}
+</tbody>
+</table>
+{{if .Invalidated}}<p><input type="checkbox" id="edit_confirm" name="confirminvalidate" required><label for="edit_confirm">{{printf .Translation.EditPollConfirm .Invalidated}}</label></p>{{end}}
+{{if .HasPassword}}
+<table style="border: none;">
+  <tr style="border: none; background-color: inherit;">
+    <td style="border: none;"><label>{{.Translation.Username}}: <input type="text" name="user" maxlength="500" value="{{.User}}" required></label></td>
+  </tr>
+  <tr style="border: none; background-color: inherit;">
+    <td style="border: none;"><label>{{.Translation.Password}}: <input type="password" name="pw" maxlength="500" required></label></td>
+  </tr>
+</table>
+{{end}}
+<p><input type="submit" value="{{.Translation.EditPollSave}}"></p>
+</form>
+<p><a href="/{{.Key}}">{{.Translation.BackToPoll}}</a></p>
+`))
+
+// editRows returns the rows of the edit form for the current structure of the poll.
+func (p Poll) editRows() ([]editQuestion, []editOption) {
+	questions := make([]editQuestion, 0, len(p.Questions)+editNewRows)
+	for i := range p.Questions {
+		questions = append(questions, editQuestion{Old: i, Text: p.Questions[i]})
+	}
+	if p.Dates == nil {
+		for i := 0; i < editNewRows; i++ {
+			questions = append(questions, editQuestion{Old: -1})
+		}
+	}
+	options := make([]editOption, 0, len(p.AnswerOption)+editNewRows)
+	for i := range p.AnswerOption {
+		options = append(options, editOption{Old: i, Text: p.AnswerOption[i][0], Value: p.AnswerOption[i][1], Colour: p.AnswerOption[i][2]})
+	}
+	for i := 0; i < editNewRows; i++ {
+		options = append(options, editOption{Old: -1, Value: "0.0"})
+	}
+	return questions, options
+}
+
+// parseEditRows returns the rows of the submitted edit form.
+// Existing questions and answer options must be sent at most once and in their original order.
+func (p Poll) parseEditRows(form url.Values) ([]editQuestion, []editOption, error) {
+	tl := GetDefaultTranslation()
+	parseOld := func(v string, n, previous int) (int, error) {
+		old, err := strconv.Atoi(v)
+		if err != nil || old < -1 || old >= n || (old != -1 && old <= previous) {
+			return 0, errors.New(tl.EditPollInvalid)
+		}
+		return old, nil
+	}
+
+	questionRows, err := parseFormList(form, "editquestions", config.MaxNumberQuestions+editNewRows, "editquestionold", "editquestion", "editquestionremove")
+	if err != nil {
+		return nil, nil, err
+	}
+	questions := make([]editQuestion, 0, len(questionRows))
+	previous := -1
+	for _, row := range questionRows {
+		old, err := parseOld(row.Values[0], len(p.Questions), previous)
+		if err != nil {
+			return nil, nil, err
+		}
+		q := editQuestion{Old: old, Text: row.Values[1], Remove: row.Values[2] != ""}
+		if old != -1 {
+			previous = old
+			if p.Dates != nil {
+				q.Text = p.Questions[old]
+			}
+		} else if p.Dates != nil && q.Text != "" {
+			return nil, nil, errors.New(tl.EditPollInvalid)
+		}
+		questions = append(questions, q)
+	}
+
+	optionRows, err := parseFormList(form, "editoptions", config.MaxNumberQuestions+editNewRows, "editoptionold", "editoption", "editoptionvalue", "editoptioncolour", "editoptionremove")
+	if err != nil {
+		return nil, nil, err
+	}
+	options := make([]editOption, 0, len(optionRows))
+	previous = -1
+	for _, row := range optionRows {
+		old, err := parseOld(row.Values[0], len(p.AnswerOption), previous)
+		if err != nil {
+			return nil, nil, err
+		}
+		if old != -1 {
+			previous = old
+		}
+		options = append(options, editOption{Old: old, Text: row.Values[1], Value: row.Values[2], Colour: row.Values[3], Remove: row.Values[4] != ""})
+	}
+	return questions, options, nil
+}
+
+// editedPoll returns the poll with the edited description, questions and answer options.
+// questionMap contains the previous index of each question (-1 for new questions), optionMap the new index of each previous answer option (-1 for removed options).
+func (p Poll) editedPoll(description string, questions []editQuestion, options []editOption) (Poll, []int, []int, error) {
+	tl := GetDefaultTranslation()
+	np := p
+	np.Description = description
+	np.Questions = make([]string, 0, len(questions))
+	np.Capacity = nil
+	np.Dates = nil
+	np.Sections = nil
+	np.FinalDate = 0
+	np.InvitationsSent = nil
+
+	questionMap := make([]int, 0, len(questions))
+	for _, q := range questions {
+		if q.Remove || q.Text == "" {
+			// Empty rows are removed as well
+			continue
+		}
+		questionMap = append(questionMap, q.Old)
+		np.Questions = append(np.Questions, q.Text)
+	}
+
+	if p.Capacity != nil {
+		np.Capacity = make([]int, len(questionMap))
+		for i, old := range questionMap {
+			if old != -1 {
+				np.Capacity[i] = p.Capacity[old]
+			}
+		}
+		if !np.hasCapacity() {
+			np.Capacity = nil
+		}
+	}
+	if p.Dates != nil {
+		np.Dates = make([]PollDate, len(questionMap))
+		for i, old := range questionMap {
+			np.Dates[i] = p.Dates[old]
+		}
+	}
+	for i, old := range questionMap {
+		if old != -1 && old == p.FinalDate-1 {
+			np.FinalDate = i + 1
+		}
+		if old != -1 && slices.Contains(p.InvitationsSent, old+1) {
+			np.InvitationsSent = append(np.InvitationsSent, i+1)
+		}
+	}
+	// A section starts at its first remaining question, empty sections are removed
+	for _, s := range p.Sections {
+		for i, old := range questionMap {
+			if old < s.Start {
+				continue
+			}
+			if len(np.Sections) != 0 && np.Sections[len(np.Sections)-1].Start == i {
+				// The previous section is empty
+				np.Sections = np.Sections[:len(np.Sections)-1]
+			}
+			np.Sections = append(np.Sections, PollSection{Title: s.Title, Start: i})
+			break
+		}
+	}
+
+	optionMap := make([]int, len(p.AnswerOption))
+	for i := range optionMap {
+		optionMap[i] = -1
+	}
+	np.AnswerOption = make([][]string, 0, len(options))
+	for _, o := range options {
+		if o.Remove || o.Text == "" {
+			continue
+		}
+		value := o.Value
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			value = "0.0"
+		}
+		colour := o.Colour
+		if colour == "" {
+			colour = "#ffffff"
+		}
+		if o.Old != -1 {
+			optionMap[o.Old] = len(np.AnswerOption)
+		}
+		np.AnswerOption = append(np.AnswerOption, []string{o.Text, value, colour})
+	}
+
+	if len(np.Questions) == 0 || len(np.AnswerOption) == 0 {
+		return Poll{}, nil, nil, errors.New(tl.PollNoOptions)
+	}
+	if !VerifyPollConfig(np) {
+		return Poll{}, nil, nil, errors.New(tl.EditPollInvalid)
+	}
+	return np, questionMap, optionMap, nil
+}
+
+// remapAnswer returns the results of an answer for the edited poll.
+// The bool is false if the answer can not be remapped, i.e. a single choice answer selected a removed answer option or is missing a new question.
+func (p Poll) remapAnswer(results, questionMap, optionMap []int) ([]int, bool) {
+	remapped := make([]int, len(questionMap))
+	for i, old := range questionMap {
+		if p.MultipleChoice {
+			// Removed answer options are unselected, new questions have no selection
+			if old == -1 || old >= len(results) {
+				continue
+			}
+			for _, o := range p.selectedOptions(results[old]) {
+				if optionMap[o] != -1 {
+					remapped[i] |= 1 << optionMap[o]
+				}
+			}
+			continue
+		}
+		if old == -1 || old >= len(results) || !p.validAnswer(results[old]) || optionMap[results[old]] == -1 {
+			return nil, false
+		}
+		remapped[i] = optionMap[results[old]]
+	}
+	return remapped, true
+}
+
+// structureUnchanged returns whether the stored configuration of the poll still has the revision rev.
+// pollStructureMutex must be held, so the poll can not be edited until the answer is saved.
+func structureUnchanged(key, rev string) (bool, error) {
+	b, err := safe.GetPollConfig(key)
+	if err != nil {
+		return false, err
+	}
+	current, err := LoadPoll(b)
+	if err != nil {
+		return false, err
+	}
+	return current.Revision() == rev, nil
+}
+
+// handleEdit shows the edit form of a poll ('edit=true') and saves the edited poll ('editaction=save').
+// Access is only allowed for the creator of the poll.
+func (p Poll) handleEdit(rw http.ResponseWriter, r *http.Request, key string) {
+	tl := GetDefaultTranslation()
+
+	if !checkCreator(rw, r, key, true) {
+		return
+	}
+	if p.Deleted {
+		rw.WriteHeader(http.StatusGone)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollIsDeleted)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	td := editTemplateStruct{
+		Key:            key,
+		Description:    p.Description,
+		FixedQuestions: p.Dates != nil,
+		HasPassword:    managementRequiresPassword(r),
+		User:           r.Form.Get("user"),
+		CSRF:           csrfToken(rw, r),
+		Translation:    tl,
+	}
+	td.Questions, td.Options = p.editRows()
+
+	if r.Form.Get("editaction") == "save" {
+		questions, options, err := p.parseEditRows(r.Form)
+		if err != nil {
+			writeFormListError(rw, err)
+			return
+		}
+		td.Description = r.Form.Get("description")
+		td.Questions, td.Options = questions, options
+		invalidated, status, err := p.saveEdit(r, key, td.Description, questions, options, r.Form.Get("confirminvalidate") != "")
+		if err != nil {
+			rw.WriteHeader(status)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(errorText(status, err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if invalidated == 0 {
+			http.Redirect(rw, r, "/"+key, http.StatusSeeOther)
+			return
+		}
+		// Ask for confirmation with the submitted values
+		td.Invalidated = invalidated
+	}
+
+	_, _, _, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	td.Answers = len(ids)
+	for i := range td.Questions {
+		td.Questions[i].Row = i + 1
+	}
+	for i := range td.Options {
+		td.Options[i].Row = i + 1
+	}
+
+	buf := bytes.Buffer{}
+	err = editTemplate.Execute(&buf, td)
+	if err != nil {
+		log.Printf("edit: %s", err.Error())
+	}
+	t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
+
+// saveEdit saves the edited poll and remaps all answers.
+// If answers can not be remapped and confirm is false, nothing is saved and the number of these answers is returned.
+// On error, the HTTP status to report is returned as well.
+func (p Poll) saveEdit(r *http.Request, key, description string, questions []editQuestion, options []editOption, confirm bool) (int, int, error) {
+	tl := GetDefaultTranslation()
+	np, questionMap, optionMap, err := p.editedPoll(description, questions, options)
+	if err != nil {
+		return 0, http.StatusBadRequest, err
+	}
+	if !np.filterPollTexts() {
+		return 0, http.StatusBadRequest, errors.New(tl.ContentFiltered)
+	}
+
+	pollStructureMutex.Lock()
+	defer pollStructureMutex.Unlock()
+
+	// The poll might have been edited in parallel
+	unchanged, err := structureUnchanged(key, p.Revision())
+	if err != nil {
+		return 0, http.StatusInternalServerError, err
+	}
+	if !unchanged {
+		return 0, http.StatusConflict, errors.New(tl.PollChanged)
+	}
+
+	results, names, _, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		return 0, http.StatusInternalServerError, err
+	}
+	remapped := make([][]int, len(results))
+	invalid := make([]int, 0)
+	for i := range results {
+		a, ok := p.remapAnswer(results[i], questionMap, optionMap)
+		if !ok {
+			invalid = append(invalid, i)
+			continue
+		}
+		remapped[i] = a
+	}
+	if len(invalid) != 0 && !confirm {
+		return len(invalid), http.StatusOK, nil
+	}
+
+	b, err := np.ExportPoll()
+	if err != nil {
+		return 0, http.StatusInternalServerError, err
+	}
+	err = safe.SavePollConfig(key, b)
+	if err != nil {
+		return 0, http.StatusInternalServerError, err
+	}
+
+	for i := range results {
+		if remapped[i] == nil || slices.Equal(remapped[i], results[i]) {
+			continue
+		}
+		_, _, comment, err := safe.GetSinglePollResult(key, ids[i])
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+		change, err := safe.GetChange(key, ids[i])
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+		err = safe.OverwritePollResult(key, ids[i], names[i], comment, remapped[i], change)
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+	}
+	for _, i := range invalid {
+		err = safe.DeleteAnswer(key, ids[i])
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+		countAnswerDeletion(r, key)
+		recordPollEvent(key, pollEventDeleted, ids[i], names[i])
+	}
+
+	recordPollEvent(key, pollEventPollEdited, "", requestUser(r))
+	markAggregateDirty(key)
+	publishLiveUpdate(key, liveAnswerChanged)
+	return 0, http.StatusOK, nil
+}
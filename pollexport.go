@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PollFullExport contains a poll together with all of its answers.
+// It can be loaded as configuration of a new poll to move a poll between instances.
+type PollFullExport struct {
+	Poll    json.RawMessage // same format as the exported configuration
+	Answers []PollExportAnswer
+}
+
+// PollExportAnswer contains a single answer of an exported poll.
+type PollExportAnswer struct {
+	ID             string // ID on the exporting instance, new IDs are assigned on import
+	Name           string
+	Comment        string
+	Results        []int
+	Change         string     `json:",omitempty"`
+	Mail           string     `json:",omitempty"`
+	ConsentTime    *time.Time `json:",omitempty"`
+	ConsentVersion string     `json:",omitempty"`
+}
+
+// ExportFull returns the configuration and all answers of the poll stored under key.
+func (p Poll) ExportFull(key string) ([]byte, error) {
+	c, err := p.ExportPoll()
+	if err != nil {
+		return nil, err
+	}
+	results, names, comments, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		return nil, err
+	}
+	mails, err := safe.GetAnswerMails(key)
+	if err != nil {
+		return nil, err
+	}
+
+	export := PollFullExport{Poll: c, Answers: make([]PollExportAnswer, 0, len(ids))}
+	for i := range ids {
+		change, err := safe.GetChange(key, ids[i])
+		if err != nil {
+			return nil, err
+		}
+		a := PollExportAnswer{
+			ID:      ids[i],
+			Name:    names[i],
+			Comment: comments[i],
+			Results: results[i],
+			Change:  change,
+			Mail:    mails[ids[i]],
+		}
+		t, v, err := safe.GetConsent(key, ids[i])
+		if err != nil {
+			return nil, err
+		}
+		if !t.IsZero() {
+			a.ConsentTime = &t
+			a.ConsentVersion = v
+		}
+		export.Answers = append(export.Answers, a)
+	}
+	return json.MarshalIndent(export, "", "  ")
+}
+
+// parseFullExport parses data as a full export.
+// The bool is false if data is not a full export (e.g. only a configuration).
+func parseFullExport(data []byte) (PollFullExport, bool) {
+	var e PollFullExport
+	err := json.Unmarshal(data, &e)
+	if err != nil || len(e.Poll) == 0 || e.Answers == nil {
+		return PollFullExport{}, false
+	}
+	return e, true
+}
+
+// verifyExportAnswers checks whether the answers fit to the poll.
+// Names and comments are checked against the content filter, so they might be masked afterwards.
+func (p Poll) verifyExportAnswers(answers []PollExportAnswer) error {
+	if len(answers) > csvImportMaxRows {
+		return fmt.Errorf("more than %d answers", csvImportMaxRows)
+	}
+	for i := range answers {
+		if len(answers[i].Results) != len(p.Questions) {
+			return fmt.Errorf("answer %s: expected %d results, got %d", answers[i].ID, len(p.Questions), len(answers[i].Results))
+		}
+		for _, r := range answers[i].Results {
+			if !p.validAnswer(r) {
+				return fmt.Errorf("answer %s: unknown answer option %d", answers[i].ID, r)
+			}
+		}
+		if !filterContent(&answers[i].Name, &answers[i].Comment) {
+			return fmt.Errorf("answer %s: %s", answers[i].ID, GetDefaultTranslation().ContentFiltered)
+		}
+	}
+	// The poll is new, so only the imported answers count towards the capacity
+	if exceeded := p.importExceedsCapacity(make([]int, len(p.Questions)), exportResults(answers)); len(exceeded) != 0 {
+		return errors.New(capacityExceededMessage(GetDefaultTranslation(), exceeded))
+	}
+	return nil
+}
+
+// exportResults returns the results of all exported answers.
+func exportResults(answers []PollExportAnswer) [][]int {
+	results := make([][]int, len(answers))
+	for i := range answers {
+		results[i] = answers[i].Results
+	}
+	return results
+}
+
+// importExportAnswers saves exported answers to the poll stored under key. The answers must be verified with verifyExportAnswers first.
+// Answers get new IDs, but keep their edit token, email address and consent.
+func (p Poll) importExportAnswers(key string, answers []PollExportAnswer) error {
+	if p.hasCapacity() {
+		// Answers might have been given since the poll was created
+		defer lockCapacity(key)()
+		exceeded, err := p.checkImportCapacity(key, exportResults(answers))
+		if err != nil {
+			return err
+		}
+		if len(exceeded) != 0 {
+			return errors.New(capacityExceededMessage(GetDefaultTranslation(), exceeded))
+		}
+	}
+
+	for i := range answers {
+		a := answers[i]
+		if p.DisableComments {
+			a.Comment = ""
+		}
+		if p.Anonymous {
+			a.Name = ""
+		}
+		id, err := safe.SavePollResult(key, a.Name, a.Comment, a.Results, a.Change)
+		if err != nil {
+			return err
+		}
+		if a.Mail != "" {
+			err = safe.SaveAnswerMail(key, id, a.Mail)
+			if err != nil {
+				return err
+			}
+		}
+		if a.ConsentTime != nil {
+			err = safe.SaveConsent(key, id, *a.ConsentTime, a.ConsentVersion)
+			if err != nil {
+				return err
+			}
+		}
+		hookAnswerSaved(key, id, false)
+	}
+	return nil
+}
+
+// handleFullExport writes the configuration and all answers of the poll as a JSON download.
+func (p Poll) handleFullExport(rw http.ResponseWriter, key string) {
+	b, err := p.ExportFull(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-full.json\"", strings.NewReplacer("\"", "", "/", "-", "\\", "-").Replace(key)))
+	rw.Write(b)
+}
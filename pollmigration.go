@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PollVersion is the current version of the poll configuration schema.
+// It must be increased whenever the structure of Poll changes in a way older configurations can not be read directly.
+// In that case, a migration must be added to pollMigrations.
+const PollVersion = 1
+
+// pollMigrations contains all migrations of the poll configuration schema.
+// pollMigrations[i] migrates a configuration from version i to version i+1.
+// Configurations without a version are treated as version 0.
+var pollMigrations = []func(c map[string]json.RawMessage) error{
+	// 0 -> 1: Version field introduced, no structural changes.
+	func(c map[string]json.RawMessage) error { return nil },
+}
+
+func init() {
+	if len(pollMigrations) != PollVersion {
+		panic(fmt.Sprintf("poll migrations: have %d migrations for version %d", len(pollMigrations), PollVersion))
+	}
+}
+
+// migratePollConfig migrates a poll configuration to the current version.
+func migratePollConfig(config []byte) ([]byte, error) {
+	c := make(map[string]json.RawMessage)
+	err := json.Unmarshal(config, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	version := 0
+	if v, ok := c["Version"]; ok {
+		err = json.Unmarshal(v, &version)
+		if err != nil {
+			return nil, fmt.Errorf("poll migration: invalid version: %w", err)
+		}
+	}
+
+	if version == PollVersion {
+		return config, nil
+	}
+	if version < 0 || version > PollVersion {
+		return nil, fmt.Errorf("poll migration: unsupported version %d (supported up to %d)", version, PollVersion)
+	}
+
+	for ; version < PollVersion; version++ {
+		err = pollMigrations[version](c)
+		if err != nil {
+			return nil, fmt.Errorf("poll migration %d -> %d: %w", version, version+1, err)
+		}
+	}
+
+	c["Version"] = json.RawMessage(fmt.Sprint(PollVersion))
+	return json.Marshal(c)
+}
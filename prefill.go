@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+)
+
+// Organisers can send links which open the answer form with suggested selections, e.g. '<poll>?answer=1&prefill=0,2,1'.
+// The 'prefill' parameter contains the index of the answer option for each question (in the order of the poll), separated by commas.
+// Empty entries leave a question unselected. For multiple choice polls, several answer options of a question are separated by '+'.
+// The selections are only suggestions, participants still have to submit the form.
+
+// parsePrefill returns the answers given by the 'prefill' parameter v. Questions without suggestion are -1.
+// The bool is false if v contains more entries than questions or an invalid answer option.
+func (p Poll) parsePrefill(v string) ([]int, bool) {
+	entries := strings.Split(v, ",")
+	if len(entries) > len(p.Questions) {
+		return nil, false
+	}
+	answers := make([]int, len(p.Questions))
+	for i := range answers {
+		answers[i] = -1
+	}
+	for i, e := range entries {
+		// '+' is decoded as space in queries
+		options := strings.Fields(strings.ReplaceAll(e, "+", " "))
+		if len(options) == 0 {
+			continue
+		}
+		if !p.MultipleChoice && len(options) != 1 {
+			return nil, false
+		}
+		a, ok := p.parseFormAnswer(options)
+		if !ok || !p.validAnswer(a) {
+			return nil, false
+		}
+		answers[i] = a
+	}
+	return answers, true
+}
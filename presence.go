@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// presenceMaxConnections is the maximum number of presence connections per poll.
+const presenceMaxConnections = 500
+
+type presencePoll struct {
+	filling int
+	conns   map[*websocket.Conn]bool
+}
+
+var presenceMutex sync.Mutex
+var presencePolls = make(map[string]*presencePoll)
+
+// HandlePresence upgrades the request to a WebSocket which receives the number of people currently filling in the poll stored under key.
+// If filling is true, the connection itself counts as a person filling in the poll.
+func HandlePresence(rw http.ResponseWriter, r *http.Request, key string, filling bool) {
+	presenceMutex.Lock()
+	p, ok := presencePolls[key]
+	if ok && len(p.conns) >= presenceMaxConnections {
+		presenceMutex.Unlock()
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	presenceMutex.Unlock()
+
+	websocket.Handler(func(conn *websocket.Conn) {
+		presenceMutex.Lock()
+		p, ok := presencePolls[key]
+		if !ok {
+			p = &presencePoll{conns: make(map[*websocket.Conn]bool)}
+			presencePolls[key] = p
+		}
+		p.conns[conn] = filling
+		if filling {
+			p.filling++
+		}
+		presenceMutex.Unlock()
+		presenceBroadcast(key)
+
+		// The client does not send anything, wait until the connection is closed
+		var discard string
+		for {
+			if websocket.Message.Receive(conn, &discard) != nil {
+				break
+			}
+		}
+
+		presenceMutex.Lock()
+		delete(p.conns, conn)
+		if filling {
+			p.filling--
+		}
+		if len(p.conns) == 0 {
+			delete(presencePolls, key)
+		}
+		presenceMutex.Unlock()
+		presenceBroadcast(key)
+	}).ServeHTTP(rw, r)
+}
+
+func presenceBroadcast(key string) {
+	presenceMutex.Lock()
+	p, ok := presencePolls[key]
+	if !ok {
+		presenceMutex.Unlock()
+		return
+	}
+	message := strconv.Itoa(p.filling)
+	conns := make([]*websocket.Conn, 0, len(p.conns))
+	for c := range p.conns {
+		conns = append(conns, c)
+	}
+	presenceMutex.Unlock()
+
+	for i := range conns {
+		conns[i].SetWriteDeadline(time.Now().Add(5 * time.Second))
+		websocket.Message.Send(conns[i], message)
+	}
+}
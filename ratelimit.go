@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"html/template"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimiterMaxEntries is the maximum number of IPs tracked per rate limiter.
+// If it is reached, IPs with a full bucket are removed. If this is not enough, the least recently seen half is removed.
+const rateLimiterMaxEntries = 10000
+
+type rateLimiterEntry struct {
+	limiter *rate.Limiter
+	last    time.Time
+}
+
+// rateLimiter is a token bucket rate limiter keyed by client IP.
+// A nil rateLimiter allows all requests.
+type rateLimiter struct {
+	name    string
+	limit   rate.Limit
+	burst   int
+	mutex   sync.Mutex
+	entries map[string]*rateLimiterEntry
+}
+
+var (
+	createRateLimiter      *rateLimiter
+	voteRateLimiter        *rateLimiter
+	failedLoginRateLimiter *rateLimiter
+)
+
+// newRateLimiter returns a rate limiter allowing perMinute requests per minute and IP on average and up to burst requests at once.
+// It returns nil if perMinute is not positive. A burst smaller than 1 is set to perMinute.
+func newRateLimiter(name string, perMinute, burst int) *rateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = perMinute
+	}
+	return &rateLimiter{name: name, limit: rate.Limit(float64(perMinute) / 60), burst: burst, entries: make(map[string]*rateLimiterEntry)}
+}
+
+// initialiseRateLimiters creates the rate limiters configured through 'RateLimit*'.
+func initialiseRateLimiters() {
+	createRateLimiter = newRateLimiter("create", config.RateLimitCreatePerMinute, config.RateLimitCreateBurst)
+	voteRateLimiter = newRateLimiter("vote", config.RateLimitVotePerMinute, config.RateLimitVoteBurst)
+	failedLoginRateLimiter = newRateLimiter("failed login", config.RateLimitFailedLoginPerMinute, config.RateLimitFailedLoginBurst)
+}
+
+// rateLimitsEnabled returns whether any rate limit is configured.
+func rateLimitsEnabled() bool {
+	return createRateLimiter != nil || voteRateLimiter != nil || failedLoginRateLimiter != nil
+}
+
+// limiter returns the limiter of the key. The mutex must be held.
+func (l *rateLimiter) limiter(key string, now time.Time) *rate.Limiter {
+	e, ok := l.entries[key]
+	if !ok {
+		if len(l.entries) >= rateLimiterMaxEntries {
+			l.cleanup(now)
+		}
+		e = &rateLimiterEntry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.entries[key] = e
+	}
+	e.last = now
+	return e.limiter
+}
+
+// cleanup removes entries to make room for new IPs. The mutex must be held.
+// Entries with a full bucket behave like new entries, so removing them does not change the limits.
+func (l *rateLimiter) cleanup(now time.Time) {
+	for k, e := range l.entries {
+		if e.limiter.TokensAt(now) >= float64(l.burst) {
+			delete(l.entries, k)
+		}
+	}
+	if len(l.entries) < rateLimiterMaxEntries/2 {
+		return
+	}
+
+	last := make([]time.Time, 0, len(l.entries))
+	for _, e := range l.entries {
+		last = append(last, e.last)
+	}
+	slices.SortFunc(last, func(a, b time.Time) int { return a.Compare(b) })
+	cutoff := last[len(last)/2]
+	for k, e := range l.entries {
+		if !e.last.After(cutoff) {
+			delete(l.entries, k)
+		}
+	}
+}
+
+// take removes a token for the key. It returns false if no token is available.
+func (l *rateLimiter) take(key string) bool {
+	if l == nil {
+		return true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.limiter(key, time.Now()).Allow()
+}
+
+// available returns whether a token is available for the key without removing it.
+func (l *rateLimiter) available(key string) bool {
+	if l == nil {
+		return true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.limiter(key, time.Now()).Tokens() >= 1
+}
+
+// retryAfter returns the number of seconds until a new token is available.
+func (l *rateLimiter) retryAfter() int {
+	return int(math.Ceil(1 / float64(l.limit)))
+}
+
+// statusRecorder remembers the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// logRateLimited logs a request rejected by l.
+func logRateLimited(r *http.Request, l *rateLimiter) {
+	if config.LogFailedLogin {
+		log.Printf("rate limit: %s limit reached for %s", l.name, GetRealIP(r))
+	}
+}
+
+// writeRateLimited writes the response for a request rejected by l.
+func writeRateLimited(rw http.ResponseWriter, r *http.Request, l *rateLimiter) {
+	logRateLimited(r, l)
+	tl := GetDefaultTranslation()
+	rw.Header().Set("Retry-After", strconv.Itoa(l.retryAfter()))
+	rw.WriteHeader(http.StatusTooManyRequests)
+	t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.RateLimited)), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
+
+// writeRateLimitedText writes the plain text response for a request rejected by l.
+func writeRateLimitedText(rw http.ResponseWriter, r *http.Request, l *rateLimiter) {
+	logRateLimited(r, l)
+	rw.Header().Set("Retry-After", strconv.Itoa(l.retryAfter()))
+	rw.WriteHeader(http.StatusTooManyRequests)
+	rw.Write([]byte(GetDefaultTranslation().RateLimited))
+}
+
+// writeRateLimitedAPI writes the API response for a request rejected by l.
+func writeRateLimitedAPI(rw http.ResponseWriter, r *http.Request, l *rateLimiter) {
+	logRateLimited(r, l)
+	rw.Header().Set("Retry-After", strconv.Itoa(l.retryAfter()))
+	writeAPIError(rw, http.StatusTooManyRequests, GetDefaultTranslation().RateLimited)
+}
+
+// limitRequest applies the rate limits to a request before calling h. The requests are keyed by the IP returned by GetRealIP.
+// l is charged for the request (nil if no limit applies). If login is true, the request contains credentials
+// and a response with the status code failed counts as a failed login. write writes the response for rejected requests.
+func limitRequest(rw http.ResponseWriter, r *http.Request, h http.HandlerFunc, l *rateLimiter, login bool, failed int, write func(http.ResponseWriter, *http.Request, *rateLimiter)) {
+	ip := GetRealIP(r)
+	if !l.take(ip) {
+		write(rw, r, l)
+		return
+	}
+
+	if failedLoginRateLimiter == nil || !login {
+		h(rw, r)
+		return
+	}
+	if !failedLoginRateLimiter.available(ip) {
+		write(rw, r, failedLoginRateLimiter)
+		return
+	}
+	s := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+	h(s, r)
+	if s.status == failed {
+		failedLoginRateLimiter.take(ip)
+	}
+}
+
+// parseRateLimitForm parses the form of r so that the rate limits can inspect it.
+// The form is parsed with the same limits as in the handlers, which can use the parsed form afterwards. Errors are left to the handler.
+func parseRateLimitForm(r *http.Request) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.ParseMultipartForm(10000000) // 10 MB
+	} else {
+		r.ParseForm()
+	}
+}
+
+// rateLimitHandle wraps the poll handler h with the rate limits for poll creation, vote submission and failed logins.
+// If no limit is configured, h is returned.
+func rateLimitHandle(h http.HandlerFunc) http.HandlerFunc {
+	if !rateLimitsEnabled() {
+		return h
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodPut {
+			h(rw, r)
+			return
+		}
+
+		parseRateLimitForm(r)
+		var l *rateLimiter
+		if r.Method == http.MethodPost {
+			switch {
+			case r.Form.Get("type") != "":
+				l = createRateLimiter
+			case r.Form.Get("answer") != "":
+				l = voteRateLimiter
+			}
+		}
+		login := r.Form.Get("pw") != "" || r.Form.Get("participantpw") != ""
+		limitRequest(rw, r, h, l, login, http.StatusForbidden, writeRateLimited)
+	}
+}
+
+// loginRateLimitHandle wraps the form handler h with the rate limit for failed logins.
+// If no limit is configured, h is returned.
+func loginRateLimitHandle(h http.HandlerFunc) http.HandlerFunc {
+	if failedLoginRateLimiter == nil {
+		return h
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			h(rw, r)
+			return
+		}
+
+		parseRateLimitForm(r)
+		limitRequest(rw, r, h, nil, r.Form.Get("pw") != "", http.StatusForbidden, writeRateLimited)
+	}
+}
+
+// uploadRateLimitHandle wraps the upload handler h with the rate limits for poll creation and failed logins.
+// Uploads are counted as poll creation. If no limit is configured, h is returned.
+func uploadRateLimitHandle(h http.HandlerFunc) http.HandlerFunc {
+	if createRateLimiter == nil && failedLoginRateLimiter == nil {
+		return h
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			h(rw, r)
+			return
+		}
+
+		limitRequest(rw, r, h, createRateLimiter, config.AuthenticationEnabled, http.StatusForbidden, writeRateLimitedText)
+	}
+}
+
+// apiRateLimitHandle wraps the API handler h with the rate limits for poll creation, vote submission and failed logins.
+// Requests with HTTP basic authentication are counted as login. If no limit is configured, h is returned.
+func apiRateLimitHandle(h http.HandlerFunc) http.HandlerFunc {
+	if !rateLimitsEnabled() {
+		return h
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request) {
+		var l *rateLimiter
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, strings.Join([]string{config.ServerPath, apiPollsPath}, "")), "/")
+		switch {
+		case len(parts) == 1 && r.Method == http.MethodPut:
+			l = createRateLimiter
+		case len(parts) == 2 && r.Method == http.MethodPost, len(parts) == 3 && r.Method == http.MethodPut:
+			l = voteRateLimiter
+		}
+		_, _, login := r.BasicAuth()
+		limitRequest(rw, r, h, l, login, http.StatusUnauthorized, writeRateLimitedAPI)
+	}
+}
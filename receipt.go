@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// receiptCode returns the receipt of an answer in an anonymous poll.
+// The receipt contains the secret used for editing, so it must only be shown to the voter.
+func receiptCode(answerID, change string) string {
+	return answerID + "-" + change
+}
+
+// resolveReceipt returns the ID of the answer the receipt belongs to.
+// The bool indicates whether the receipt is valid.
+func resolveReceipt(key, receipt string) (string, bool, error) {
+	// The change is base32 encoded, so the last dash separates it from the answer ID
+	receipt = strings.TrimSpace(receipt)
+	i := strings.LastIndex(receipt, "-")
+	if i <= 0 {
+		return "", false, nil
+	}
+	answerID, given := receipt[:i], receipt[i+1:]
+
+	// Unknown answer IDs are reported as errors by the data safes, so check them first
+	_, _, _, ids, err := safe.GetPollResult(key)
+	if err != nil {
+		return "", false, err
+	}
+	if !slices.Contains(ids, answerID) {
+		return "", false, nil
+	}
+
+	change, err := safe.GetChange(key, answerID)
+	if err != nil {
+		return "", false, err
+	}
+	if change == "" || subtle.ConstantTimeCompare([]byte(change), []byte(given)) == 0 {
+		return "", false, nil
+	}
+	return answerID, true, nil
+}
+
+// writeReceipt shows the receipt of an answer after it was saved.
+func writeReceipt(rw http.ResponseWriter, key, receipt string) {
+	tl := GetDefaultTranslation()
+	text := fmt.Sprintf(`<p>%s</p><p><strong><code>%s</code></strong></p><p><a href="/%s?answer=yes&amp;receipt=%s">%s</a></p><p><a href="/%s">%s</a></p>`,
+		template.HTMLEscapeString(tl.ReceiptIntro),
+		template.HTMLEscapeString(receipt),
+		template.HTMLEscapeString(key), template.HTMLEscapeString(url.QueryEscape(receipt)), template.HTMLEscapeString(tl.ReceiptVerify),
+		template.HTMLEscapeString(key), template.HTMLEscapeString(tl.BackToPoll))
+	t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	auth "github.com/Top-Ranger/pollgo/authenticater"
+)
+
+// registrationValidity is the time a registration can be confirmed.
+const registrationValidity = 24 * time.Hour
+
+// maxPendingRegistrations is the maximum number of unconfirmed registrations.
+const maxPendingRegistrations = 1000
+
+type pendingRegistration struct {
+	user    string
+	hash    string
+	expires time.Time
+}
+
+var pendingRegistrations = make(map[string]pendingRegistration)
+var pendingRegistrationsMutex sync.Mutex
+
+// htpasswdMutex protects the user file of the Htpasswd authenticater against parallel changes by the server.
+var htpasswdMutex sync.Mutex
+
+var registerTemplate = template.Must(template.New("register").Parse(`
+<h1>{{.Register}}</h1>
+<form method="POST">
+  <table style="border: none;">
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="user">{{.EmailAddress}}: </label></td>
+      <td style="border: none;"><input type="email" id="user" name="user" maxlength="254" required></td>
+    </tr>
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="pw">{{.Password}}: </label></td>
+      <td style="border: none;"><input type="password" id="pw" name="pw" maxlength="500" autocomplete="new-password" required></td>
+    </tr>
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="pw2">{{.RepeatPassword}}: </label></td>
+      <td style="border: none;"><input type="password" id="pw2" name="pw2" maxlength="500" autocomplete="new-password" required></td>
+    </tr>
+  </table>
+  <p><input type="submit" value="{{.Register}}"></p>
+</form>
+`))
+
+// addLocalUser adds a user to the user file of the Htpasswd authenticater and reloads the authenticater.
+// It returns false if the user already exists.
+func addLocalUser(user, hash string) (bool, error) {
+	htpasswdMutex.Lock()
+	defer htpasswdMutex.Unlock()
+
+	exists, err := localUserExists(user)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+	err = updateHtpasswd(config.AuthenticaterConfig, user, hash, true)
+	if err != nil {
+		return false, err
+	}
+	b, err := os.ReadFile(config.AuthenticaterConfig)
+	if err != nil {
+		return false, err
+	}
+	return true, authenticater.LoadConfig(b)
+}
+
+// localUserExists returns whether the user is in the user file of the Htpasswd authenticater.
+func localUserExists(user string) (bool, error) {
+	b, err := os.ReadFile(config.AuthenticaterConfig)
+	if err != nil {
+		return false, err
+	}
+	users, err := auth.ParseHtpasswd(b)
+	if err != nil {
+		return false, err
+	}
+	_, ok := users[user]
+	return ok, nil
+}
+
+func registerHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	tl := GetDefaultTranslation()
+
+	switch r.Method {
+	case http.MethodGet:
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			buf := bytes.Buffer{}
+			err := registerTemplate.Execute(&buf, tl)
+			if err != nil {
+				log.Printf("register: %s", err.Error())
+			}
+			t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		pendingRegistrationsMutex.Lock()
+		p, ok := pendingRegistrations[token]
+		delete(pendingRegistrations, token)
+		pendingRegistrationsMutex.Unlock()
+		if !ok || time.Now().After(p.expires) {
+			rw.WriteHeader(http.StatusNotFound)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.InvalidToken)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		added, err := addLocalUser(p.user, p.hash)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if !added {
+			rw.WriteHeader(http.StatusConflict)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.UserExists)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		log.Printf("register: added user %s", p.user)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.RegistrationComplete)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	case http.MethodPost:
+		err := r.ParseForm()
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		user, pw := strings.TrimSpace(r.Form.Get("user")), r.Form.Get("pw")
+		if !validMailAddress(user) || strings.ContainsAny(user, ":#") {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.InvalidEmailAddress)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if pw == "" || len(pw) > 500 || pw != r.Form.Get("pw2") {
+			rw.WriteHeader(http.StatusBadRequest)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PasswordsDoNotMatch)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		exists, err := localUserExists(user)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if exists {
+			// Do not reveal which users exist
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.RegistrationMailSent)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		hash, err := auth.HashPassword(pw)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		b := make([]byte, 32)
+		_, err = rand.Read(b)
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		token := base64.RawURLEncoding.EncodeToString(b)
+
+		now := time.Now()
+		pendingRegistrationsMutex.Lock()
+		for k, p := range pendingRegistrations {
+			if now.After(p.expires) || p.user == user {
+				delete(pendingRegistrations, k)
+			}
+		}
+		if len(pendingRegistrations) >= maxPendingRegistrations {
+			pendingRegistrationsMutex.Unlock()
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			t := textTemplateStruct{"503 Service Unavailable", tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		pendingRegistrations[token] = pendingRegistration{user: user, hash: hash, expires: now.Add(registrationValidity)}
+		pendingRegistrationsMutex.Unlock()
+
+		link := fmt.Sprintf("%s%s/register.html?token=%s", config.PublicURL, config.ServerPath, token)
+		err = sendMail(user, fmt.Sprintf("%s: %s", config.InstanceName, tl.Register), fmt.Sprintf(tl.RegistrationMailText, config.InstanceName, link))
+		if err != nil {
+			log.Printf("register: can not send mail: %s", err.Error())
+			pendingRegistrationsMutex.Lock()
+			delete(pendingRegistrations, token)
+			pendingRegistrationsMutex.Unlock()
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.RegistrationMailSent)), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	default:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		t := textTemplateStruct{"405 Method Not Allowed", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	}
+}
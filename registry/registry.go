@@ -18,7 +18,9 @@
 package registry
 
 import (
+	"errors"
 	"sync"
+	"time"
 )
 
 // AlreadyRegisteredError represents an error where an option is already registeres
@@ -29,8 +31,15 @@ func (a AlreadyRegisteredError) Error() string {
 	return string(a)
 }
 
+// ErrPollNotAvailable must be returned by a DataSafe if results should be saved for a poll without configuration or marked as deleted.
+var ErrPollNotAvailable = errors.New("poll does not exist or is deleted")
+
+// ErrInvitationChanged must be returned by a DataSafe by SetInvitationAnswer if the invitation does not exist or is not linked to the expected answer.
+var ErrInvitationChanged = errors.New("invitation does not exist or was changed")
+
 // DataSafe represents a backend for save storage of poll configuration and results.
 // All results must be stored in the same order they are added.
+// SavePollResult and OverwritePollResult must not save results for polls without configuration or marked as deleted (see ErrPollNotAvailable).
 // All methods must be save for parallel usage.
 type DataSafe interface {
 	SavePollResult(pollID, name, comment string, results []int, change string) (string, error)
@@ -38,12 +47,51 @@ type DataSafe interface {
 	GetPollResult(pollID string) (results [][]int, name []string, comment []string, answerIDs []string, err error)
 	GetSinglePollResult(pollID, answerID string) (result []int, name string, comment string, err error)
 	DeleteAnswer(pollID, answerID string) error
+	EndorseAnswer(pollID, answerID string) error
+	GetEndorsements(pollID string) (map[string]int, error)
+	GetAnswerTimes(pollID string) (created map[string]time.Time, modified map[string]time.Time, err error)
+	SaveConsent(pollID, answerID string, t time.Time, version string) error
+	GetConsent(pollID, answerID string) (time.Time, string, error)
+	SaveAnswerMail(pollID, answerID, mail string) error
+	GetAnswerMails(pollID string) (map[string]string, error)
+	PseudonymiseAnswers(before time.Time, pseudonym func(pollID, answerID string) string) (int, error)
 	SavePollConfig(pollID string, config []byte) error
 	GetPollConfig(pollID string) ([]byte, error)
 	SavePollCreator(pollID, name string) error
 	GetPollCreator(pollID string) (string, error)
+	GetPollsByCreator(name string) ([]string, error)
+	SavePollOrganizers(pollID string, names []string) error
+	GetPollOrganizers(pollID string) ([]string, error)
 	MarkPollDeleted(pollID string) error
 	GetChange(pollID, answerID string) (string, error)
+	GetLastActivity(pollID string) (time.Time, error)
+	SaveDiscussionEntry(pollID, name, text string) (string, error)
+	GetDiscussion(pollID string) (names []string, texts []string, times []time.Time, entryIDs []string, err error)
+	DeleteDiscussionEntry(pollID, entryID string) error
+	SetReminder(pollID string, t time.Time) error
+	GetDueReminders(until time.Time) ([]string, error)
+	SetFollowUp(pollID string, t time.Time) error
+	GetDueFollowUps(until time.Time) ([]string, error)
+	SetExpiry(pollID string, t time.Time) error
+	GetExpiredPolls(until time.Time) ([]string, error)
+	SaveAggregate(pollID string, aggregate []byte) error
+	GetAggregate(pollID string) ([]byte, error)
+	GetPollsCreatedBefore(before time.Time) ([]string, error)
+	ListPolls() ([]string, error)
+	GetPollStats(pollID string) (answers int, lastModified time.Time, err error)
+	SaveReport(pollID, reason string) error
+	GetReports(pollID string) (reasons []string, times []time.Time, err error)
+	DeleteReports(pollID string) error
+	GetReportedPolls() ([]string, error)
+	SavePollEvent(pollID, event, answerID, actor string) error
+	GetPollEvents(pollID string) (events []string, answerIDs []string, actors []string, times []time.Time, err error)
+	AddPollAccess(pollID string, views, opens, uniqueOpens int) error
+	GetPollAccess(pollID string) (views, opens, uniqueOpens int, err error)
+	SaveInvitation(pollID, token, name string) error
+	GetInvitations(pollID string) (tokens []string, names []string, answerIDs []string, err error)
+	SetInvitationAnswer(pollID, token, previousAnswerID, answerID string) error
+	GetStorageUsage() (polls int, bytes int64, err error)
+	HealthCheck() error
 	RunGC() error
 	LoadConfig(data []byte) error
 	FlushAndClose()
@@ -57,11 +105,36 @@ type Authenticater interface {
 	Authenticate(user, password string) (bool, error)
 }
 
+// Hook allows integrations to react to events of polls in-process.
+// It can safely be assumed that LoadConfig will only be called once before any other method will be called.
+// LoadConfig gets nil if no configuration is given.
+// All other methods are called synchronously after the event was saved, so they should return quickly.
+// They must be safely callable in parallel. Errors are logged, but do not undo the event.
+type Hook interface {
+	LoadConfig(b []byte) error
+	OnPollCreated(pollID, creator string) error
+	OnAnswerSaved(pollID, answerID string, changed bool) error
+	OnPollDeleted(pollID string) error
+}
+
+// Formatter renders texts (e.g. descriptions of polls) as HTML.
+// The output is sanitised afterwards, so a Formatter does not need to remove unsafe HTML itself.
+// It can safely be assumed that LoadConfig will only be called once before Format will be called.
+// Format must be safely callable in parallel.
+type Formatter interface {
+	LoadConfig(b []byte) error
+	Format(b []byte) ([]byte, error)
+}
+
 var (
 	knownDataSafes          = make(map[string]DataSafe)
 	knownDataSafesMutex     = sync.RWMutex{}
 	knownAuthenticater      = make(map[string]Authenticater)
 	knownAuthenticaterMutex = sync.RWMutex{}
+	knownHooks              = make(map[string]Hook)
+	knownHooksMutex         = sync.RWMutex{}
+	knownFormatters         = make(map[string]Formatter)
+	knownFormattersMutex    = sync.RWMutex{}
 )
 
 // RegisterDataSafe registeres a data safe.
@@ -111,3 +184,51 @@ func GetAuthenticater(name string) (Authenticater, bool) {
 	a, ok := knownAuthenticater[name]
 	return a, ok
 }
+
+// RegisterHook registeres a hook.
+// The name of the hook is used as an identifier and must be unique.
+// You can savely use it in parallel.
+func RegisterHook(h Hook, name string) error {
+	knownHooksMutex.Lock()
+	defer knownHooksMutex.Unlock()
+
+	_, ok := knownHooks[name]
+	if ok {
+		return AlreadyRegisteredError("Hook already registered")
+	}
+	knownHooks[name] = h
+	return nil
+}
+
+// GetHook returns a hook.
+// The bool indicates whether it existed. You can only use it if the bool is true.
+func GetHook(name string) (Hook, bool) {
+	knownHooksMutex.RLock()
+	defer knownHooksMutex.RUnlock()
+	h, ok := knownHooks[name]
+	return h, ok
+}
+
+// RegisterFormatter registeres a formatter.
+// The name of the formatter is used as an identifier and must be unique.
+// You can savely use it in parallel.
+func RegisterFormatter(f Formatter, name string) error {
+	knownFormattersMutex.Lock()
+	defer knownFormattersMutex.Unlock()
+
+	_, ok := knownFormatters[name]
+	if ok {
+		return AlreadyRegisteredError("Formatter already registered")
+	}
+	knownFormatters[name] = f
+	return nil
+}
+
+// GetFormatter returns a formatter.
+// The bool indicates whether it existed. You can only use it if the bool is true.
+func GetFormatter(name string) (Formatter, bool) {
+	knownFormattersMutex.RLock()
+	defer knownFormattersMutex.RUnlock()
+	f, ok := knownFormatters[name]
+	return f, ok
+}
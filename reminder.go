@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// reminderCheckInterval is the interval in which due reminders are sent.
+const reminderCheckInterval = 5 * time.Minute
+
+// ReminderNotification is sent as JSON to the reminder webhook before the deadline of a poll is reached.
+type ReminderNotification struct {
+	Poll     string
+	Creator  string
+	Deadline time.Time
+	Answers  int
+}
+
+var reminderClient = http.Client{Timeout: 10 * time.Second}
+
+// sendReminder sends the reminder of a single poll to the webhook.
+func sendReminder(key string, p Poll) error {
+	creator, err := safe.GetPollCreator(key)
+	if err != nil {
+		return err
+	}
+	_, n, _, _, err := safe.GetPollResult(key)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(ReminderNotification{Poll: key, Creator: creator, Deadline: p.Deadline, Answers: len(n)})
+	if err != nil {
+		return err
+	}
+
+	resp, err := reminderClient.Post(config.ReminderWebhook, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sendDueReminders sends all reminders which are due.
+// Reminders which could not be sent are retried until the deadline of the poll is reached.
+func sendDueReminders() {
+	keys, err := safe.GetDueReminders(time.Now())
+	if err != nil {
+		log.Printf("reminder: can not get due reminders: %s", err.Error())
+		return
+	}
+
+	for _, key := range keys {
+		b, err := safe.GetPollConfig(key)
+		if err != nil {
+			log.Printf("reminder: can not load poll %s: %s", key, err.Error())
+			continue
+		}
+		p, err := LoadPoll(b)
+		if err != nil {
+			log.Printf("reminder: can not load poll %s: %s", key, err.Error())
+			continue
+		}
+
+		if !p.Deadline.IsZero() && p.Deadline.After(time.Now()) {
+			err = sendReminder(key, p)
+			if err != nil {
+				log.Printf("reminder: can not send reminder for %s: %s", key, err.Error())
+				continue
+			}
+		}
+
+		err = safe.SetReminder(key, time.Time{})
+		if err != nil {
+			log.Printf("reminder: can not remove reminder for %s: %s", key, err.Error())
+		}
+	}
+}
+
+// reminderWorker periodically sends due reminders. It never returns.
+func reminderWorker() {
+	t := time.NewTicker(reminderCheckInterval)
+	defer t.Stop()
+	for {
+		sendDueReminders()
+		<-t.C
+	}
+}
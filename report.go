@@ -0,0 +1,370 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Top-Ranger/pollgo/registry"
+)
+
+// reportMaxLength is the maximum length of the reason of a report in runes.
+const reportMaxLength = 1000
+
+// reportMaxPerPoll is the maximum number of stored reports per poll. Further reports are accepted, but not stored.
+const reportMaxPerPoll = 100
+
+type moderationReport struct {
+	Reason string
+	Time   string
+}
+
+type moderationEntry struct {
+	Key     string
+	Creator string
+	Answers int
+	Hidden  bool
+	Locked  bool
+	Reports []moderationReport
+}
+
+type moderationTemplateStruct struct {
+	User        string
+	SSO         bool // no user / password is needed
+	Entries     []moderationEntry
+	CSRF        string
+	Translation Translation
+}
+
+var moderationLoginTemplate = template.Must(template.New("moderationlogin").Parse(`
+<h1>{{.Translation.Moderation}}</h1>
+<form method="POST">
+  <input type="hidden" name="csrf" value="{{.CSRF}}">
+  <table style="border: none;">
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="user">{{.Translation.Username}}: </label></td>
+      <td style="border: none;"><input type="text" id="user" name="user" maxlength="500" required></td>
+    </tr>
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="pw">{{.Translation.Password}}: </label></td>
+      <td style="border: none;"><input type="password" id="pw" name="pw" maxlength="500" required></td>
+    </tr>
+  </table>
+  <p><input type="submit" value="{{.Translation.Login}}"></p>
+</form>
+`))
+
+var moderationTemplate = template.Must(template.New("moderation").Parse(`
+<h1>{{.Translation.Moderation}} ({{.User}})</h1>
+{{if .Entries}}
+<form method="POST">
+<table>
+<thead>
+<tr>
+<th></th>
+<th>{{.Translation.Poll}}</th>
+<th>{{.Translation.Answers}}</th>
+<th>{{.Translation.Reports}}</th>
+</tr>
+</thead>
+<tbody>
+{{range .Entries}}
+<tr>
+<td><input type="radio" name="poll" value="{{.Key}}" aria-label="{{.Key}}" required></td>
+<td><a href="/{{.Key}}" target="_blank">{{.Key}}</a>{{if .Creator}} ({{.Creator}}){{end}}{{if .Hidden}} <em>[{{$.Translation.PollHiddenState}}]</em>{{end}}{{if .Locked}} <em>[{{$.Translation.PollLockedState}}]</em>{{end}}</td>
+<td class="centre">{{.Answers}}</td>
+<td><ul>{{range .Reports}}<li>{{.Time}}: {{if .Reason}}{{.Reason}}{{else}}<em>[{{$.Translation.Unknown}}]</em>{{end}}</li>{{end}}</ul></td>
+</tr>
+{{end}}
+</tbody>
+</table>
+{{template "action" .}}
+</form>
+{{else}}
+<p>{{.Translation.NoReports}}</p>
+{{end}}
+<h2>{{.Translation.ModerationOtherPoll}}</h2>
+<form method="POST">
+<p><label for="other_poll">{{.Translation.Poll}}: </label><input type="text" id="other_poll" name="poll" maxlength="600" required></p>
+{{template "action" .}}
+</form>
+{{define "action"}}
+<input type="hidden" name="csrf" value="{{$.CSRF}}">
+<p><select name="action" aria-label="{{$.Translation.Moderation}}" required>
+  <option value="lock">{{$.Translation.ModerationLock}}</option>
+  <option value="hide">{{$.Translation.ModerationHide}}</option>
+  <option value="dismiss">{{$.Translation.ModerationDismiss}}</option>
+  <option value="delete">{{$.Translation.ModerationDelete}}</option>
+</select></p>
+{{if not $.SSO}}
+<table style="border: none;">
+  <tr style="border: none; background-color: inherit;">
+    <td style="border: none;"><label>{{$.Translation.Username}}: <input type="text" name="user" maxlength="500" value="{{$.User}}" required></label></td>
+  </tr>
+  <tr style="border: none; background-color: inherit;">
+    <td style="border: none;"><label>{{$.Translation.Password}}: <input type="password" name="pw" maxlength="500" required></label></td>
+  </tr>
+</table>
+{{end}}
+<p><input type="submit" value="{{$.Translation.Submit}}"></p>
+{{end}}
+`))
+
+// reportsEnabled returns whether polls can be reported. This requires at least one moderator.
+func reportsEnabled() bool {
+	return config.AuthenticationEnabled && len(config.Moderators) > 0
+}
+
+// handleReport stores an abuse report ('report=true') for a poll.
+func handleReport(rw http.ResponseWriter, r *http.Request, key string) {
+	tl := GetDefaultTranslation()
+
+	if r.Form.Get("dsgvo") == "" {
+		rw.WriteHeader(http.StatusForbidden)
+		t := textTemplateStruct{"403 Forbidden", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	reason := strings.TrimSpace(r.Form.Get("reason"))
+	if utf8.RuneCountInString(reason) > reportMaxLength {
+		rw.WriteHeader(http.StatusBadRequest)
+		t := textTemplateStruct{"400 Bad Request", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+
+	reasons, _, err := safe.GetReports(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	if len(reasons) < reportMaxPerPoll {
+		err = safe.SaveReport(key, reason)
+		if errors.Is(err, registry.ErrPollNotAvailable) {
+			rw.WriteHeader(http.StatusGone)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.PollIsDeleted)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		reportSecurityEvent(SecurityEvent{Type: SecurityEventPollReported, Poll: key, Details: reason})
+	}
+
+	text := fmt.Sprintf(`<p>%s</p><p><a href="/%s">%s</a></p>`, template.HTMLEscapeString(tl.ReportReceived), template.HTMLEscapeString(key), template.HTMLEscapeString(tl.BackToPoll))
+	t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
+
+// errUnknownModerationAction is returned by moderate for unknown actions.
+var errUnknownModerationAction = errors.New("unknown action")
+
+// moderate applies a moderation action to a poll.
+// Locking and hiding keep the reports, dismissing removes them and restores the poll.
+func moderate(key, action string) error {
+	c, err := safe.GetPollConfig(key)
+	if err != nil {
+		return err
+	}
+	p, err := LoadPoll(c)
+	if err != nil {
+		return err
+	}
+	if !p.initialised || p.Deleted {
+		return registry.ErrPollNotAvailable
+	}
+
+	switch action {
+	case "lock":
+		p.Locked = true
+	case "hide":
+		p.Hidden = true
+	case "dismiss":
+		p.Hidden = false
+		p.Locked = false
+		err = safe.DeleteReports(key)
+		if err != nil {
+			return err
+		}
+	case "delete":
+		return p.deletePoll(key)
+	default:
+		return fmt.Errorf("%w '%s'", errUnknownModerationAction, action)
+	}
+
+	b, err := p.ExportPoll()
+	if err != nil {
+		return err
+	}
+	return safe.SavePollConfig(key, b)
+}
+
+func moderationHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	tl := GetDefaultTranslation()
+
+	method := r.Method
+	if method == http.MethodGet {
+		// The login form is not needed with a login session
+		if sessionUser(r) != "" {
+			method = http.MethodPost
+		} else if oidcEnabled() {
+			http.Redirect(rw, r, oidcLoginURL(r.URL.Path), http.StatusSeeOther)
+			return
+		}
+	}
+
+	switch method {
+	case http.MethodGet:
+		buf := bytes.Buffer{}
+		err := moderationLoginTemplate.Execute(&buf, loginTemplateStruct{CSRF: csrfToken(rw, r), Translation: tl})
+		if err != nil {
+			log.Printf("moderation: %s", err.Error())
+		}
+		t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	case http.MethodPost:
+		err := r.ParseForm()
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		// Requests with a login session are sent as GET
+		if r.Method == http.MethodPost && !checkCSRF(rw, r) {
+			return
+		}
+
+		user, correct, err := authenticateRequest(r)
+		if errors.Is(err, ErrLoginLocked) {
+			rw.Header().Set("Retry-After", strconv.Itoa(config.LoginLockoutMinutes*60))
+			rw.WriteHeader(http.StatusTooManyRequests)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.LoginLocked)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if !correct {
+			if config.LogFailedLogin {
+				log.Printf("Failed authentication from %s", GetRealIP(r))
+			}
+			rw.WriteHeader(http.StatusForbidden)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.AuthentificationFailure)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if !slices.Contains(config.Moderators, user) {
+			rw.WriteHeader(http.StatusForbidden)
+			t := textTemplateStruct{"403 Forbidden", tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		// Actions must be posted, so following a link can not change polls
+		if action := r.Form.Get("action"); action != "" && r.Method == http.MethodPost {
+			key := r.Form.Get("poll")
+			err := moderate(key, action)
+			if err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, errUnknownModerationAction) || errors.Is(err, registry.ErrPollNotAvailable) {
+					status = http.StatusBadRequest
+				}
+				rw.WriteHeader(status)
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(errorText(status, err))), tl, config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+			log.Printf("moderation: %s applied '%s' to %s", user, action, key)
+		}
+
+		keys, err := safe.GetReportedPolls()
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		td := moderationTemplateStruct{
+			User:        user,
+			SSO:         sessionUser(r) != "",
+			CSRF:        csrfToken(rw, r),
+			Entries:     make([]moderationEntry, 0, len(keys)),
+			Translation: tl,
+		}
+		for i := range keys {
+			e := moderationEntry{Key: keys[i]}
+			c, err := safe.GetPollConfig(keys[i])
+			if err == nil {
+				p, err := LoadPoll(c)
+				if err == nil {
+					e.Hidden = p.Hidden
+					e.Locked = p.Locked
+				}
+			}
+			e.Creator, _ = safe.GetPollCreator(keys[i])
+			results, _, _, _, err := safe.GetPollResult(keys[i])
+			if err == nil {
+				e.Answers = len(results)
+			}
+			reasons, times, err := safe.GetReports(keys[i])
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+				textTemplate.Execute(rw, t)
+				return
+			}
+			for j := range reasons {
+				e.Reports = append(e.Reports, moderationReport{Reason: reasons[j], Time: FormatTimeDisplay(times[j], config.DateTimeDisplayFormat)})
+			}
+			td.Entries = append(td.Entries, e)
+		}
+
+		buf := bytes.Buffer{}
+		err = moderationTemplate.Execute(&buf, td)
+		if err != nil {
+			log.Printf("moderation: %s", err.Error())
+		}
+		t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	default:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		t := textTemplateStruct{"405 Method Not Allowed", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	}
+}
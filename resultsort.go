@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Visitors can sort the answers of a poll with the 'sort' parameter ('name', 'time' or 'score') and reverse the order with 'order=desc'.
+// Without 'sort', answers are shown in the order of the data safe. Sorting happens on the server, so it works without JavaScript and across result pages.
+
+// Values of the 'sort' parameter.
+const (
+	resultSortName  = "name"
+	resultSortTime  = "time"
+	resultSortScore = "score"
+)
+
+// parseResultSort returns the sort order given by the 'sort' and 'order' parameters. Unknown values keep the order of the data safe.
+func parseResultSort(sortBy, order string) (string, bool) {
+	switch sortBy {
+	case resultSortName, resultSortTime, resultSortScore:
+		return sortBy, order == "desc"
+	default:
+		return "", false
+	}
+}
+
+// resultOrder returns the order of the answers for the given sort order.
+// created is only needed for sorting by submission time, answers without known creation time are sorted first. score contains the score contribution of each answer.
+// Answers which are equal keep the order of the data safe.
+func resultOrder(sortBy string, desc bool, names, ids []string, created map[string]time.Time, score []float64) []int {
+	order := make([]int, len(names))
+	for i := range order {
+		order[i] = i
+	}
+	var less func(a, b int) bool
+	switch sortBy {
+	case resultSortName:
+		less = func(a, b int) bool { return strings.ToLower(names[a]) < strings.ToLower(names[b]) }
+	case resultSortTime:
+		less = func(a, b int) bool { return created[ids[a]].Before(created[ids[b]]) }
+	case resultSortScore:
+		less = func(a, b int) bool { return score[a] < score[b] }
+	default:
+		return order
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if desc {
+			return less(order[j], order[i])
+		}
+		return less(order[i], order[j])
+	})
+	return order
+}
+
+// sortAnswers arranges the answers of the template in the given order. It must be called before summarise.
+func (td *pollTemplateStruct) sortAnswers(order []int) {
+	answers := make([][][]string, len(order))
+	whiteFont := make([][]bool, len(order))
+	names := make([]string, len(order))
+	comments := make([]string, len(order))
+	ids := make([]string, len(order))
+	canEdit := make([]bool, len(order))
+	endorsements := make([]int, len(order))
+	endorsed := make([]bool, len(order))
+	for i, o := range order {
+		answers[i] = td.Answers[o]
+		whiteFont[i] = td.AnswerWhiteFont[o]
+		names[i] = td.Names[o]
+		comments[i] = td.Comments[o]
+		ids[i] = td.IDs[o]
+		canEdit[i] = td.CanEdit[o]
+		endorsements[i] = td.Endorsements[o]
+		endorsed[i] = td.Endorsed[o]
+	}
+	td.Answers = answers
+	td.AnswerWhiteFont = whiteFont
+	td.Names = names
+	td.Comments = comments
+	td.IDs = ids
+	td.CanEdit = canEdit
+	td.Endorsements = endorsements
+	td.Endorsed = endorsed
+}
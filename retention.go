@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+)
+
+// retentionCheckInterval is the interval in which old answers are pseudonymised.
+const retentionCheckInterval = time.Hour
+
+// answerPseudonym returns the pseudonym of an answer.
+// It is derived from the poll and answer ID, so the same participant keeps the same pseudonym.
+func answerPseudonym(pollID, answerID string) string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{pollID, answerID}, "\n")))
+	return strings.Join([]string{GetDefaultTranslation().Participant, hex.EncodeToString(hash[:3])}, " ")
+}
+
+// pseudonymiseOldAnswers replaces names and comments of all answers older than PseudonymiseAfterDays.
+func pseudonymiseOldAnswers() {
+	before := time.Now().AddDate(0, 0, -config.PseudonymiseAfterDays)
+	n, err := safe.PseudonymiseAnswers(before, answerPseudonym)
+	if err != nil {
+		log.Printf("retention: can not pseudonymise answers: %s", err.Error())
+	}
+	if n != 0 {
+		log.Printf("retention: pseudonymised %d answers", n)
+	}
+}
+
+// retentionWorker periodically pseudonymises old answers. It never returns.
+func retentionWorker() {
+	t := time.NewTicker(retentionCheckInterval)
+	defer t.Stop()
+	for {
+		pseudonymiseOldAnswers()
+		<-t.C
+	}
+}
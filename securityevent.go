@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Types of security events.
+const (
+	SecurityEventLoginLocked        = "login_locked"
+	SecurityEventLoginFailures      = "login_failures"
+	SecurityEventLargePollDeleted   = "large_poll_deleted"
+	SecurityEventMassAnswerDeletion = "mass_answer_deletion"
+	SecurityEventPollReported       = "poll_reported"
+)
+
+// massDeletionWindow is the time window in which answer deletions of a poll are counted.
+const massDeletionWindow = time.Hour
+
+// loginFailureWindow is the time window in which failed logins of a user or an IP are counted.
+const loginFailureWindow = time.Hour
+
+// SecurityEvent represents a suspicious event. It is sent as JSON to the security webhook.
+type SecurityEvent struct {
+	Type    string
+	Time    time.Time
+	Poll    string `json:",omitempty"`
+	User    string `json:",omitempty"`
+	IP      string `json:",omitempty"`
+	Details string `json:",omitempty"`
+}
+
+// reportSecurityEvent writes the event to the log and, if 'SecurityWebhook' is set, posts it to the webhook. It does not block.
+// Integrations which want to react to events in-process should use a registry.Hook.
+func reportSecurityEvent(e SecurityEvent) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	logSecurityNotifier(e)
+	if config.SecurityWebhook != "" {
+		go webhookSecurityNotifier(e)
+	}
+}
+
+// logSecurityNotifier writes the event to the log.
+func logSecurityNotifier(e SecurityEvent) {
+	log.Printf("security event: %s poll=%q user=%q ip=%q %s", e.Type, e.Poll, e.User, e.IP, e.Details)
+}
+
+var securityClient = http.Client{Timeout: 10 * time.Second}
+
+// webhookSecurityNotifier posts the event as JSON to the security webhook.
+func webhookSecurityNotifier(e SecurityEvent) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("security event: can not encode event: %s", err.Error())
+		return
+	}
+
+	resp, err := securityClient.Post(config.SecurityWebhook, "application/json", bytes.NewReader(b))
+	if err != nil {
+		log.Printf("security event: can not send event: %s", err.Error())
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		log.Printf("security event: webhook returned status %d", resp.StatusCode)
+	}
+}
+
+var answerDeletions = make(map[string][]time.Time)
+var answerDeletionsMutex sync.Mutex
+
+// countAnswerDeletion records the deletion of an answer and reports a security event
+// if 'SecurityMassDeletionAnswers' answers of the poll were deleted within an hour.
+func countAnswerDeletion(r *http.Request, key string) {
+	if config.SecurityMassDeletionAnswers <= 0 {
+		return
+	}
+
+	now := time.Now()
+	answerDeletionsMutex.Lock()
+	defer answerDeletionsMutex.Unlock()
+
+	// Remove old deletions of all polls so the map does not grow indefinitely
+	for k := range answerDeletions {
+		d := answerDeletions[k]
+		i := 0
+		for i < len(d) && now.Sub(d[i]) > massDeletionWindow {
+			i++
+		}
+		if i == len(d) {
+			delete(answerDeletions, k)
+		} else {
+			answerDeletions[k] = d[i:]
+		}
+	}
+
+	d := append(answerDeletions[key], now)
+	if len(d) >= config.SecurityMassDeletionAnswers {
+		reportSecurityEvent(SecurityEvent{Type: SecurityEventMassAnswerDeletion, Time: now, Poll: key, IP: GetRealIP(r), Details: fmt.Sprintf("%d answers deleted within %s", len(d), massDeletionWindow)})
+		d = nil
+	}
+	answerDeletions[key] = d
+}
+
+var loginFailures = make(map[string][]time.Time)
+var loginFailuresMutex sync.Mutex
+
+// countLoginFailure records a failed login and reports a security event if 'SecurityLoginFailures' logins
+// of the user or the IP failed within an hour. It is independent of the lockout through 'LoginMaxFailures'.
+func countLoginFailure(r *http.Request, user string) {
+	if config.SecurityLoginFailures <= 0 {
+		return
+	}
+
+	now := time.Now()
+	loginFailuresMutex.Lock()
+	defer loginFailuresMutex.Unlock()
+
+	// Remove old failures so the map does not grow indefinitely
+	for k := range loginFailures {
+		f := loginFailures[k]
+		i := 0
+		for i < len(f) && now.Sub(f[i]) > loginFailureWindow {
+			i++
+		}
+		if i == len(f) {
+			delete(loginFailures, k)
+		} else {
+			loginFailures[k] = f[i:]
+		}
+	}
+
+	for _, k := range loginThrottleKeys(r, user) {
+		f := append(loginFailures[k], now)
+		if len(f) >= config.SecurityLoginFailures {
+			reportSecurityEvent(SecurityEvent{Type: SecurityEventLoginFailures, Time: now, User: user, IP: GetRealIP(r), Details: fmt.Sprintf("%d failed logins of %s within %s", len(f), k, loginFailureWindow)})
+			f = nil
+		}
+		loginFailures[k] = f
+	}
+}
+
+// reportLargePollDeleted reports the deletion of a poll if it has at least 'SecurityLargePollAnswers' answers.
+// It must be called before the poll is deleted.
+func reportLargePollDeleted(r *http.Request, key string) {
+	if config.SecurityLargePollAnswers <= 0 {
+		return
+	}
+	_, n, _, _, err := safe.GetPollResult(key)
+	if err == nil && len(n) >= config.SecurityLargePollAnswers {
+		creator, _ := safe.GetPollCreator(key)
+		reportSecurityEvent(SecurityEvent{Type: SecurityEventLargePollDeleted, Poll: key, User: creator, IP: GetRealIP(r), Details: fmt.Sprintf("poll with %d answers deleted", len(n))})
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadSeedPolls loads all poll exports ('*.json') in 'SeedPath' into the data safe.
+// The file name (without extension) is used as the poll name.
+// Existing polls are never overwritten, deleted polls are restored.
+func loadSeedPolls() {
+	files, err := filepath.Glob(filepath.Join(config.SeedPath, "*.json"))
+	if err != nil {
+		log.Printf("seed: can not list %s: %s", config.SeedPath, err.Error())
+		return
+	}
+
+	loaded := 0
+	for _, f := range files {
+		key := strings.TrimSuffix(filepath.Base(f), ".json")
+		key = strings.TrimLeft(strings.Join([]string{config.ServerPath, "/", key}, ""), "/")
+		ok, err := loadSeedPoll(key, f)
+		if err != nil {
+			log.Printf("seed: can not load %s: %s", f, err.Error())
+			continue
+		}
+		if ok {
+			loaded++
+		}
+	}
+	log.Printf("seed: loaded %d of %d polls", loaded, len(files))
+}
+
+// loadSeedPoll saves the poll export at path as key if the poll is missing.
+// It returns whether the poll was saved.
+func loadSeedPoll(key, path string) (bool, error) {
+	c, err := safe.GetPollConfig(key)
+	if err != nil {
+		return false, err
+	}
+	existing, err := LoadPoll(c)
+	if err != nil {
+		return false, err
+	}
+	if existing.initialised && !existing.Deleted {
+		return false, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	p, err := LoadPoll(b)
+	if err != nil {
+		return false, err
+	}
+	if !p.initialised || !VerifyPollConfig(p) {
+		return false, errors.New("invalid poll configuration")
+	}
+	p.Deleted = false
+	b, err = p.ExportPoll()
+	if err != nil {
+		return false, err
+	}
+	err = safe.SavePollConfig(key, b)
+	if err != nil {
+		return false, fmt.Errorf("can not save poll %s: %w", key, err)
+	}
+	hookPollCreated(key, "")
+	return true, nil
+}
@@ -19,21 +19,25 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"html/template"
-	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 var serverMutex sync.Mutex
 var serverStarted bool
 var server http.Server
+var redirectServer *http.Server // serves 'TLSRedirectAddress', nil if not used
 var rootPath string
 
 var dsgvo []byte
@@ -41,7 +45,6 @@ var impressum []byte
 
 //go:embed static font js css
 var cachedFiles embed.FS
-var etagCompare string
 var cssTemplates *template.Template
 
 var robottxt = []byte(`User-agent: *
@@ -50,14 +53,14 @@ Disallow: /`)
 func init() {
 	var err error
 
-	cssTemplates, err = template.ParseFS(cachedFiles, "css/*")
+	cssTemplates, err = template.New("css").Funcs(templateFunctions).ParseFS(cachedFiles, "css/*")
 	if err != nil {
 		panic(err)
 	}
 }
 
 const startpage = `
-<h1>PollGo!</h1>
+<h1>%s</h1>
 
 <script>
 function toRandomPage() {
@@ -69,7 +72,7 @@ function toRandomPage() {
   }
   var id = btoa(String.fromCharCode.apply(null, b));
   id = id.replace(new RegExp("/", "g"), "-")
-  target = target + id
+  target = target + id + "?create=true"
   window.location.href = target;
 }
 </script>
@@ -125,10 +128,42 @@ func initialiseServer() error {
 		return nil
 	}
 	server = http.Server{Addr: config.Address}
+	server.RegisterOnShutdown(stopLiveUpdates)
 
 	// Do setup
 	rootPath = strings.Join([]string{config.ServerPath, "/"}, "")
 
+	// Content filter
+	if config.PathContentFilter != "" {
+		err := loadContentFilter(config.PathContentFilter)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Extra HTML for all pages - must be loaded before pages are pre-rendered
+	if config.PathExtraHead != "" {
+		b, err := os.ReadFile(config.PathExtraHead)
+		if err != nil {
+			return err
+		}
+		extraHead = template.HTML(b)
+	}
+	if config.PathExtraFooter != "" {
+		b, err := os.ReadFile(config.PathExtraFooter)
+		if err != nil {
+			return err
+		}
+		extraFooter = template.HTML(b)
+	}
+
+	// Uploads
+	if config.UploadPath != "" {
+		allowUploadedImages()
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/upload"}, ""), uploadRateLimitHandle(uploadHandle))
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/uploads/"}, ""), uploadedImageHandle)
+	}
+
 	// DSGVO
 	b, err := os.ReadFile(config.PathDSGVO)
 	if err != nil {
@@ -156,99 +191,125 @@ func initialiseServer() error {
 		rw.Write(impressum)
 	})
 
-	etag := fmt.Sprint("\"", strconv.FormatInt(time.Now().Unix(), 10), "\"")
-	etagCompare := strings.TrimSuffix(etag, "\"")
-	etagCompareApache := strings.Join([]string{etagCompare, "-"}, "")       // Dirty hack for apache2, who appends -gzip inside the quotes if the file is compressed, thus preventing If-None-Match matching the ETag
-	etagCompareCaddy := strings.Join([]string{"W/", etagCompare, "\""}, "") // Dirty hack for caddy, who appends W/ before the quotes if the file is compressed, thus preventing If-None-Match matching the ETag
-
-	staticHandle := func(rw http.ResponseWriter, r *http.Request) {
-		// Check for ETag
-		v, ok := r.Header["If-None-Match"]
-		if ok {
-			for i := range v {
-				if v[i] == etag || v[i] == etagCompareCaddy || strings.HasPrefix(v[i], etagCompareApache) {
-					rw.WriteHeader(http.StatusNotModified)
-					return
-				}
-			}
-		}
-
-		// Send file if existing in cache
-		path := r.URL.Path
-		path = strings.TrimPrefix(path, config.ServerPath)
-		path = strings.TrimPrefix(path, "/")
-
-		if strings.HasPrefix(path, "css/") {
-			// special case
-			path = strings.TrimPrefix(path, "css/")
-			rw.Header().Set("ETag", etag)
-			rw.Header().Set("Cache-Control", "public, max-age=43200")
-			rw.Header().Set("Content-Type", "text/css")
-			err := cssTemplates.ExecuteTemplate(rw, path, struct{ ServerPath string }{config.ServerPath})
-			if err != nil {
-				rw.WriteHeader(http.StatusNotFound)
-				log.Println("server:", err)
-			}
-			return
-		}
-
-		data, err := cachedFiles.Open(path)
-		if err != nil {
-			rw.WriteHeader(http.StatusNotFound)
-		} else {
-			rw.Header().Set("ETag", etag)
-			rw.Header().Set("Cache-Control", "public, max-age=43200")
-			switch {
-			case strings.HasSuffix(path, ".svg"):
-				rw.Header().Set("Content-Type", "image/svg+xml")
-			case strings.HasSuffix(path, ".ttf"):
-				rw.Header().Set("Content-Type", "application/x-font-truetype")
-			case strings.HasSuffix(path, ".js"):
-				rw.Header().Set("Content-Type", "application/javascript")
-			default:
-				rw.Header().Set("Content-Type", "text/plain")
-			}
-			io.Copy(rw, data)
-		}
+	err = loadAssets()
+	if err != nil {
+		return err
 	}
 
 	http.HandleFunc(strings.Join([]string{config.ServerPath, "/css/"}, ""), staticHandle)
 	http.HandleFunc(strings.Join([]string{config.ServerPath, "/static/"}, ""), staticHandle)
 	http.HandleFunc(strings.Join([]string{config.ServerPath, "/font/"}, ""), staticHandle)
 	http.HandleFunc(strings.Join([]string{config.ServerPath, "/js/"}, ""), staticHandle)
+	if config.CustomStaticPath != "" {
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/custom/"}, ""), customStaticHandle)
+	}
 
 	http.HandleFunc(strings.Join([]string{config.ServerPath, "/favicon.ico"}, ""), func(rw http.ResponseWriter, r *http.Request) {
-		// Check for ETag
-		v, ok := r.Header["If-None-Match"]
-		if ok {
-			for i := range v {
-				if v[i] == etag || v[i] == etagCompareCaddy || strings.HasPrefix(v[i], etagCompareApache) {
-					rw.WriteHeader(http.StatusNotModified)
-					return
-				}
-			}
+		if config.FaviconURL != "" {
+			http.Redirect(rw, r, config.FaviconURL, http.StatusFound)
+			return
 		}
 
-		f, err := cachedFiles.ReadFile("static/favicon.ico")
-
-		if err != nil {
+		a, ok := assets["static/favicon.ico"]
+		if !ok {
 			rw.WriteHeader(http.StatusNotFound)
 			return
 		}
 
-		rw.Write(f)
+		if etagMatches(r, a.etag) {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		rw.Header().Set("ETag", a.etag)
+		rw.Write(a.data)
 	})
 
 	// robots.txt
+	if config.PathRobotsTxt != "" {
+		robottxt, err = os.ReadFile(config.PathRobotsTxt)
+		if err != nil {
+			return err
+		}
+	}
 	http.HandleFunc(strings.Join([]string{config.ServerPath, "/robots.txt"}, ""), func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		rw.Write(robottxt)
 	})
 
-	http.HandleFunc("/", rootHandle)
+	// sitemap.xml
+	if len(config.SitemapPolls) != 0 {
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/sitemap.xml"}, ""), sitemapHandle)
+	}
+
+	// API tokens
+	err = initialiseAPITokens()
+	if err != nil {
+		return err
+	}
+
+	// Metrics
+	if config.EnableMetrics {
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/metrics"}, ""), metricsHandle)
+	}
+
+	// Health
+	http.HandleFunc(strings.Join([]string{config.ServerPath, "/health"}, ""), healthHandle)
+
+	// OpenAPI
+	openAPIDocument, err = buildOpenAPIDocument()
+	if err != nil {
+		return err
+	}
+	http.HandleFunc(strings.Join([]string{config.ServerPath, "/api/v1/openapi.json"}, ""), openAPIHandle)
+
+	// Poll API
+	http.HandleFunc(strings.Join([]string{config.ServerPath, apiPollsPath}, ""), apiRateLimitHandle(apiHandle))
+
+	// Preview
+	http.HandleFunc(strings.Join([]string{config.ServerPath, "/preview"}, ""), previewHandle)
+
+	// Registration
+	if config.EnableRegistration {
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/register.html"}, ""), registerHandle)
+	}
+
+	// Password reset
+	if config.EnablePasswordReset {
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/resetpassword.html"}, ""), passwordResetHandle)
+	}
+
+	// Login sessions
+	if sessionsEnabled() {
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/login"}, ""), loginRateLimitHandle(loginHandle))
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/logout"}, ""), logoutHandle)
+	}
+	if oidcEnabled() {
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/login/oidc"}, ""), oidcLoginHandle)
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/login/oidc/callback"}, ""), oidcCallbackHandle)
+	}
+
+	// My polls
+	http.HandleFunc(strings.Join([]string{config.ServerPath, "/mypolls.html"}, ""), loginRateLimitHandle(myPollsHandle))
+	if reportsEnabled() {
+		http.HandleFunc(strings.Join([]string{config.ServerPath, "/moderation.html"}, ""), loginRateLimitHandle(moderationHandle))
+	}
+
+	http.HandleFunc("/", rateLimitHandle(rootHandle))
+
+	server.Handler = recoverHandler(http.DefaultServeMux)
+	if config.EnableH2C {
+		// Allow HTTP/2 without TLS, e.g. for load balancers talking HTTP/2 to PollGo! directly
+		server.Handler = h2c.NewHandler(server.Handler, &http2.Server{})
+	}
 	return nil
 }
 
 func rootHandle(rw http.ResponseWriter, r *http.Request) {
+	if !checkAPIToken(rw, r) {
+		return
+	}
+
 	// Is this a check password request?
 	if r.Method == http.MethodPut {
 		rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
@@ -256,19 +317,20 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 			err := r.ParseMultipartForm(10000000) // 10 MB
 			if err != nil {
 				rw.WriteHeader(http.StatusInternalServerError)
-				rw.Write([]byte(err.Error()))
+				rw.Write([]byte(internalErrorText(err)))
 				return
 			}
 
-			user, pw := r.Form.Get("user"), r.Form.Get("pw")
-			if len(user) == 0 || len(pw) == 0 {
-				rw.WriteHeader(http.StatusForbidden)
+			_, correct, err := authenticateRequest(r)
+			if errors.Is(err, ErrLoginLocked) {
+				rw.Header().Set("Retry-After", strconv.Itoa(config.LoginLockoutMinutes*60))
+				rw.WriteHeader(http.StatusTooManyRequests)
+				rw.Write([]byte(GetDefaultTranslation().LoginLocked))
 				return
 			}
-			correct, err := authenticater.Authenticate(user, pw)
 			if err != nil {
 				rw.WriteHeader(http.StatusInternalServerError)
-				rw.Write([]byte(err.Error()))
+				rw.Write([]byte(internalErrorText(err)))
 				return
 			}
 			if !correct {
@@ -291,7 +353,16 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 	if r.URL.Path == rootPath || r.URL.Path == config.ServerPath || r.URL.Path == "/" {
 		rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 		tl := GetDefaultTranslation()
-		text := fmt.Sprintf(startpage, template.HTMLEscapeString(tl.CreateNewPollRandom), template.HTMLEscapeString(tl.Starred), template.HTMLEscapeString(tl.FunctionRequiresJavaScript))
+		text := fmt.Sprintf(startpage, template.HTMLEscapeString(config.InstanceName), template.HTMLEscapeString(tl.CreateNewPollRandom), template.HTMLEscapeString(tl.Starred), template.HTMLEscapeString(tl.FunctionRequiresJavaScript))
+		if config.AuthenticationEnabled {
+			text = strings.Join([]string{text, fmt.Sprintf(`<p><a href="%s/mypolls.html">%s</a></p>`, config.ServerPath, template.HTMLEscapeString(tl.MyPolls))}, "\n")
+		}
+		if config.EnableRegistration {
+			text = strings.Join([]string{text, fmt.Sprintf(`<p><a href="%s/register.html">%s</a></p>`, config.ServerPath, template.HTMLEscapeString(tl.Register))}, "\n")
+		}
+		if config.EnablePasswordReset {
+			text = strings.Join([]string{text, fmt.Sprintf(`<p><a href="%s/resetpassword.html">%s</a></p>`, config.ServerPath, template.HTMLEscapeString(tl.ForgotPassword))}, "\n")
+		}
 		t := textTemplateStruct{template.HTML(text), tl, config.ServerPath}
 		textTemplate.Execute(rw, t)
 		return
@@ -317,7 +388,7 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 	c, err := safe.GetPollConfig(key)
 	if err != nil {
 		rw.WriteHeader(http.StatusInternalServerError)
-		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 		textTemplate.Execute(rw, t)
 		return
 	}
@@ -325,7 +396,7 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 	p, err := LoadPoll(c)
 	if err != nil {
 		rw.WriteHeader(http.StatusInternalServerError)
-		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(err.Error())), GetDefaultTranslation(), config.ServerPath}
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), GetDefaultTranslation(), config.ServerPath}
 		textTemplate.Execute(rw, t)
 		return
 	}
@@ -334,7 +405,7 @@ func rootHandle(rw http.ResponseWriter, r *http.Request) {
 
 // RunServer starts the actual server.
 // It does nothing if a server is already started.
-// It will return directly after the server is started.
+// It will return directly after the server is listening.
 func RunServer() {
 	serverMutex.Lock()
 	defer serverMutex.Unlock()
@@ -346,10 +417,39 @@ func RunServer() {
 	if err != nil {
 		log.Panicln("server:", err)
 	}
+	tlsConfig, redirect, err := serverTLSConfig()
+	if err != nil {
+		log.Panicln("server:", err)
+	}
+	ln, err := net.Listen("tcp", config.Address)
+	if err != nil {
+		log.Panicln("server:", err)
+	}
+	if tlsConfig != nil && config.TLSRedirectAddress != "" {
+		redirectLn, err := net.Listen("tcp", config.TLSRedirectAddress)
+		if err != nil {
+			log.Panicln("server:", err)
+		}
+		redirectServer = &http.Server{Addr: config.TLSRedirectAddress, Handler: redirect}
+		log.Println("server: Redirecting HTTP at", config.TLSRedirectAddress)
+		go func() {
+			err := redirectServer.Serve(redirectLn)
+			if err != http.ErrServerClosed {
+				log.Println("server:", err)
+			}
+		}()
+	}
 	log.Println("server: Server starting at", config.Address)
 	serverStarted = true
 	go func() {
-		err = server.ListenAndServe()
+		var err error
+		if tlsConfig != nil {
+			server.TLSConfig = tlsConfig
+			// The certificates are provided by the TLS configuration
+			err = server.ServeTLS(ln, "", "")
+		} else {
+			err = server.Serve(ln)
+		}
 		if err != http.ErrServerClosed {
 			log.Println("server:", err)
 		}
@@ -365,6 +465,13 @@ func StopServer() {
 	if !serverStarted {
 		return
 	}
+	if redirectServer != nil {
+		err := redirectServer.Shutdown(context.Background())
+		if err != nil {
+			log.Println("server:", err)
+		}
+		redirectServer = nil
+	}
 	err := server.Shutdown(context.Background())
 	if err == nil {
 		log.Println("server: stopped")
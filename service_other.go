@@ -0,0 +1,48 @@
+//go:build !windows
+
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// isService returns whether PollGo! runs as a Windows service.
+func isService() bool {
+	return false
+}
+
+// prepareService does nothing outside of Windows.
+func prepareService(configPath string) string {
+	return configPath
+}
+
+// waitForShutdown blocks until an interrupt is received.
+func waitForShutdown() {
+	s := make(chan os.Signal, 1)
+	signal.Notify(s, os.Interrupt, syscall.SIGTERM)
+	<-s
+}
+
+// serviceCommand reports that services are only supported on Windows.
+func serviceCommand(args []string) int {
+	fmt.Fprintln(os.Stderr, "pollgo service is only supported on Windows")
+	return 1
+}
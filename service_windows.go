@@ -0,0 +1,230 @@
+//go:build windows
+
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// serviceName is the name of the Windows service.
+const serviceName = "PollGo"
+
+const serviceCommandUsage = `usage: pollgo service [-config path] install|remove|start|stop
+
+Manages the Windows service of PollGo!. The configuration given at installation is used by the service.
+`
+
+// eventLogWriter writes log output to the Windows event log.
+type eventLogWriter struct {
+	l *eventlog.Log
+}
+
+func (e eventLogWriter) Write(p []byte) (int, error) {
+	err := e.l.Info(1, strings.TrimRight(string(p), "\r\n"))
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// isService returns whether PollGo! runs as a Windows service.
+func isService() bool {
+	s, err := svc.IsWindowsService()
+	if err != nil {
+		log.Printf("service: can not determine whether running as service: %s", err.Error())
+		return false
+	}
+	return s
+}
+
+// prepareService redirects the log to the event log and changes the working directory to the directory of the configuration,
+// as services are started in the system directory. It returns the absolute path of the configuration.
+func prepareService(configPath string) string {
+	l, err := eventlog.Open(serviceName)
+	if err == nil {
+		log.SetOutput(eventLogWriter{l})
+		log.SetFlags(0)
+	}
+	abs, err := filepath.Abs(configPath)
+	if err != nil {
+		log.Printf("service: can not resolve configuration path: %s", err.Error())
+		return configPath
+	}
+	err = os.Chdir(filepath.Dir(abs))
+	if err != nil {
+		log.Printf("service: can not change working directory: %s", err.Error())
+	}
+	return abs
+}
+
+type serviceHandler struct{}
+
+// Execute reports the service as running and returns once the service should stop.
+func (serviceHandler) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for c := range r {
+		switch c.Cmd {
+		case svc.Interrogate:
+			changes <- c.CurrentStatus
+		case svc.Stop, svc.Shutdown:
+			changes <- svc.Status{State: svc.StopPending}
+			return false, 0
+		}
+	}
+	return false, 0
+}
+
+// waitForShutdown blocks until the service is stopped or an interrupt is received.
+func waitForShutdown() {
+	if isService() {
+		err := svc.Run(serviceName, serviceHandler{})
+		if err != nil {
+			log.Printf("service: %s", err.Error())
+		}
+		return
+	}
+
+	s := make(chan os.Signal, 1)
+	signal.Notify(s, os.Interrupt, syscall.SIGTERM)
+	<-s
+}
+
+// serviceCommand runs the "service" subcommand with the remaining arguments and returns the exit code.
+func serviceCommand(args []string) int {
+	fs := flag.NewFlagSet("service", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, serviceCommandUsage) }
+	configPath := fs.String("config", "./config.json", "Path to json config for PollGo!")
+	err := fs.Parse(args)
+	if err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	switch fs.Arg(0) {
+	case "install":
+		err = installService(*configPath)
+	case "remove":
+		err = removeService()
+	case "start":
+		err = controlService(func(s *mgr.Service) error { return s.Start() })
+	case "stop":
+		err = controlService(func(s *mgr.Service) error {
+			_, err := s.Control(svc.Stop)
+			return err
+		})
+	default:
+		fs.Usage()
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func installService(configPath string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	configPath, err = filepath.Abs(configPath)
+	if err != nil {
+		return err
+	}
+	_, err = loadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("can not load configuration: %w", err)
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.CreateService(serviceName, exe, mgr.Config{DisplayName: "PollGo!", Description: "PollGo! poll server", StartType: mgr.StartAutomatic}, "-config", configPath)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	err = eventlog.InstallAsEventCreate(serviceName, eventlog.Error|eventlog.Warning|eventlog.Info)
+	if err != nil {
+		s.Delete()
+		return fmt.Errorf("can not install event log source: %w", err)
+	}
+	return nil
+}
+
+func removeService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	err = s.Delete()
+	if err != nil {
+		return err
+	}
+	return eventlog.Remove(serviceName)
+}
+
+func controlService(f func(s *mgr.Service) error) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	err = f(s)
+	if err != nil {
+		return err
+	}
+	// Give the service manager some time so that the state is visible directly after the command
+	time.Sleep(time.Second)
+	return nil
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// If authentication is enabled, users can log in once at '/login' (or through OpenID Connect, see oidc.go) instead of entering user / password in every form.
+// The user is kept in a signed cookie, so no session state is stored on the server. Forms still accept user / password if there is no session.
+
+// sessionCookieName is the name of the cookie holding the login session.
+const sessionCookieName = "pollgo_session"
+
+type loginTemplateStruct struct {
+	Return      string
+	User        string // user of the current login session
+	CSRF        string
+	Translation Translation
+	ServerPath  string
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`
+<h1>{{.Translation.Login}}</h1>
+{{if .User}}<form method="POST" action="{{.ServerPath}}/logout"><p>{{printf .Translation.SSOLoggedInAs .User}} <input type="hidden" name="csrf" value="{{.CSRF}}"><input type="hidden" name="return" value="{{.Return}}"><input type="submit" value="{{.Translation.SSOLogout}}"></p></form>{{end}}
+<form method="POST">
+  <input type="hidden" name="csrf" value="{{.CSRF}}">
+  <input type="hidden" name="return" value="{{.Return}}">
+  <table style="border: none;">
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="user">{{.Translation.Username}}: </label></td>
+      <td style="border: none;"><input type="text" id="user" name="user" maxlength="500" autocomplete="username" required></td>
+    </tr>
+    <tr style="border: none; background-color: inherit;">
+      <td style="border: none;"><label for="pw">{{.Translation.Password}}: </label></td>
+      <td style="border: none;"><input type="password" id="pw" name="pw" maxlength="500" autocomplete="current-password" required></td>
+    </tr>
+  </table>
+  <p><input type="submit" value="{{.Translation.Login}}"></p>
+</form>
+`))
+
+// sessionsEnabled returns whether users can log in with a session.
+func sessionsEnabled() bool {
+	return config.AuthenticationEnabled
+}
+
+// sessionSignature signs the user and the expiry of a login session.
+// The key is derived from the token secret, so sessions can not be confused with other signed tokens.
+func sessionSignature(user string, expires int64) []byte {
+	key := hmac.New(sha256.New, getTokenSecret())
+	key.Write([]byte("session"))
+	mac := hmac.New(sha256.New, key.Sum(nil))
+	fmt.Fprintf(mac, "%s\x00%d", user, expires)
+	return mac.Sum(nil)
+}
+
+// setSessionCookie starts a login session of the user.
+func setSessionCookie(rw http.ResponseWriter, user string) {
+	expires := time.Now().Add(time.Duration(config.SessionHours) * time.Hour)
+	cookie := http.Cookie{}
+	cookie.Name = sessionCookieName
+	cookie.Value = strings.Join([]string{base64.RawURLEncoding.EncodeToString([]byte(user)), strconv.FormatInt(expires.Unix(), 10), base64.RawURLEncoding.EncodeToString(sessionSignature(user, expires.Unix()))}, ".")
+	cookie.Expires = expires
+	cookie.Path = "/"
+	cookie.SameSite = http.SameSiteLaxMode
+	cookie.HttpOnly = true
+	cookie.Secure = !config.InsecureAllowCookiesOverHTTP
+	http.SetCookie(rw, &cookie)
+}
+
+// sessionUser returns the user of the login session of the request or an empty string if there is no valid session.
+func sessionUser(r *http.Request) string {
+	if !sessionsEnabled() {
+		return ""
+	}
+	c, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(c.Value, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	u, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ""
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return ""
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ""
+	}
+	if !hmac.Equal(sig, sessionSignature(string(u), expires)) {
+		return ""
+	}
+	return string(u)
+}
+
+// requestUser returns the user making the request: the user of the login session if there is one, otherwise the user given in the form.
+// The user must be authenticated before.
+func requestUser(r *http.Request) string {
+	if user := sessionUser(r); user != "" {
+		return user
+	}
+	return r.Form.Get("user")
+}
+
+// loginReturnPath returns the local path the visitor is sent to after the login. Other targets are replaced by the start page.
+func loginReturnPath(v string) string {
+	if !strings.HasPrefix(v, "/") || strings.HasPrefix(v, "//") || strings.Contains(v, "\\") {
+		return strings.Join([]string{config.ServerPath, "/"}, "")
+	}
+	return v
+}
+
+// loginURL returns the URL of the login which returns to the local path afterwards.
+func loginURL(returnTo string) string {
+	if oidcEnabled() {
+		return oidcLoginURL(returnTo)
+	}
+	return fmt.Sprintf("%s/login?return=%s", config.ServerPath, url.QueryEscape(returnTo))
+}
+
+// pollLoginURL returns the URL of the login which returns to the poll stored under key. It is empty if sessions are not enabled.
+func pollLoginURL(key string) string {
+	if !sessionsEnabled() {
+		return ""
+	}
+	return loginURL(strings.Join([]string{"/", key}, ""))
+}
+
+// loginHandle shows the login form and starts a login session for correct user / password combinations.
+// With 'OIDCIssuer', the visitor is sent to the provider instead.
+func loginHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	tl := GetDefaultTranslation()
+
+	if oidcEnabled() {
+		http.Redirect(rw, r, oidcLoginURL(loginReturnPath(r.URL.Query().Get("return"))), http.StatusSeeOther)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		td := loginTemplateStruct{
+			Return:      loginReturnPath(r.URL.Query().Get("return")),
+			User:        sessionUser(r),
+			CSRF:        csrfToken(rw, r),
+			Translation: tl,
+			ServerPath:  config.ServerPath,
+		}
+		buf := bytes.Buffer{}
+		err := loginTemplate.Execute(&buf, td)
+		if err != nil {
+			log.Printf("login: %s", err.Error())
+		}
+		t := textTemplateStruct{template.HTML(buf.String()), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	case http.MethodPost:
+		err := r.ParseForm()
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if !checkCSRF(rw, r) {
+			return
+		}
+
+		user, pw := r.Form.Get("user"), r.Form.Get("pw")
+		correct := false
+		if len(user) != 0 && len(pw) != 0 {
+			correct, err = authenticate(r, user, pw)
+		}
+		if errors.Is(err, ErrLoginLocked) {
+			rw.Header().Set("Retry-After", strconv.Itoa(config.LoginLockoutMinutes*60))
+			rw.WriteHeader(http.StatusTooManyRequests)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.LoginLocked)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+		if !correct {
+			if config.LogFailedLogin {
+				log.Printf("Failed authentication from %s", GetRealIP(r))
+			}
+			rw.WriteHeader(http.StatusForbidden)
+			t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.AuthentificationFailure)), tl, config.ServerPath}
+			textTemplate.Execute(rw, t)
+			return
+		}
+
+		setSessionCookie(rw, user)
+		http.Redirect(rw, r, loginReturnPath(r.Form.Get("return")), http.StatusSeeOther)
+	default:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		t := textTemplateStruct{"405 Method Not Allowed", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+	}
+}
+
+// logoutHandle ends the login session. It only accepts POST requests with the CSRF token, so other sites can not log users out.
+// With OpenID Connect, the session at the provider is kept, so the next login might not ask for the password again.
+func logoutHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	tl := GetDefaultTranslation()
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		t := textTemplateStruct{"405 Method Not Allowed", tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		t := textTemplateStruct{template.HTML(template.HTMLEscapeString(internalErrorText(err))), tl, config.ServerPath}
+		textTemplate.Execute(rw, t)
+		return
+	}
+	if !checkCSRF(rw, r) {
+		return
+	}
+
+	cookie := http.Cookie{}
+	cookie.Name = sessionCookieName
+	cookie.Value = ""
+	cookie.MaxAge = -1
+	cookie.Path = "/"
+	cookie.SameSite = http.SameSiteLaxMode
+	cookie.HttpOnly = true
+	cookie.Secure = !config.InsecureAllowCookiesOverHTTP
+	http.SetCookie(rw, &cookie)
+	http.Redirect(rw, r, loginReturnPath(r.Form.Get("return")), http.StatusSeeOther)
+}
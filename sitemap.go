@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/xml"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// isSitemapPoll returns whether the poll stored under key is part of the sitemap.
+func isSitemapPoll(key string) bool {
+	for i := range config.SitemapPolls {
+		if config.SitemapPolls[i] == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sitemapHandle(rw http.ResponseWriter, r *http.Request) {
+	s := sitemapURLSet{
+		XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		URLs:  make([]sitemapURL, 0, len(config.SitemapPolls)),
+	}
+
+	for i := range config.SitemapPolls {
+		key := config.SitemapPolls[i]
+		c, err := safe.GetPollConfig(key)
+		if err != nil {
+			log.Printf("sitemap: can not load %s: %s", key, err.Error())
+			continue
+		}
+		p, err := LoadPoll(c)
+		if err != nil {
+			log.Printf("sitemap: can not load %s: %s", key, err.Error())
+			continue
+		}
+		if !p.initialised || p.Deleted || p.Hidden {
+			continue
+		}
+
+		u := sitemapURL{Loc: strings.Join([]string{config.SitemapBaseURL, "/", key}, "")}
+		last, err := safe.GetLastActivity(key)
+		if err == nil && !last.IsZero() {
+			u.LastMod = last.UTC().Format(time.RFC3339)
+		}
+		s.URLs = append(s.URLs, u)
+	}
+
+	b, err := xml.Marshal(s)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Printf("sitemap: %s", internalErrorText(err))
+		return
+	}
+	rw.Header().Set("Content-Type", "application/xml")
+	rw.Write([]byte(xml.Header))
+	rw.Write(b)
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// PollStatistics contains aggregated statistics of a single poll.
+type PollStatistics struct {
+	Key           string
+	Questions     []string
+	AnswerOptions []string
+	Participants  int
+	Counts        [][]int // [question][answer option]
+	Points        []float64
+	LastActivity  *time.Time
+}
+
+// GetStatistics aggregates the current results of the poll stored under key.
+// With 'PrecomputeAggregates', the precomputed aggregate is used if it is up to date.
+func (p Poll) GetStatistics(key string) (PollStatistics, error) {
+	if config.PrecomputeAggregates {
+		if s, ok := p.loadAggregate(key); ok {
+			return s, nil
+		}
+		markAggregateDirty(key)
+	}
+	return p.computeStatistics(key)
+}
+
+// computeStatistics aggregates the current results of the poll stored under key from all answers.
+func (p Poll) computeStatistics(key string) (PollStatistics, error) {
+	s := PollStatistics{
+		Key:           key,
+		Questions:     p.Questions,
+		AnswerOptions: make([]string, len(p.AnswerOption)),
+		Counts:        make([][]int, len(p.Questions)),
+		Points:        make([]float64, len(p.Questions)),
+	}
+
+	values := make([]float64, len(p.AnswerOption))
+	for i := range p.AnswerOption {
+		s.AnswerOptions[i] = p.AnswerOption[i][0]
+		f, err := strconv.ParseFloat(p.AnswerOption[i][1], 64)
+		if err != nil {
+			return PollStatistics{}, err
+		}
+		values[i] = f
+	}
+
+	for i := range s.Counts {
+		s.Counts[i] = make([]int, len(p.AnswerOption))
+	}
+
+	r, _, _, _, err := safe.GetPollResult(key)
+	if err != nil {
+		return PollStatistics{}, err
+	}
+	s.Participants = len(r)
+
+	for i := range r {
+		if len(r[i]) != len(p.Questions) {
+			return PollStatistics{}, fmt.Errorf("len(r[%d]) != len(p.Questions)", i)
+		}
+		for q := range r[i] {
+			if !p.validAnswer(r[i][q]) {
+				return PollStatistics{}, fmt.Errorf("answer r[%d][%d] out of range", i, q)
+			}
+			for _, o := range p.selectedOptions(r[i][q]) {
+				s.Counts[q][o]++
+			}
+			s.Points[q] += p.answerPoints(r[i][q], values)
+		}
+	}
+
+	last, err := safe.GetLastActivity(key)
+	if err != nil {
+		return PollStatistics{}, err
+	}
+	if !last.IsZero() {
+		s.LastActivity = &last
+	}
+
+	return s, nil
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// storageUsageCacheTime is the time the storage usage of the data safe is cached.
+const storageUsageCacheTime = time.Minute
+
+var storageUsagePolls int
+var storageUsageBytes int64
+var storageUsageTime time.Time
+var storageUsageMutex sync.Mutex
+
+// instanceFull returns whether the instance wide limits ('MaxPolls', 'MaxStorageBytes') are reached. Uploaded images count towards the storage limit.
+// If newPoll is true, the poll limit is checked as well. A created poll is counted directly so that bursts can't exceed the limit.
+// If the usage can not be determined, the limits are not enforced.
+func instanceFull(newPoll bool) bool {
+	if config.MaxPolls <= 0 && config.MaxStorageBytes <= 0 {
+		return false
+	}
+
+	storageUsageMutex.Lock()
+	defer storageUsageMutex.Unlock()
+
+	if time.Since(storageUsageTime) > storageUsageCacheTime {
+		polls, size, err := safe.GetStorageUsage()
+		if err != nil {
+			log.Printf("storage quota: can not get storage usage: %s", err.Error())
+			return false
+		}
+		uploads, err := uploadStorageUsage()
+		if err != nil {
+			log.Printf("storage quota: can not get upload usage: %s", err.Error())
+			return false
+		}
+		size += uploads
+		storageUsagePolls, storageUsageBytes, storageUsageTime = polls, size, time.Now()
+	}
+
+	if config.MaxStorageBytes > 0 && storageUsageBytes >= config.MaxStorageBytes {
+		return true
+	}
+	if newPoll && config.MaxPolls > 0 {
+		if storageUsagePolls >= config.MaxPolls {
+			return true
+		}
+		storageUsagePolls++
+	}
+	return false
+}
+
+// uploadStorageUsage returns the size of all files in the upload directory in bytes.
+func uploadStorageUsage() (int64, error) {
+	if config.UploadPath == "" {
+		return 0, nil
+	}
+	var size int64
+	err := filepath.WalkDir(config.UploadPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// writeInstanceFull writes the "instance full" page.
+func writeInstanceFull(rw http.ResponseWriter) {
+	rw.WriteHeader(http.StatusInsufficientStorage)
+	tl := GetDefaultTranslation()
+	t := textTemplateStruct{template.HTML(template.HTMLEscapeString(tl.InstanceFull)), tl, config.ServerPath}
+	textTemplate.Execute(rw, t)
+}
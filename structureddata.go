@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// Types for schema.org structured data (JSON-LD). Only aggregated results are included, never names or comments of participants.
+
+type structuredPoll struct {
+	Context              string                   `json:"@context"`
+	Type                 string                   `json:"@type"`
+	Name                 string                   `json:"name"`
+	URL                  string                   `json:"url,omitempty"`
+	Description          string                   `json:"description,omitempty"`
+	InLanguage           string                   `json:"inLanguage,omitempty"`
+	DateModified         string                   `json:"dateModified,omitempty"`
+	Expires              string                   `json:"expires,omitempty"`
+	InteractionStatistic structuredInteraction    `json:"interactionStatistic"`
+	HasPart              []structuredPollQuestion `json:"hasPart"`
+}
+
+type structuredInteraction struct {
+	Type                 string `json:"@type"`
+	InteractionType      string `json:"interactionType"`
+	UserInteractionCount int    `json:"userInteractionCount"`
+}
+
+type structuredPollQuestion struct {
+	Type            string             `json:"@type"`
+	Name            string             `json:"name"`
+	AnswerCount     int                `json:"answerCount"`
+	SuggestedAnswer []structuredAnswer `json:"suggestedAnswer"`
+}
+
+type structuredAnswer struct {
+	Type        string `json:"@type"`
+	Text        string `json:"text"`
+	UpvoteCount int    `json:"upvoteCount"`
+}
+
+// structuredData returns the poll and its aggregated results as schema.org JSON-LD.
+// The result can be safely embedded in a script element of type 'application/ld+json'.
+func (p Poll) structuredData(key string, s PollStatistics, language string) (template.JS, error) {
+	d := structuredPoll{
+		Context:     "https://schema.org",
+		Type:        "CreativeWork",
+		Name:        key,
+		Description: p.Description,
+		InLanguage:  language,
+		InteractionStatistic: structuredInteraction{
+			Type:                 "InteractionCounter",
+			InteractionType:      "https://schema.org/VoteAction",
+			UserInteractionCount: s.Participants,
+		},
+		HasPart: make([]structuredPollQuestion, len(s.Questions)),
+	}
+	if config.PublicURL != "" {
+		d.URL = strings.Join([]string{config.PublicURL, "/", key}, "")
+	}
+	if s.LastActivity != nil {
+		d.DateModified = s.LastActivity.Format(time.RFC3339)
+	}
+	if !p.Expiry.IsZero() {
+		d.Expires = p.Expiry.Format(time.RFC3339)
+	}
+
+	for q := range s.Questions {
+		d.HasPart[q] = structuredPollQuestion{
+			Type:            "Question",
+			Name:            s.Questions[q],
+			AnswerCount:     s.Participants,
+			SuggestedAnswer: make([]structuredAnswer, len(s.AnswerOptions)),
+		}
+		for o := range s.AnswerOptions {
+			d.HasPart[q].SuggestedAnswer[o] = structuredAnswer{Type: "Answer", Text: s.AnswerOptions[o], UpvoteCount: s.Counts[q][o]}
+		}
+	}
+
+	// json.Marshal escapes '<', '>' and '&', so the data can not close the surrounding script element
+	b, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	return template.JS(b), nil
+}
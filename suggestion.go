@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sort"
+)
+
+// dateSuggestionCount is the maximum number of suggested dates.
+const dateSuggestionCount = 3
+
+// Indices of the answer options of date polls.
+const (
+	dateAnswerYes = iota
+	dateAnswerIfNeeded
+	dateAnswerNo
+)
+
+type dateSuggestion struct {
+	Question string
+	Yes      int
+	IfNeeded int
+	No       int
+	score    float64
+}
+
+// suggestDates ranks the questions of a date poll by the configured weights of yes, if needed and no answers.
+// Questions nobody can attend are never suggested.
+func (p Poll) suggestDates(results [][]int) []dateSuggestion {
+	s := make([]dateSuggestion, len(p.Questions))
+	for q := range p.Questions {
+		s[q].Question = p.Questions[q]
+	}
+
+	for i := range results {
+		for q := range results[i] {
+			if q >= len(s) {
+				continue
+			}
+			switch results[i][q] {
+			case dateAnswerYes:
+				s[q].Yes++
+			case dateAnswerIfNeeded:
+				s[q].IfNeeded++
+			case dateAnswerNo:
+				s[q].No++
+			}
+		}
+	}
+
+	suggestions := make([]dateSuggestion, 0, len(s))
+	for q := range s {
+		if s[q].Yes+s[q].IfNeeded == 0 {
+			continue
+		}
+		s[q].score = float64(s[q].Yes)*config.SuggestionWeightYes + float64(s[q].IfNeeded)*config.SuggestionWeightIfNeeded - float64(s[q].No)*config.SuggestionWeightNo
+		suggestions = append(suggestions, s[q])
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].score > suggestions[j].score })
+	return suggestions[:min(dateSuggestionCount, len(suggestions))]
+}
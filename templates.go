@@ -24,16 +24,69 @@ import (
 var templateFiles embed.FS
 var textTemplate *template.Template
 
+// extraHead and extraFooter hold additional HTML for all pages (see PathExtraHead and PathExtraFooter).
+// They must only be written to during server initialisation.
+var extraHead, extraFooter template.HTML
+
 type textTemplateStruct struct {
 	Text        template.HTML
 	Translation Translation
 	ServerPath  string
 }
 
+// templateFunctions holds the instance wide values (like branding) which are available in all templates.
+// They are evaluated on execution, so the configuration can be loaded after the templates are parsed.
+var templateFunctions = template.FuncMap{
+	"instanceName": func() string {
+		return config.InstanceName
+	},
+	"customLogo": func() bool {
+		return config.LogoURL != ""
+	},
+	"logoURL": func() string {
+		if config.LogoURL != "" {
+			return config.LogoURL
+		}
+		return assetURL("static/Logo.svg")
+	},
+	"faviconURL": func() string {
+		return config.FaviconURL
+	},
+	"asset": assetURL,
+	"singleSignOn": func() bool {
+		return oidcEnabled()
+	},
+	"consentText": func() string {
+		if config.ConsentText != "" {
+			return config.ConsentText
+		}
+		return GetDefaultTranslation().AcceptPrivacyPolicy
+	},
+	"consentURL": func() string {
+		return config.ConsentURL
+	},
+	"degraded": func() bool {
+		return dataSafeUnhealthy.Load()
+	},
+	"demoMode": func() bool {
+		return config.DemoMode
+	},
+	"demoPollLifetime": func() int {
+		return config.DemoPollLifetimeHours
+	},
+	"reportsEnabled": reportsEnabled,
+	"extraHead": func() template.HTML {
+		return extraHead
+	},
+	"extraFooter": func() template.HTML {
+		return extraFooter
+	},
+}
+
 func init() {
 	var err error
 
-	textTemplate, err = template.ParseFS(templateFiles, "template/text.html")
+	textTemplate, err = template.New("text.html").Funcs(templateFunctions).ParseFS(templateFiles, "template/text.html")
 	if err != nil {
 		panic(err)
 	}
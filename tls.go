@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/tls"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// tlsReloadInterval is the minimum time between two checks whether the certificate files changed.
+const tlsReloadInterval = time.Minute
+
+// certificateReloader serves the certificate in TLSCertFile / TLSKeyFile.
+// Changed files (e.g. renewed certificates) are loaded without restarting the server.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mutex   sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+// newCertificateReloader returns a certificateReloader for the given files. It fails if the files can not be loaded.
+func newCertificateReloader(certFile, keyFile string) (*certificateReloader, error) {
+	c := &certificateReloader{certFile: certFile, keyFile: keyFile}
+	err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.checked = time.Now()
+	return c, nil
+}
+
+// lastModified returns the time the certificate or key file was last changed.
+func (c *certificateReloader) lastModified() (time.Time, error) {
+	var last time.Time
+	for _, f := range []string{c.certFile, c.keyFile} {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(last) {
+			last = fi.ModTime()
+		}
+	}
+	return last, nil
+}
+
+// load loads the certificate from the files. The mutex must be held if the reloader is in use.
+func (c *certificateReloader) load() error {
+	modTime, err := c.lastModified()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+	c.cert = &cert
+	c.modTime = modTime
+	return nil
+}
+
+// GetCertificate returns the current certificate. It can be used as tls.Config.GetCertificate.
+// If the files changed but can not be loaded (e.g. while they are written), the previous certificate is kept.
+func (c *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if time.Since(c.checked) >= tlsReloadInterval {
+		c.checked = time.Now()
+		modTime, err := c.lastModified()
+		if err == nil && !modTime.Equal(c.modTime) {
+			err = c.load()
+			if err == nil {
+				log.Println("tls: reloaded certificate")
+			}
+		}
+		if err != nil {
+			log.Println("tls: can not reload certificate:", err)
+		}
+	}
+	return c.cert, nil
+}
+
+// serverTLSConfig returns the TLS configuration of the server and the handler for plain HTTP requests on 'TLSRedirectAddress'.
+// Both are nil if the server does not terminate TLS.
+func serverTLSConfig() (*tls.Config, http.Handler, error) {
+	if len(config.TLSAutocertDomains) != 0 {
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(config.TLSAutocertDomains...),
+			Cache:      autocert.DirCache(config.TLSAutocertCacheDir),
+			Email:      config.TLSAutocertEmail,
+		}
+		// The handler answers HTTP challenges and redirects all other requests
+		return m.TLSConfig(), m.HTTPHandler(nil), nil
+	}
+
+	if config.TLSCertFile != "" {
+		c, err := newCertificateReloader(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		t := &tls.Config{
+			GetCertificate: c.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		}
+		return t, http.HandlerFunc(redirectHTTPS), nil
+	}
+
+	return nil, nil, nil
+}
+
+// redirectHTTPS redirects the request to the same URL using HTTPS on the port of 'Address'.
+func redirectHTTPS(rw http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if _, port, err := net.SplitHostPort(config.Address); err == nil && port != "443" && port != "" {
+		host = net.JoinHostPort(host, port)
+	}
+	http.Redirect(rw, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+}
@@ -27,78 +27,288 @@ import (
 
 // Translation represents an object holding all translations
 type Translation struct {
-	Language                   string
-	WeekdayMonday              string
-	WeekdayTuesday             string
-	WeekdayWednesday           string
-	WeekdayThursday            string
-	WeekdayFriday              string
-	WeekdaySaturday            string
-	WeekdaySunday              string
-	DateYes                    string
-	DateNo                     string
-	DateOnlyIfNeeded           string
-	DateCanNotSay              string
-	Name                       string
-	Optional                   string
-	Points                     string
-	Submit                     string
-	CreatedBy                  string
-	Impressum                  string
-	PrivacyPolicy              string
-	NewPoll                    string
-	NormalPoll                 string
-	AppointmentPoll            string
-	Question                   string
-	AnswerOption               string
-	Value                      string
-	Colour                     string
-	Description                string
-	AddOption                  string
-	Yes                        string
-	No                         string
-	Username                   string
-	Password                   string
-	AcceptPrivacyPolicy        string
-	CreatePoll                 string
-	Time                       string
-	StartDate                  string
-	EndDate                    string
-	NoTime                     string
-	AddTime                    string
-	Participate                string
-	SelectPollKind             string
-	Results                    string
-	PollToLargeError           string
-	PollNoOptions              string
-	DeletePoll                 string
-	PollIsDeleted              string
-	Starred                    string
-	LoadConfiguration          string
-	Configuration              string
-	MoreOptions                string
-	ExportConfiguration        string
-	Comment                    string
-	Unknown                    string
-	SelectAll                  string
-	FunctionRequiresJavaScript string
-	UserNotCreator             string
-	CreateNewPollRandom        string
-	PleaseWait                 string
-	AuthentificationFailure    string
-	ErrorOccured               string
-	OpinionPoll                string
-	OpinionItem                string
-	AddOpinionItem             string
-	OpinionGood                string
-	OpinionRatherGood          string
-	OpinionNeutral             string
-	OpinionRatherBad           string
-	OpinionBad                 string
-	InvalidKey                 string
-	EditAnswer                 string
-	DeleteAnswer               string
-	RememberedAs               string
+	Language                       string
+	WeekdayMonday                  string
+	WeekdayTuesday                 string
+	WeekdayWednesday               string
+	WeekdayThursday                string
+	WeekdayFriday                  string
+	WeekdaySaturday                string
+	WeekdaySunday                  string
+	DateYes                        string
+	DateNo                         string
+	DateOnlyIfNeeded               string
+	DateCanNotSay                  string
+	Name                           string
+	Optional                       string
+	Points                         string
+	Submit                         string
+	CreatedBy                      string
+	Impressum                      string
+	PrivacyPolicy                  string
+	NewPoll                        string
+	NormalPoll                     string
+	AppointmentPoll                string
+	Question                       string
+	AnswerOption                   string
+	Value                          string
+	Colour                         string
+	Description                    string
+	AddOption                      string
+	Yes                            string
+	No                             string
+	Username                       string
+	Password                       string
+	AcceptPrivacyPolicy            string
+	CreatePoll                     string
+	Time                           string
+	StartDate                      string
+	EndDate                        string
+	NoTime                         string
+	AddTime                        string
+	Participate                    string
+	SelectPollKind                 string
+	Results                        string
+	PollToLargeError               string
+	PollNoOptions                  string
+	DeletePoll                     string
+	PollIsDeleted                  string
+	Starred                        string
+	LoadConfiguration              string
+	Configuration                  string
+	MoreOptions                    string
+	ExportConfiguration            string
+	Comment                        string
+	Unknown                        string
+	SelectAll                      string
+	FunctionRequiresJavaScript     string
+	UserNotCreator                 string
+	CreateNewPollRandom            string
+	PleaseWait                     string
+	AuthentificationFailure        string
+	ErrorOccured                   string
+	OpinionPoll                    string
+	OpinionItem                    string
+	AddOpinionItem                 string
+	OpinionGood                    string
+	OpinionRatherGood              string
+	OpinionNeutral                 string
+	OpinionRatherBad               string
+	OpinionBad                     string
+	InvalidKey                     string
+	EditAnswer                     string
+	DeleteAnswer                   string
+	RememberedAs                   string
+	MyPolls                        string
+	Poll                           string
+	Answers                        string
+	Login                          string
+	NoPollsFound                   string
+	PresenceOthersFilling          string
+	PollNotFound                   string
+	CreatePollHere                 string
+	ImportCSV                      string
+	ImportCSVDescription           string
+	InvalidCSV                     string
+	Discussion                     string
+	NoDiscussionEntries            string
+	DiscussionEntry                string
+	ModerateDiscussion             string
+	DeleteDiscussionEntry          string
+	DiscussionFull                 string
+	Endorsements                   string
+	EndorseAnswer                  string
+	DisableComments                string
+	NameAlreadyTaken               string
+	BackToPoll                     string
+	UniqueNames                    string
+	ShuffleOrder                   string
+	CalendarWeek                   string
+	GroupByWeek                    string
+	Page                           string
+	NextPage                       string
+	PreviousPage                   string
+	UploadImage                    string
+	Preview                        string
+	AllWeekdays                    string
+	TimeWeekday                    string
+	Deadline                       string
+	PollClosed                     string
+	TimeRemaining                  string
+	Days                           string
+	Dashboard                      string
+	ResponsesOverTime              string
+	UnknownCreationTime            string
+	Date                           string
+	NewAnswers                     string
+	NoData                         string
+	EditedAnswers                  string
+	Created                        string
+	Modified                       string
+	TransposedView                 string
+	NormalView                     string
+	Mean                           string
+	Median                         string
+	ShowPercentages                string
+	SuggestedDates                 string
+	IfNeededWeight                 string
+	Participant                    string
+	CustomConsent                  string
+	CustomConsentDescription       string
+	ConsentText                    string
+	ConsentURL                     string
+	ExportMyData                   string
+	ExportPersonalData             string
+	LoginLocked                    string
+	Register                       string
+	EmailAddress                   string
+	RepeatPassword                 string
+	InvalidToken                   string
+	UserExists                     string
+	RegistrationComplete           string
+	InvalidEmailAddress            string
+	PasswordsDoNotMatch            string
+	RegistrationMailSent           string
+	RegistrationMailText           string
+	ForgotPassword                 string
+	ResetPassword                  string
+	PasswordResetMailSent          string
+	PasswordChanged                string
+	PasswordResetMailText          string
+	PollQuotaExceeded              string
+	InstanceFull                   string
+	PollChanged                    string
+	StorageDegraded                string
+	DemoModeBanner                 string
+	ContentFiltered                string
+	Moderation                     string
+	Reports                        string
+	PollHiddenState                string
+	ModerationHide                 string
+	ModerationDismiss              string
+	ModerationDelete               string
+	NoReports                      string
+	ReportReceived                 string
+	PollHidden                     string
+	ReportReason                   string
+	ReportPoll                     string
+	PollLocked                     string
+	PollLockedState                string
+	ModerationLock                 string
+	ModerationOtherPoll            string
+	Invitations                    string
+	InvitationsResponded           string
+	InvitationLink                 string
+	InvitationStatus               string
+	InvitationAnswered             string
+	InvitationPending              string
+	NoInvitations                  string
+	TooManyInvitations             string
+	InvitationNames                string
+	ManageInvitations              string
+	InvitationInvalid              string
+	InvitationAlreadyUsed          string
+	Anonymous                      string
+	AnonymousNotice                string
+	ReceiptIntro                   string
+	ReceiptVerify                  string
+	ReceiptCode                    string
+	ReceiptInvalid                 string
+	FollowUpDays                   string
+	FollowUpInvalid                string
+	FollowUpScheduled              string
+	FollowUpPoll                   string
+	FollowUpCreated                string
+	FollowUpMailText               string
+	CalendarInvitationDescription  string
+	CalendarInvitationSubject      string
+	CalendarInvitationMailText     string
+	CalendarInvitationsSent        string
+	FinalDate                      string
+	ChooseFinalDate                string
+	NoFinalDate                    string
+	SendCalendarInvitations        string
+	AnswerMail                     string
+	ExportPollWithAnswers          string
+	PollClosedByCreator            string
+	ClosePoll                      string
+	ReopenPoll                     string
+	Expiry                         string
+	PollExpired                    string
+	CalendarExportFinal            string
+	CalendarExportCandidates       string
+	CSRFInvalid                    string
+	RateLimited                    string
+	ResultsSummarised              string
+	ResultVisibility               string
+	ResultsVisibleAll              string
+	ResultsVisibleClosed           string
+	ResultsVisibleTotals           string
+	ResultsHiddenUntilClosed       string
+	AnswersHiddenTotals            string
+	MultipleChoice                 string
+	Duplicates                     string
+	DuplicatesDescription          string
+	DuplicatesKeep                 string
+	DuplicatesModified             string
+	DuplicatesMerge                string
+	DuplicatesMergeDescription     string
+	DuplicatesDelete               string
+	DuplicatesDeleteDescription    string
+	DuplicatesInvalid              string
+	NoDuplicates                   string
+	Capacity                       string
+	SlotFull                       string
+	CapacityExceeded               string
+	ComparePolls                   string
+	ComparePollKey                 string
+	CompareDifference              string
+	CompareDifferentQuestions      string
+	InternalError                  string
+	ActivityLog                    string
+	ActivityLogEvent               string
+	ActivityLogAnswer              string
+	ActivityLogEmpty               string
+	ActivityLogCreated             string
+	ActivityLogAnswered            string
+	ActivityLogEdited              string
+	ActivityLogDeleted             string
+	ActivityLogClosed              string
+	ActivityLogReopened            string
+	SSOLogin                       string
+	SSOLoggedInAs                  string
+	SSOLogout                      string
+	SSOLoginFailed                 string
+	AnswerLayout                   string
+	AnswerLayoutAuto               string
+	AnswerLayoutGrid               string
+	AnswerLayoutCompact            string
+	PrefillInvalid                 string
+	AccessStatistics               string
+	PageViews                      string
+	AnswerFormOpens                string
+	UniqueAnswerFormOpens          string
+	AccessStatisticsNote           string
+	SortBy                         string
+	SortStorageOrder               string
+	SortSubmissionTime             string
+	SortScore                      string
+	ActivityLogPollEdited          string
+	EditPoll                       string
+	EditPollDescription            string
+	EditPollInvalidated            string
+	EditPollConfirm                string
+	EditPollInvalid                string
+	EditPollQuestions              string
+	EditPollAnswerOptions          string
+	EditPollRemove                 string
+	EditPollSave                   string
+	CoOrganizers                   string
+	CoOrganizersDescription        string
+	CoOrganizerNames               string
+	CoOrganizersSave               string
+	CoOrganizersInvalid            string
+	CalendarInvitationsAlreadySent string
 }
 
 const defaultLanguage = "en"
@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Uploads are stored per poll in a directory of 'UploadPath' named after the hash of the poll key.
+// The key itself is stored in the directory (uploadKeyFile), so the gc can remove the uploads together with the poll.
+// Uploads created before this were stored directly in 'UploadPath'. They are still served, but not removed by the gc.
+
+// uploadKeyFile is the name of the file containing the poll key in the upload directory of a poll.
+const uploadKeyFile = ".key"
+
+// uploadOrphanTime is the time uploads for polls which were never created are kept.
+const uploadOrphanTime = 24 * time.Hour
+
+// uploadCSRFHeader is the header which must contain the CSRF token for uploads.
+const uploadCSRFHeader = "X-CSRF-Token"
+
+// uploadImageTypes maps the allowed content types of uploaded images to their file extension.
+var uploadImageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Errors of saveUpload caused by the uploaded file. All other errors are internal errors.
+var (
+	errUploadTooLarge    = errors.New("upload: file too large")
+	errUploadUnsupported = errors.New("upload: unsupported file type")
+)
+
+// uploadNameRegexp matches the names of uploaded images, optionally inside the upload directory of a poll.
+var uploadNameRegexp = regexp.MustCompile(`^([0-9a-f]{64}/)?[0-9a-f]{64}\.(png|jpg|gif|webp)$`)
+
+// uploadDirRegexp matches the upload directories of polls.
+var uploadDirRegexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// allowUploadedImages allows images in formatted text, as long as they are uploaded to this instance.
+// This prevents tracking of participants through external images.
+func allowUploadedImages() {
+	prefix := regexp.QuoteMeta(strings.Join([]string{config.ServerPath, "/uploads/"}, ""))
+	policy.AllowAttrs("src").Matching(regexp.MustCompile(`^` + prefix + `([0-9a-f]{64}/)?[0-9a-f]{64}\.(png|jpg|gif|webp)$`)).OnElements("img")
+	policy.AllowAttrs("alt", "title").OnElements("img")
+}
+
+// uploadDir returns the name of the upload directory of the poll stored under key.
+func uploadDir(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// saveUpload stores an uploaded image in the upload directory of the poll stored under key and returns its name relative to 'UploadPath'.
+// The name is derived from the content, so uploading the same image twice results in a single file.
+func saveUpload(key string, r io.Reader) (string, error) {
+	b, err := io.ReadAll(io.LimitReader(r, config.MaxUploadSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(b)) > config.MaxUploadSize {
+		return "", errUploadTooLarge
+	}
+
+	ext, ok := uploadImageTypes[http.DetectContentType(b)]
+	if !ok {
+		return "", errUploadUnsupported
+	}
+
+	dir := uploadDir(key)
+	err = os.MkdirAll(filepath.Join(config.UploadPath, dir), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	keyPath := filepath.Join(config.UploadPath, dir, uploadKeyFile)
+	if _, err := os.Stat(keyPath); err != nil {
+		err = os.WriteFile(keyPath, []byte(key), 0644)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	hash := sha256.Sum256(b)
+	name := strings.Join([]string{dir, "/", hex.EncodeToString(hash[:]), ext}, "")
+	path := filepath.Join(config.UploadPath, filepath.FromSlash(name))
+	if _, err := os.Stat(path); err == nil {
+		return name, nil
+	}
+
+	// Write to a temporary file first so no partial images are served
+	f, err := os.CreateTemp(filepath.Join(config.UploadPath, dir), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(b)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	err = f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	err = os.Rename(f.Name(), path)
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return name, nil
+}
+
+// uploadHandle accepts image uploads for the poll given in 'poll' (the path of the poll) and returns the URL of the uploaded image.
+// The request must contain the CSRF token in the X-CSRF-Token header. Without login session, the credentials are sent through HTTP basic authentication.
+// Authentication is checked before the upload is read. For existing polls, the user must be allowed to manage the poll.
+func uploadHandle(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+
+	if r.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !validCSRFToken(r, r.Header.Get(uploadCSRFHeader)) {
+		if config.LogFailedLogin {
+			log.Printf("Invalid CSRF token from %s", GetRealIP(r))
+		}
+		rw.WriteHeader(http.StatusForbidden)
+		rw.Write([]byte(GetDefaultTranslation().CSRFInvalid))
+		return
+	}
+
+	// Same as in rootHandle
+	path := r.URL.Query().Get("poll")
+	if !strings.HasPrefix(path, strings.Join([]string{config.ServerPath, "/"}, "")) || strings.ContainsRune(strings.TrimLeft(strings.TrimPrefix(path, config.ServerPath), "/"), '/') {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(GetDefaultTranslation().InvalidKey))
+		return
+	}
+	key := strings.TrimLeft(path, "/")
+	if key == strings.TrimLeft(config.ServerPath, "/") {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(GetDefaultTranslation().InvalidKey))
+		return
+	}
+
+	c, err := safe.GetPollConfig(key)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(internalErrorText(err)))
+		return
+	}
+	p, err := LoadPoll(c)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(internalErrorText(err)))
+		return
+	}
+	if p.Deleted {
+		rw.WriteHeader(http.StatusGone)
+		rw.Write([]byte(GetDefaultTranslation().PollIsDeleted))
+		return
+	}
+
+	// The body is not parsed yet, so the credentials are taken from the HTTP basic authentication
+	r.Form = make(map[string][]string)
+	if user, pw, ok := r.BasicAuth(); ok {
+		r.Form.Set("user", user)
+		r.Form.Set("pw", pw)
+	}
+
+	// Uploading requires the same authentication as creating a poll
+	if config.AuthenticationEnabled && !p.initialised {
+		_, correct, err := authenticateRequest(r)
+		if errors.Is(err, ErrLoginLocked) {
+			rw.Header().Set("Retry-After", strconv.Itoa(config.LoginLockoutMinutes*60))
+			rw.WriteHeader(http.StatusTooManyRequests)
+			rw.Write([]byte(GetDefaultTranslation().LoginLocked))
+			return
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			rw.Write([]byte(internalErrorText(err)))
+			return
+		}
+		if !correct {
+			if config.LogFailedLogin {
+				log.Printf("Failed authentication from %s", GetRealIP(r))
+			}
+			rw.WriteHeader(http.StatusForbidden)
+			rw.Write([]byte(GetDefaultTranslation().AuthentificationFailure))
+			return
+		}
+	}
+	if p.initialised && !checkCreator(rw, r, key, true) {
+		return
+	}
+
+	if instanceFull(false) {
+		rw.WriteHeader(http.StatusInsufficientStorage)
+		rw.Write([]byte(GetDefaultTranslation().InstanceFull))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(rw, r.Body, config.MaxUploadSize+1000000) // Allow for some overhead of the form
+	err = r.ParseMultipartForm(config.MaxUploadSize)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	f, _, err := r.FormFile("image")
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+	defer f.Close()
+
+	name, err := saveUpload(key, f)
+	if errors.Is(err, errUploadTooLarge) || errors.Is(err, errUploadUnsupported) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(internalErrorText(err)))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	rw.Write([]byte(strings.Join([]string{config.ServerPath, "/uploads/", name}, "")))
+}
+
+// gcUploads removes the uploads of polls which no longer exist or are marked as deleted.
+// Uploads for polls which were not created (yet) are kept for uploadOrphanTime after the last upload.
+func gcUploads() error {
+	entries, err := os.ReadDir(config.UploadPath)
+	if err != nil {
+		return err
+	}
+	var removed int
+	for _, e := range entries {
+		if !e.IsDir() || !uploadDirRegexp.MatchString(e.Name()) {
+			continue
+		}
+		dir := filepath.Join(config.UploadPath, e.Name())
+		key, err := os.ReadFile(filepath.Join(dir, uploadKeyFile))
+		if err != nil {
+			log.Printf("upload gc: can not read key of %s: %s", e.Name(), err.Error())
+			continue
+		}
+		c, err := safe.GetPollConfig(string(key))
+		if err != nil {
+			return err
+		}
+		p, err := LoadPoll(c)
+		if err != nil {
+			return err
+		}
+		if !p.initialised {
+			info, err := e.Info()
+			if err != nil {
+				return err
+			}
+			if time.Since(info.ModTime()) < uploadOrphanTime {
+				continue
+			}
+		} else if !p.Deleted {
+			continue
+		}
+		err = os.RemoveAll(dir)
+		if err != nil {
+			return err
+		}
+		removed++
+	}
+	log.Printf("upload gc: removed uploads of %d polls", removed)
+	return nil
+}
+
+// uploadedImageHandle serves uploaded images.
+func uploadedImageHandle(rw http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, strings.Join([]string{config.ServerPath, "/uploads/"}, ""))
+	if !uploadNameRegexp.MatchString(name) {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	b, err := os.ReadFile(filepath.Join(config.UploadPath, filepath.FromSlash(name)))
+	if err != nil {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	rw.Header().Set("Content-Type", http.DetectContentType(b))
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	rw.Header().Set("Cache-Control", "public, max-age=31536000, immutable") // Name depends on the content
+	rw.Write(b)
+}
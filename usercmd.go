@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2026 Marcus Soll
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	auth "github.com/Top-Ranger/pollgo/authenticater"
+)
+
+const userCommandUsage = `usage: pollgo user [-config path] [-file path] add|remove|passwd USER
+
+Manages the users of the Htpasswd authenticater. The password is read from the first line of stdin.
+If -file is not given, 'AuthenticaterConfig' of the configuration is used.
+`
+
+// userCommand runs the "user" subcommand with the remaining arguments and returns the exit code.
+func userCommand(args []string, stdin io.Reader, stdout io.Writer) int {
+	fs := flag.NewFlagSet("user", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	fs.Usage = func() { fmt.Fprint(stdout, userCommandUsage) }
+	configPath := fs.String("config", "./config.json", "Path to json config for PollGo!")
+	file := fs.String("file", "", "Path to the htpasswd file")
+	err := fs.Parse(args)
+	if err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	command, user := fs.Arg(0), fs.Arg(1)
+	if user == "" || strings.ContainsAny(user, ":\n\r") || strings.HasPrefix(user, "#") {
+		fmt.Fprintln(stdout, "invalid user name")
+		return 2
+	}
+
+	if *file == "" {
+		c, err := loadConfig(*configPath)
+		if err != nil {
+			fmt.Fprintln(stdout, err)
+			return 1
+		}
+		if c.Authenticater != "Htpasswd" {
+			fmt.Fprintf(stdout, "authenticater is %s, but user management is only available for Htpasswd\n", c.Authenticater)
+			return 1
+		}
+		*file = c.AuthenticaterConfig
+	}
+
+	switch command {
+	case "add", "passwd":
+		fmt.Fprint(stdout, "Password: ")
+		pw, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && pw != "") {
+			fmt.Fprintln(stdout)
+			fmt.Fprintln(stdout, "can not read password:", err)
+			return 1
+		}
+		fmt.Fprintln(stdout)
+		pw = strings.TrimRight(pw, "\r\n")
+		if pw == "" {
+			fmt.Fprintln(stdout, "password must not be empty")
+			return 1
+		}
+		hash, err := auth.HashPassword(pw)
+		if err != nil {
+			fmt.Fprintln(stdout, err)
+			return 1
+		}
+		err = updateHtpasswd(*file, user, hash, command == "add")
+		if err != nil {
+			fmt.Fprintln(stdout, err)
+			return 1
+		}
+	case "remove":
+		err = updateHtpasswd(*file, user, "", false)
+		if err != nil {
+			fmt.Fprintln(stdout, err)
+			return 1
+		}
+	default:
+		fs.Usage()
+		return 2
+	}
+	fmt.Fprintf(stdout, "%s: %s done\n", user, command)
+	return 0
+}
+
+// updateHtpasswd sets the hash of the user in the htpasswd file. An empty hash removes the user.
+// If add is true, the user must not exist, otherwise the user must exist.
+// All other lines are kept. The file is replaced atomically.
+func updateHtpasswd(path, user, hash string, add bool) error {
+	b, err := os.ReadFile(path)
+	if err != nil && !(add && errors.Is(err, os.ErrNotExist)) {
+		return err
+	}
+	_, err = auth.ParseHtpasswd(b)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	var out bytes.Buffer
+	found := false
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		l := s.Text()
+		u, _, ok := strings.Cut(strings.TrimSpace(l), ":")
+		if ok && u == user {
+			found = true
+			if hash != "" {
+				fmt.Fprintf(&out, "%s:%s\n", user, hash)
+			}
+			continue
+		}
+		out.WriteString(l)
+		out.WriteByte('\n')
+	}
+	if s.Err() != nil {
+		return s.Err()
+	}
+
+	switch {
+	case add && found:
+		return fmt.Errorf("user %s already exists", user)
+	case !add && !found:
+		return fmt.Errorf("user %s does not exist", user)
+	case add:
+		fmt.Fprintf(&out, "%s:%s\n", user, hash)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".htpasswd-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if info, err := os.Stat(path); err == nil {
+		err = f.Chmod(info.Mode().Perm())
+		if err != nil {
+			f.Close()
+			return err
+		}
+	}
+	_, err = f.Write(out.Bytes())
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}